@@ -0,0 +1,49 @@
+package editorfunc
+
+import "time"
+
+// AutoSaveInterval is the idle time after which a changed buffer is written to its file.
+// Zero disables auto-save. It is read when an editor is created.
+var AutoSaveInterval time.Duration
+
+// markDirty flags the buffer as changed and restarts the idle timer
+func (e *LuaEditor) markDirty() {
+	e.dirty = true
+	e.editGen++
+	if e.autoSaveInterval <= 0 {
+		return
+	}
+	if e.autoSaveTimer != nil {
+		e.autoSaveTimer.Stop()
+	}
+	gen := e.editGen
+	e.autoSaveTimer = time.AfterFunc(e.autoSaveInterval, func() {
+		if e.app == nil {
+			return
+		}
+		e.app.QueueUpdateDraw(func() {
+			e.autoSave(gen)
+		})
+	})
+}
+
+// shouldAutoSave reports whether the timer started by edit gen has to save the buffer.
+// A later edit starts a new timer and a manual save clears the dirty flag.
+func (e *LuaEditor) shouldAutoSave(gen int) bool {
+	return e.autoSaveInterval > 0 && e.dirty && e.fileName != "" && gen == e.editGen
+}
+
+// autoSave writes the buffer if it is still dirty and idle since the edit gen
+func (e *LuaEditor) autoSave(gen int) {
+	if !e.shouldAutoSave(gen) {
+		return
+	}
+	if e.SaveFile() == nil {
+		e.SetStatus("Auto-saved " + e.fileName)
+	}
+}
+
+// IsDirty reports whether the buffer has changes that are not saved
+func (e *LuaEditor) IsDirty() bool {
+	return e.dirty
+}
@@ -0,0 +1,69 @@
+package editorfunc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newAutoSaveEditor creates an editor of a file with auto-save after an hour, so only the test saves
+func newAutoSaveEditor(t *testing.T) (*LuaEditor, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "script.lua")
+	if err := os.WriteFile(path, []byte("x = 1"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	prev := AutoSaveInterval
+	AutoSaveInterval = time.Hour
+	t.Cleanup(func() { AutoSaveInterval = prev })
+	e := NewLuaEditor(nil, "", path, nil)
+	t.Cleanup(func() {
+		if e.autoSaveTimer != nil {
+			e.autoSaveTimer.Stop()
+		}
+	})
+	return e, path
+}
+
+func TestAutoSaveWritesADirtyBuffer(t *testing.T) {
+	e, path := newAutoSaveEditor(t)
+	e.content = []string{"x = 2"}
+	e.markDirty()
+	e.autoSave(e.editGen)
+	if e.IsDirty() {
+		t.Error("the buffer is still dirty after the auto-save")
+	}
+	if data, _ := os.ReadFile(path); string(data) != string(e.fileContent()) {
+		t.Errorf("the file holds %q, want the buffer %q", data, e.fileContent())
+	}
+}
+
+func TestAutoSaveSkipsACleanOrChangedBuffer(t *testing.T) {
+	e, path := newAutoSaveEditor(t)
+	e.autoSave(e.editGen)
+	if data, _ := os.ReadFile(path); string(data) != "x = 1" {
+		t.Errorf("a clean buffer was saved: %q", data)
+	}
+
+	e.content = []string{"x = 2"}
+	e.markDirty()
+	gen := e.editGen
+	e.markDirty() // Edited again before the first timer fired
+	if e.shouldAutoSave(gen) {
+		t.Error("the timer of an earlier edit saves the buffer")
+	}
+	if !e.shouldAutoSave(e.editGen) {
+		t.Error("the timer of the last edit does not save the buffer")
+	}
+
+	e.fileName = ""
+	if e.shouldAutoSave(e.editGen) {
+		t.Error("a buffer without a file name is auto-saved")
+	}
+	e.fileName = path
+	e.autoSaveInterval = 0
+	if e.shouldAutoSave(e.editGen) {
+		t.Error("the buffer is auto-saved with auto-save disabled")
+	}
+}
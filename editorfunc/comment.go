@@ -0,0 +1,100 @@
+package editorfunc
+
+import (
+	"strings"
+)
+
+// luaLineComment is put in front of the lines commented out by ToggleComment
+const luaLineComment = "-- "
+
+// ToggleComment comments out the selected lines, or the line of the cursor without a selection,
+// by putting "-- " in front of them. If all of them are comments already, the "-- " or "--"
+// is taken away instead. Blank lines are left as they are. It is one edit, so a single undo
+// restores the lines.
+func (e *LuaEditor) ToggleComment() {
+	from, to := e.cursorY, e.cursorY
+	if e.selection.active {
+		from, to = e.selection.startY, e.selection.endY
+		if from > to {
+			from, to = to, from
+		}
+		// A selection ending at the start of a line does not take that line in
+		if to > from && e.selectionEndX(to) == 0 {
+			to--
+		}
+	}
+	toggled, shifts := toggleLineComments(e.content, from, to)
+	if shifts == nil {
+		return
+	}
+	// Keep the cursor and the selection on the same characters of their lines
+	shift := func(x, y int) int {
+		if y < from || y > to {
+			return x
+		}
+		return max(x+shifts[y-from], 0)
+	}
+	cursorX := shift(e.cursorX, e.cursorY)
+	e.recordEdit(e.content, toggled, e.cursorX, e.cursorY, cursorX, e.cursorY)
+	e.content = toggled
+	e.cursorX = cursorX
+	if e.selection.active {
+		e.selection.startX = shift(e.selection.startX, e.selection.startY)
+		e.selection.endX = shift(e.selection.endX, e.selection.endY)
+	}
+	e.redraw()
+}
+
+// selectionEndX returns the column where the selection ends on the line y, the last of its lines
+func (e *LuaEditor) selectionEndX(y int) int {
+	if e.selection.endY == y {
+		return e.selection.endX
+	}
+	return e.selection.startX
+}
+
+// toggleLineComments returns the lines with the lines from..to (0-based, both included)
+// commented out, or uncommented if all of them that are not blank are comments, and how
+// many characters every one of them moved right (negative if left). shifts is nil if
+// there is nothing to toggle. The \r kept at the end of some lines in memory stays there.
+func toggleLineComments(lines []string, from, to int) (toggled []string, shifts []int) {
+	from, to = max(from, 0), min(to, len(lines)-1)
+	if from > to {
+		return nil, nil
+	}
+	allComments, hasCode := true, false
+	for _, line := range lines[from : to+1] {
+		body := strings.TrimLeft(strings.TrimSuffix(line, "\r"), " \t")
+		if body == "" {
+			continue
+		}
+		hasCode = true
+		if !strings.HasPrefix(body, "--") {
+			allComments = false
+		}
+	}
+	if !hasCode {
+		return nil, nil
+	}
+	toggled = append([]string(nil), lines...)
+	shifts = make([]int, to-from+1)
+	for i := from; i <= to; i++ {
+		line := lines[i]
+		indent := leadingSpaces(line)
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if !allComments {
+			toggled[i] = luaLineComment + line
+			shifts[i-from] = len(luaLineComment)
+			continue
+		}
+		marker := "--"
+		if strings.HasPrefix(line[indent:], luaLineComment) {
+			marker = luaLineComment
+		}
+		toggled[i] = line[:indent] + line[indent+len(marker):]
+		shifts[i-from] = -len(marker)
+	}
+	return toggled, shifts
+}
@@ -0,0 +1,53 @@
+package editorfunc
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestToggleLineComments(t *testing.T) {
+	tests := []struct {
+		name       string
+		lines      []string
+		from, to   int
+		want       []string
+		wantShifts []int
+	}{
+		{
+			name:       "comments out code",
+			lines:      []string{"local a = 1\r", "  print(a)\r", "", "return a"},
+			from:       0,
+			to:         3,
+			want:       []string{"-- local a = 1\r", "--   print(a)\r", "", "-- return a"},
+			wantShifts: []int{3, 3, 0, 3},
+		},
+		{
+			name:       "uncomments when all lines are comments",
+			lines:      []string{"  -- a()\r", "--b()", "x()"},
+			from:       0,
+			to:         1,
+			want:       []string{"  a()\r", "b()", "x()"},
+			wantShifts: []int{-3, -2},
+		},
+		{
+			name:       "comments out when one line is code",
+			lines:      []string{"-- a()", "b()"},
+			from:       0,
+			to:         1,
+			want:       []string{"-- -- a()", "-- b()"},
+			wantShifts: []int{3, 3},
+		},
+		{
+			name:  "leaves blank lines alone",
+			lines: []string{"a()", "  \r", ""},
+			from:  1,
+			to:    2,
+		},
+	}
+	for _, tt := range tests {
+		got, shifts := toggleLineComments(tt.lines, tt.from, tt.to)
+		if !slices.Equal(got, tt.want) || !slices.Equal(shifts, tt.wantShifts) {
+			t.Errorf("%s: got %q %v, want %q %v", tt.name, got, shifts, tt.want, tt.wantShifts)
+		}
+	}
+}
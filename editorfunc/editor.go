@@ -2,11 +2,13 @@ package editorfunc
 
 import (
 	"fmt"
+	"gotulua/keymapfunc"
 	"gotulua/statefunc"
 	"os"
 	"regexp"
 	"runtime"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/atotto/clipboard"
@@ -21,7 +23,7 @@ const (
 )
 
 const (
-	editorTitle string = " (Ctrl+S to Save, Ctrl+Q to Quit, Ctrl+Z to Undo, Ctrl+Y to Redo, Insert to Copy, Ctrl+F to Find, F10 to Menu, F1 to Help, F5 to Run) "
+	editorTitle string = " (Ctrl+S to Save, Ctrl+Q to Quit, Ctrl+Z to Undo, Ctrl+Y to Redo, Insert to Copy, Ctrl+F to Find, Ctrl+G to Go to Line, Ctrl+/ to Comment, F10 to Menu, F1 to Help, F5 to Run, Ctrl+Enter to Run Selection, F6 to Last Output) "
 )
 
 // EditAction represents a single edit operation that can be undone/redone
@@ -62,6 +64,13 @@ type LuaEditor struct {
 	findText         string
 	currentFindY     int
 	currentFindX     int
+	dirty            bool          // Set when the buffer changed after it was opened or saved
+	editGen          int           // Incremented on every change, tells auto-save if the editor was idle
+	autoSaveInterval time.Duration // Idle time before auto-save, 0 if disabled
+	autoSaveTimer    *time.Timer
+	lineEnding       string // Line ending written by SaveFile, one of the LineEnding styles
+	showOverview     bool   // Draw the overview column on the right border
+	showLineNumbers  bool   // Draw line numbers in a gutter on the left
 }
 
 // Lua syntax highlighting rules
@@ -80,6 +89,9 @@ var (
 	tviewColorPattern   = regexp.MustCompile(`\x01\#{0,1}[A-F0-9\:\-]+\x02`)
 )
 
+// findHighlightTag is the background of the occurrences of the search term
+const findHighlightTag = "[:olive]"
+
 // SyntaxHighlightLua applies Lua syntax highlighting to the given text.
 func SyntaxHighlightLua(text string) string {
 	// To avoid color tags being shown when the cursor is on a keyword (i.e., when tview regions are used),
@@ -166,6 +178,86 @@ func highlightLineByStatus(highlightType int, text string) string {
 	return line
 }
 
+// findMatches reports for every rune of the line whether it belongs to an occurrence of term.
+// The line has its brackets replaced by \x01 and \x02 like in redraw, so the term is escaped the same way.
+func findMatches(line, term string) []bool {
+	term = strings.ReplaceAll(term, "[", "\x01")
+	term = strings.ReplaceAll(term, "]", "\x02")
+	runes := []rune(line)
+	termRunes := []rune(term)
+	matched := make([]bool, len(runes))
+	found := false
+	for i := 0; i+len(termRunes) <= len(runes); {
+		if string(runes[i:i+len(termRunes)]) == term {
+			for j := i; j < i+len(termRunes); j++ {
+				matched[j] = true
+			}
+			found = true
+			i += len(termRunes)
+			continue
+		}
+		i++
+	}
+	if !found {
+		return nil
+	}
+	return matched
+}
+
+// runeIndex returns the rune position of the first occurrence of term in line
+// starting at the rune position from, or -1 if there is none.
+// Positions count runes like cursorX, so lines with multibyte characters are found right.
+func runeIndex(line, term string, from int) int {
+	runes := []rune(line)
+	if from < 0 || from > len(runes) {
+		return -1
+	}
+	index := strings.Index(string(runes[from:]), term)
+	if index < 0 {
+		return -1
+	}
+	return from + utf8.RuneCountInString(string(runes[from:])[:index])
+}
+
+// highlightFindMatches wraps the matched runes of the highlighted line in the search background.
+// Color tags inside a match are kept and the background is set again after each of them.
+func highlightFindMatches(hl string, matched []bool) string {
+	if matched == nil {
+		return hl
+	}
+	var b strings.Builder
+	runeIdx := 0
+	inMatch := false
+	for pos := 0; pos < len(hl); {
+		if hl[pos] == '[' {
+			end := strings.IndexByte(hl[pos:], ']')
+			if end != -1 {
+				b.WriteString(hl[pos : pos+end+1])
+				if inMatch {
+					b.WriteString(findHighlightTag)
+				}
+				pos += end + 1
+				continue
+			}
+		}
+		r, size := utf8.DecodeRuneInString(hl[pos:])
+		isMatch := runeIdx < len(matched) && matched[runeIdx]
+		if isMatch && !inMatch {
+			b.WriteString(findHighlightTag)
+		} else if !isMatch && inMatch {
+			b.WriteString("[:-]")
+		}
+		inMatch = isMatch
+		b.WriteRune(r)
+		pos += size
+		runeIdx++
+	}
+	if inMatch {
+		b.WriteString("[:-]")
+	}
+	return b.String()
+}
+
 // StatusBar is a simple status bar for the LuaEditor.
 type StatusBar struct {
 	*tview.TextView
@@ -214,18 +306,18 @@ func (e *LuaEditor) FindText(text string, again bool) {
 		e.findText = text
 	}
 	if e.findText == "" {
+		if !again {
+			// An empty search clears the highlighted occurrences
+			e.redraw()
+		}
 		return
 	}
 	for e.currentFindY < len(e.content) {
-		cl := e.content[e.currentFindY]
-		if e.currentFindX > 0 && e.currentFindX < len(cl) {
-			cl = cl[e.currentFindX:]
-		}
-		if strings.Contains(cl, e.findText) {
-			index := strings.Index(cl, e.findText)
+		index := runeIndex(e.content[e.currentFindY], e.findText, e.currentFindX)
+		if index >= 0 {
 			e.cursorY = e.currentFindY
-			e.cursorX = index + e.currentFindX
-			e.currentFindX += index + len(e.findText)
+			e.cursorX = index
+			e.currentFindX = index + utf8.RuneCountInString(e.findText)
 			_, _, _, height := e.GetInnerRect()
 			row, _ := e.GetScrollOffset()
 			if e.cursorY >= row+height {
@@ -303,6 +395,7 @@ func (e *LuaEditor) OpenFile(fileName string) error {
 	e.content = strings.Split(string(data), "\n")
 	e.cursorX = 0
 	e.cursorY = 0
+	e.dirty = false
 
 	// Update editor title
 	e.SetBorder(true).SetTitle(fileName + editorTitle)
@@ -359,6 +452,10 @@ func NewLuaEditor(app *tview.Application, initialContent string, fileName string
 		redoStack:        make([]EditAction, 0),
 		highlightedLine:  -1,
 		highlightType:    IsNoHighlight,
+		autoSaveInterval: AutoSaveInterval,
+		lineEnding:       DefaultLineEnding,
+		showOverview:     ShowOverview,
+		showLineNumbers:  ShowLineNumbers,
 	}
 
 	title := ""
@@ -373,32 +470,14 @@ func NewLuaEditor(app *tview.Application, initialContent string, fileName string
 	return editor
 }
 
-// SaveFile saves the current content to the file
+// SaveFile saves the current content to the file with the line ending set by SetLineEnding
 func (e *LuaEditor) SaveFile() error {
-	// Normalize line endings on Windows
-	content := e.content
-	var suffix string
-	if runtime.GOOS == "windows" {
-		suffix = "\r\n"
-	} else {
-		suffix = "\n"
-	}
-	normalizedContent := make([]string, len(content))
-	for i, line := range content {
-		// Remove any existing line endings
-		line = strings.TrimRight(line, "\r\n")
-		// Add Windows line ending
-		normalizedContent[i] = line + suffix
-	}
-	content = normalizedContent
-
-	// Join lines and write to file
-	fileContent := strings.Join(content, "")
-	err := os.WriteFile(e.fileName, []byte(fileContent), 0644)
+	err := os.WriteFile(e.fileName, e.fileContent(), 0644)
 	if err != nil {
 		e.SetErrorStatus(fmt.Sprintf("Error saving file: %v", err))
 		return err
 	}
+	e.dirty = false
 	e.SetStatus("File saved successfully")
 	return nil
 }
@@ -410,15 +489,54 @@ func (e *LuaEditor) SetSaveAsDialogHandler(handler func(*tview.Application, stri
 
 // ShowSaveAsDialog shows the Save As dialog for the editor
 func (e *LuaEditor) ShowSaveAsDialog() {
-	if e.showSaveAsDialog != nil {
-		e.showSaveAsDialog(e.app, e.fileName, func(fileName string) error {
-			return e.SaveFile()
-		}, func() {
-			e.SetStatus("Save As dialog cancelled")
-		})
-	} else {
+	e.saveAs(nil)
+}
+
+// saveAs asks for a file name and saves the content to it. after is called once the file is saved,
+// not when the dialog is cancelled.
+func (e *LuaEditor) saveAs(after func()) {
+	if e.showSaveAsDialog == nil {
 		e.SetErrorStatus("Save As dialog not available")
+		return
 	}
+	saved := false
+	e.showSaveAsDialog(e.app, e.fileName, func(fileName string) error {
+		e.fileName = fileName
+		if err := e.SaveFile(); err != nil {
+			return err
+		}
+		saved = true
+		return nil
+	}, func() {
+		if !saved {
+			e.SetStatus("Save As dialog cancelled")
+			return
+		}
+		if after != nil {
+			after()
+		}
+	})
+}
+
+// SaveAndRun saves the editor content and runs the saved file, so the script that runs is
+// the one in the editor. A file without a name is named in the Save As dialog first.
+// Nothing runs when the file is not saved.
+func (e *LuaEditor) SaveAndRun() {
+	if e.fileName == "" {
+		e.saveAs(e.runFile)
+		return
+	}
+	if err := e.SaveFile(); err != nil {
+		return // SaveFile shows the error in the status bar
+	}
+	e.runFile()
+}
+
+// runFile runs the file of the editor from disk
+func (e *LuaEditor) runFile() {
+	statefunc.PushVisual(statefunc.MainFlex)
+	statefunc.App.SetRoot(statefunc.RunFlexLevel0, true)
+	statefunc.StartScript(statefunc.L, e.GetFileName(), statefunc.RunLuaScriptFunc)
 }
 
 // calculateHeight updates the height field to the number of visible lines in the editor area.
@@ -449,6 +567,11 @@ func (e *LuaEditor) redraw() {
 			hl = SyntaxHighlightLua(line)
 		}
 
+		// Mark every occurrence of the search term
+		if e.findText != "" {
+			hl = highlightFindMatches(hl, findMatches(line, e.findText))
+		}
+
 		// Handle selection highlighting
 		if e.selection.active {
 			var newHl strings.Builder
@@ -625,7 +748,8 @@ func (e *LuaEditor) redraw() {
 			}
 			hl = strings.ReplaceAll(hl, "\x01", "[")
 		}
-		hl = hl + "\r"
+		// The gutter goes in front last, the cursor position above is found in the line alone
+		hl = e.gutter(y) + hl + "\r"
 		e.Write([]byte(hl))
 	}
 }
@@ -635,7 +759,7 @@ func (e *LuaEditor) handleMouse(action tview.MouseAction, event *tcell.EventMous
 	e.highlightType = IsNoHighlight // Reset highlight type on mouse action
 	x, y := event.Position()
 	left, top, _, _ := e.GetInnerRect()
-	innerX, innerY := x-left, y-top
+	innerX, innerY := max(x-left-e.gutterWidth(), 0), y-top
 
 	// Get current scroll offset and adjust innerY
 	row, _ := e.GetScrollOffset()
@@ -733,34 +857,48 @@ func (e *LuaEditor) handleInput(event *tcell.EventKey) *tcell.EventKey {
 	}
 	e.FillStatusBar()
 
-	// Check for Ctrl+Shift+S (Save As)
-	if event.Key() == tcell.KeyCtrlS && event.Modifiers()&tcell.ModShift != 0 {
+	// Commands use the keys of the keymap, Ctrl+Shift+S (Save As) by default
+	if keymapfunc.Matches(keymapfunc.EditorSaveAs, event) {
 		e.ShowSaveAsDialog()
 		return nil
 	}
 
 	// Handle undo/redo
-	if event.Key() == tcell.KeyCtrlZ {
+	if keymapfunc.Matches(keymapfunc.EditorUndo, event) {
 		e.undo()
 		return nil
 	}
-	if event.Key() == tcell.KeyCtrlY {
+	if keymapfunc.Matches(keymapfunc.EditorRedo, event) {
 		e.redo()
 		return nil
 	}
+	if keymapfunc.Matches(keymapfunc.EditorFormat, event) {
+		e.Reformat()
+		return nil
+	}
+	if keymapfunc.Matches(keymapfunc.EditorComment, event) {
+		e.ToggleComment()
+		return nil
+	}
 
-	// Check for Shift+Insert (Paste)
-	if event.Key() == tcell.KeyInsert && event.Modifiers()&tcell.ModShift != 0 || event.Key() == tcell.KeyCtrlV {
+	// Paste, Ctrl+V or Shift+Insert by default
+	if keymapfunc.Matches(keymapfunc.EditorPaste, event) {
 		e.pasteFromClipboard()
 		return nil
 	}
 
-	// Handle copy (using Insert key)
-	if event.Key() == tcell.KeyInsert && event.Modifiers() == tcell.ModNone {
+	// Copy, the Insert key by default
+	if keymapfunc.Matches(keymapfunc.EditorCopy, event) {
 		e.copySelection()
 		return nil
 	}
 
+	// Run the selected text, Ctrl+Enter by default
+	if keymapfunc.Matches(keymapfunc.EditorRunSelection, event) {
+		e.runSelection()
+		return nil
+	}
+
 	// Handle selection with shift + arrow keys
 	if event.Modifiers()&tcell.ModShift != 0 {
 		switch event.Key() {
@@ -784,11 +922,14 @@ func (e *LuaEditor) handleInput(event *tcell.EventKey) *tcell.EventKey {
 	copy(beforeContent, e.content)
 	beforeX, beforeY := e.cursorX, e.cursorY
 
-	switch event.Key() {
-	case tcell.KeyF3, tcell.KeyF4:
+	switch key := event.Key(); {
+	case keymapfunc.Matches(keymapfunc.EditorFindNext, event):
 		e.FindText("", true)
 		return nil
-	case tcell.KeyCtrlS:
+	case keymapfunc.Matches(keymapfunc.EditorGoToLine, event):
+		e.showGoToLine()
+		return nil
+	case keymapfunc.Matches(keymapfunc.EditorSave, event):
 		if e.fileName != "" {
 			err := e.SaveFile()
 			if err != nil {
@@ -803,12 +944,11 @@ func (e *LuaEditor) handleInput(event *tcell.EventKey) *tcell.EventKey {
 			e.onSave(strings.Join(e.content, "\n"))
 		}
 		return nil
-	case tcell.KeyCtrlQ:
+	case key == tcell.KeyCtrlQ:
 		// Exit editor (handled by parent)
 		return event
-	case tcell.KeyF1, tcell.KeyF2:
+	case keymapfunc.Matches(keymapfunc.EditorHelp, event):
 		if statefunc.ShowHelpFunc != nil {
-			statefunc.PushVisual(statefunc.MainFlex)
 			statefunc.ShowHelpFunc(true, func(functionName string) {
 				lineRunes := getRunes(e.content[e.cursorY])
 				if e.cursorX > len(lineRunes) {
@@ -835,12 +975,13 @@ func (e *LuaEditor) handleInput(event *tcell.EventKey) *tcell.EventKey {
 				e.redraw()
 			})
 		}
-	case tcell.KeyF5:
-		statefunc.PushVisual(statefunc.MainFlex)
-		statefunc.App.SetRoot(statefunc.RunFlexLevel0, true)
-		statefunc.StartScript(statefunc.L, e.GetFileName(), statefunc.RunLuaScriptFunc)
+	case keymapfunc.Matches(keymapfunc.EditorRun, event):
+		e.runFile()
+	case keymapfunc.Matches(keymapfunc.EditorSaveAndRun, event):
+		e.SaveAndRun()
+		return nil
 
-	case tcell.KeyUp:
+	case key == tcell.KeyUp:
 		if e.cursorY > 0 {
 			e.cursorY--
 			e.currentFindY = e.cursorY
@@ -857,7 +998,7 @@ func (e *LuaEditor) handleInput(event *tcell.EventKey) *tcell.EventKey {
 				e.ScrollTo(0, 0)
 			}
 		}
-	case tcell.KeyDown:
+	case key == tcell.KeyDown:
 		if e.cursorY < len(e.content)-1 {
 			e.cursorY++
 			e.currentFindY = e.cursorY
@@ -873,7 +1014,7 @@ func (e *LuaEditor) handleInput(event *tcell.EventKey) *tcell.EventKey {
 				e.ScrollTo(e.cursorY-height+1, 0)
 			}
 		}
-	case tcell.KeyLeft:
+	case key == tcell.KeyLeft:
 		if e.cursorX > 0 {
 			e.cursorX--
 		} else if e.cursorY > 0 {
@@ -889,7 +1030,7 @@ func (e *LuaEditor) handleInput(event *tcell.EventKey) *tcell.EventKey {
 		}
 		e.currentFindY = e.cursorY
 		e.currentFindX = 0
-	case tcell.KeyRight:
+	case key == tcell.KeyRight:
 		if e.cursorY <= len(e.content)-1 {
 			lineRunes := getRunes(e.content[e.cursorY])
 			if e.cursorX < len(lineRunes) {
@@ -918,7 +1059,7 @@ func (e *LuaEditor) handleInput(event *tcell.EventKey) *tcell.EventKey {
 		}
 		e.currentFindY = e.cursorY
 		e.currentFindX = 0
-	case tcell.KeyBackspace, tcell.KeyBackspace2:
+	case key == tcell.KeyBackspace || key == tcell.KeyBackspace2:
 		lineRunes := getRunes(e.content[e.cursorY])
 		if e.cursorX > 0 {
 			// Remove rune before cursor
@@ -945,7 +1086,7 @@ func (e *LuaEditor) handleInput(event *tcell.EventKey) *tcell.EventKey {
 		}
 		e.currentFindY = e.cursorY
 		e.currentFindX = 0
-	case tcell.KeyDelete:
+	case key == tcell.KeyDelete:
 		if e.selection.active {
 			// If selection is active, delete the selected text
 			e.deleteSelection()
@@ -979,7 +1120,7 @@ func (e *LuaEditor) handleInput(event *tcell.EventKey) *tcell.EventKey {
 		}
 		e.currentFindY = e.cursorY
 		e.currentFindX = 0
-	case tcell.KeyEnter:
+	case key == tcell.KeyEnter:
 		lineRunes := getRunes(e.content[e.cursorY])
 		// Split at cursor
 		before := lineRunes[:e.cursorX]
@@ -1011,18 +1152,18 @@ func (e *LuaEditor) handleInput(event *tcell.EventKey) *tcell.EventKey {
 		e.cursorX = 0
 		e.currentFindY = e.cursorY
 		e.currentFindX = 0
-	case tcell.KeyHome:
+	case key == tcell.KeyHome:
 		// Move cursor to the beginning of the line
 		e.cursorX = 0
 		e.currentFindY = e.cursorY
 		e.currentFindX = 0
-	case tcell.KeyEnd:
+	case key == tcell.KeyEnd:
 		// Move cursor to the end of the line (rune-aware)
 		lineRunes := getRunes(e.content[e.cursorY])
 		e.cursorX = len(lineRunes)
 		e.currentFindY = e.cursorY
 		e.currentFindX = 0
-	case tcell.KeyPgUp:
+	case key == tcell.KeyPgUp:
 		// Move cursor up by visible height or to top, and scroll screen if needed
 		pageSize := e.height - 1
 		if e.cursorY > pageSize {
@@ -1039,7 +1180,7 @@ func (e *LuaEditor) handleInput(event *tcell.EventKey) *tcell.EventKey {
 		e.ScrollTo(e.cursorY, 0)
 		e.currentFindY = e.cursorY
 		e.currentFindX = 0
-	case tcell.KeyPgDn:
+	case key == tcell.KeyPgDn:
 		// Move cursor down by visible height or to bottom, and scroll screen if needed
 		pageSize := e.height - 1
 		if e.cursorY+pageSize < len(e.content)-1 {
@@ -1069,7 +1210,7 @@ func (e *LuaEditor) handleInput(event *tcell.EventKey) *tcell.EventKey {
 		e.currentFindY = e.cursorY
 		e.currentFindX = 0
 		r := event.Rune()
-		if r != 0 {
+		if r != 0 && (key == tcell.KeyRune || key == tcell.KeyTab) { // Unbound control keys insert nothing
 			lineRunes := getRunes(e.content[e.cursorY])
 			emptyLine := len(lineRunes) == 0
 			var last13 bool
@@ -1085,7 +1226,7 @@ func (e *LuaEditor) handleInput(event *tcell.EventKey) *tcell.EventKey {
 			}
 			var ins []rune
 			if r == '\t' {
-				ins = []rune{' ', ' ', ' ', ' '}
+				ins = []rune(strings.Repeat(" ", TabWidth))
 			} else {
 				ins = []rune{r}
 			}
@@ -1095,7 +1236,7 @@ func (e *LuaEditor) handleInput(event *tcell.EventKey) *tcell.EventKey {
 			}
 			setLine(e.cursorY, lineRunes)
 			if r == '\t' {
-				e.cursorX += 4
+				e.cursorX += TabWidth
 			} else {
 				e.cursorX++
 			}
@@ -1151,6 +1292,13 @@ func (e *LuaEditor) GoToAndHighlightLine(row int) {
 	e.redraw()
 }
 
+// GoToPosition moves the cursor to the row and column (both 0-based) and shows the row.
+// A column past the end of the line puts the cursor at the end.
+func (e *LuaEditor) GoToPosition(row, col int) {
+	e.cursorX = max(col, 0)
+	e.GoToAndHighlightLine(row)
+}
+
 // GetFileName returns the current file name
 func (e *LuaEditor) GetFileName() string {
 	return e.fileName
@@ -1176,6 +1324,7 @@ func (e *LuaEditor) recordEdit(beforeContent []string, afterContent []string, be
 	e.undoStack = append(e.undoStack, action)
 	// Clear redo stack when a new edit is made
 	e.redoStack = nil
+	e.markDirty()
 }
 
 // undo reverts the last edit action
@@ -1209,6 +1358,7 @@ func (e *LuaEditor) undo() {
 	copy(e.content, action.beforeContent)
 	e.cursorX = action.beforeCursorX
 	e.cursorY = action.beforeCursorY
+	e.markDirty()
 	e.redraw()
 	e.SetStatus("Undo successful")
 }
@@ -1244,6 +1394,7 @@ func (e *LuaEditor) redo() {
 	copy(e.content, action.afterContent)
 	e.cursorX = action.afterCursorX
 	e.cursorY = action.afterCursorY
+	e.markDirty()
 	e.redraw()
 	e.SetStatus("Redo successful")
 }
@@ -1431,6 +1582,22 @@ func (e *LuaEditor) copySelection() {
 	}
 }
 
+// runSelection runs the selected text as a separate piece of Lua code
+func (e *LuaEditor) runSelection() {
+	text := e.getSelectedText()
+	if strings.TrimSpace(text) == "" {
+		e.SetStatus("Nothing selected to run")
+		return
+	}
+	if statefunc.RunLuaStringFunc == nil {
+		return
+	}
+	statefunc.SetSelectionStartLine(min(e.selection.startY, e.selection.endY))
+	statefunc.PushVisual(statefunc.MainFlex)
+	statefunc.App.SetRoot(statefunc.RunFlexLevel0, true)
+	statefunc.StartScript(statefunc.L, text, statefunc.RunLuaStringFunc)
+}
+
 // pasteFromClipboard pastes text from clipboard at current cursor position
 func (e *LuaEditor) pasteFromClipboard() error {
 	text, err := clipboard.ReadAll()
@@ -1448,6 +1615,13 @@ func (e *LuaEditor) pasteFromClipboard() error {
 	copy(beforeContent, e.content)
 	beforeX, beforeY := e.cursorX, e.cursorY
 
+	// The line is cut at the cursor by runes, the rest goes after the pasted text
+	runes := []rune(e.content[e.cursorY])
+	if e.cursorX > len(runes) {
+		e.cursorX = len(runes)
+	}
+	rest := string(runes[e.cursorX:])
+
 	// Split pasted text into lines
 	lines := strings.Split(text, "\n")
 	for i, line := range lines {
@@ -1456,15 +1630,10 @@ func (e *LuaEditor) pasteFromClipboard() error {
 
 		if i == 0 {
 			// For first line, insert at cursor position
-			currentLine := e.content[e.cursorY]
-			runes := []rune(currentLine)
-			if e.cursorX > len(runes) {
-				e.cursorX = len(runes)
-			}
 			newLine := string(runes[:e.cursorX]) + line
 			if i == len(lines)-1 {
 				// If this is the only line, append rest of current line
-				newLine += string(runes[e.cursorX:])
+				newLine += rest
 			} else {
 				// Add carriage return for Windows
 				if runtime.GOOS == "windows" {
@@ -1474,9 +1643,7 @@ func (e *LuaEditor) pasteFromClipboard() error {
 			e.content[e.cursorY] = newLine
 		} else if i == len(lines)-1 {
 			// For last line, append rest of current line
-			currentLine := e.content[e.cursorY]
-			runes := []rune(currentLine)
-			line += string(runes[e.cursorX:])
+			line += rest
 			// Insert as new line
 			e.content = append(e.content[:e.cursorY+i], append([]string{line}, e.content[e.cursorY+i:]...)...)
 		} else {
@@ -1491,7 +1658,7 @@ func (e *LuaEditor) pasteFromClipboard() error {
 	// Update cursor position
 	if len(lines) > 1 {
 		e.cursorY += len(lines) - 1
-		e.cursorX = len([]rune(lines[len(lines)-1]))
+		e.cursorX = utf8.RuneCountInString(strings.ReplaceAll(lines[len(lines)-1], "\r", ""))
 	} else {
 		e.cursorX += len([]rune(lines[0]))
 	}
@@ -0,0 +1,77 @@
+package editorfunc
+
+import (
+	"gotulua/keymapfunc"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestHighlightFindMatches(t *testing.T) {
+	tests := []struct {
+		line, term string
+		hl         string // The line as the syntax highlighting leaves it, the line itself if empty
+		want       string
+	}{
+		{"x = ab .. ab", "ab", "", "x = [:olive]ab[:-] .. [:olive]ab[:-]"},
+		{"x = ab", "cd", "", "x = ab"},
+		{"aaa", "aa", "", "[:olive]aa[:-]a"}, // Occurrences do not overlap
+		// Brackets are escaped in the line as redraw does
+		{"t\x01ab\x02 = ab", "[ab]", "", "t[:olive]\x01ab\x02[:-] = ab"},
+		// A color tag inside an occurrence keeps the highlight after it
+		{"local x", "al x", "[#00BFFF::b]local[-::-] x", "[#00BFFF::b]loc[:olive]al[-::-][:olive] x[:-]"},
+	}
+	for _, tt := range tests {
+		hl := tt.hl
+		if hl == "" {
+			hl = tt.line
+		}
+		if got := highlightFindMatches(hl, findMatches(tt.line, tt.term)); got != tt.want {
+			t.Errorf("the occurrences of %q in %q are highlighted as %q, want %q", tt.term, tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestFindTextInMultibyteLines(t *testing.T) {
+	e := NewLuaEditor(nil, "привет мир\nмир, мир", "", nil)
+	want := []struct{ y, x int }{{0, 7}, {1, 0}, {1, 5}}
+	for i, w := range want {
+		e.FindText("мир", i > 0)
+		if e.cursorY != w.y || e.cursorX != w.x {
+			t.Errorf("find %d stands on %d:%d, want %d:%d", i+1, e.cursorY, e.cursorX, w.y, w.x)
+		}
+	}
+	// The search starts over after the last occurrence
+	e.FindText("", true)
+	e.FindText("", true)
+	if e.cursorY != 0 || e.cursorX != 7 {
+		t.Errorf("the search did not start over, it stands on %d:%d", e.cursorY, e.cursorX)
+	}
+}
+
+func TestRemappedSaveKey(t *testing.T) {
+	if err := keymapfunc.Bind(keymapfunc.EditorSave, "F12"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(keymapfunc.Reset)
+	path := filepath.Join(t.TempDir(), "script.lua")
+	saves := 0
+	e := NewLuaEditor(nil, "x = 1", path, func(string) { saves++ })
+
+	e.handleInput(tcell.NewEventKey(tcell.KeyCtrlS, 0, tcell.ModCtrl))
+	if saves != 0 {
+		t.Error("the old key still saves")
+	}
+	if _, err := os.Stat(path); err == nil {
+		t.Error("the old key wrote the file")
+	}
+	e.handleInput(tcell.NewEventKey(tcell.KeyF12, 0, tcell.ModNone))
+	if saves != 1 {
+		t.Errorf("the new key saved %d times, want once", saves)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("the new key did not write the file: %v", err)
+	}
+}
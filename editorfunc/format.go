@@ -0,0 +1,189 @@
+package editorfunc
+
+import (
+	"strings"
+	"unicode"
+)
+
+// TabWidth is the number of spaces inserted by Tab and used for each indentation level by Reformat
+var TabWidth = 4
+
+// Reformat indents the lines of the buffer by the Lua blocks they are in.
+// It is one edit, so a single undo restores the previous indentation.
+func (e *LuaEditor) Reformat() {
+	formatted := reindentLua(e.content, TabWidth)
+	if equalStringSlices(formatted, e.content) {
+		e.SetStatus("Already formatted")
+		return
+	}
+	// Keep the cursor on the same character of its line
+	oldIndent := leadingSpaces(e.content[e.cursorY])
+	newIndent := leadingSpaces(formatted[e.cursorY])
+	cursorX := max(e.cursorX-oldIndent, 0) + newIndent
+
+	e.recordEdit(e.content, formatted, e.cursorX, e.cursorY, cursorX, e.cursorY)
+	e.content = formatted
+	e.cursorX = cursorX
+	e.selection.active = false
+	e.redraw()
+	e.SetStatus("Reformatted")
+}
+
+// leadingSpaces returns the number of spaces and tabs at the start of the line
+func leadingSpaces(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+// reindentLua returns the lines indented by width spaces for every Lua block they are in.
+// It is not a parser: blocks are counted by their keywords and brackets, leaving out strings
+// and comments. The lines inside long strings and comments are kept as they are.
+// The \r kept at the end of some lines in memory stays where it is.
+func reindentLua(lines []string, width int) []string {
+	result := make([]string, len(lines))
+	depth := 0
+	longLevel := -1 // Level of the long string or comment the line starts in, -1 if none
+	for i, line := range lines {
+		body, cr := strings.CutSuffix(line, "\r")
+		suffix := ""
+		if cr {
+			suffix = "\r"
+		}
+		if longLevel >= 0 {
+			// Inside a long string or comment the text is not code
+			result[i] = line
+			end := strings.Index(body, "]"+strings.Repeat("=", longLevel)+"]")
+			if end < 0 {
+				continue
+			}
+			// The code after the long bracket still opens and closes blocks
+			change, _, level := blockChanges(body[end+longLevel+2:])
+			depth = max(depth+change, 0)
+			longLevel = level
+			continue
+		}
+		text := strings.TrimLeft(body, " \t")
+		if text == "" {
+			result[i] = suffix
+			continue
+		}
+		change, leading, level := blockChanges(text)
+		indent := max(depth-leading, 0)
+		result[i] = strings.Repeat(" ", indent*width) + text + suffix
+		depth = max(depth+change, 0)
+		longLevel = level
+	}
+	return result
+}
+
+// blockChanges scans a line of Lua code and returns how it changes the block depth,
+// how many blocks are closed at its start (such lines are indented less), and the level
+// of a long string or comment left open at its end, -1 if none.
+func blockChanges(text string) (change, leading, longLevel int) {
+	atStart := true
+	closeBlock := func() {
+		change--
+		if atStart {
+			leading++
+		}
+	}
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			if level, n := longBracket(runes[i+2:]); level >= 0 {
+				if end := findLongEnd(runes[i+2+n:], level); end >= 0 {
+					i += 2 + n + end
+					continue
+				}
+				return change, leading, level
+			}
+			return change, leading, -1 // a line comment ends the code
+		case r == '[':
+			if level, n := longBracket(runes[i:]); level >= 0 {
+				atStart = false
+				if end := findLongEnd(runes[i+n:], level); end >= 0 {
+					i += n + end
+					continue
+				}
+				return change, leading, level
+			}
+			atStart = false
+			i++
+		case r == '"' || r == '\'':
+			atStart = false
+			i++
+			for i < len(runes) && runes[i] != r {
+				if runes[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			i++
+		case r == '{' || r == '(':
+			change++
+			atStart = false
+			i++
+		case r == '}' || r == ')':
+			closeBlock()
+			i++
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			// Fields like t.end are names, not keywords
+			isField := i > 0 && (runes[i-1] == '.' || runes[i-1] == ':')
+			switch word := string(runes[i:j]); {
+			case isField:
+				atStart = false
+			case word == "end" || word == "until":
+				closeBlock()
+			case word == "else" || word == "elseif":
+				// Closes the previous branch and opens its own; elseif opens with its then
+				closeBlock()
+				if word == "else" {
+					change++
+				}
+				atStart = false
+			case word == "function" || word == "do" || word == "then" || word == "repeat":
+				change++
+				atStart = false
+			default:
+				atStart = false
+			}
+			i = j
+		case unicode.IsSpace(r) || r == ';' || r == ',':
+			i++
+		default:
+			atStart = false
+			i++
+		}
+	}
+	return change, leading, -1
+}
+
+// longBracket returns the level of the long bracket opening the runes, like [[ or [==[,
+// and its length. The level is -1 if they do not start with one.
+func longBracket(runes []rune) (level, length int) {
+	if len(runes) == 0 || runes[0] != '[' {
+		return -1, 0
+	}
+	n := 1
+	for n < len(runes) && runes[n] == '=' {
+		n++
+	}
+	if n < len(runes) && runes[n] == '[' {
+		return n - 1, n + 1
+	}
+	return -1, 0
+}
+
+// findLongEnd returns the position after the long bracket of the level closing in the runes, -1 if none
+func findLongEnd(runes []rune, level int) int {
+	end := "]" + strings.Repeat("=", level) + "]"
+	if i := strings.Index(string(runes), end); i >= 0 {
+		return len([]rune(string(runes)[:i])) + len(end)
+	}
+	return -1
+}
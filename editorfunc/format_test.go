@@ -0,0 +1,49 @@
+package editorfunc
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReformatIndentsBlocksAndUndoRestores(t *testing.T) {
+	original := strings.Join([]string{
+		"local function f(x)",
+		"if x then",
+		"      return { a = 1,",
+		"b = 2 }",
+		"  elseif x == nil then",
+		"error('nil')",
+		"     else",
+		"for i = 1, 3 do print(i) end",
+		"end",
+		"  end",
+		"s = [[",
+		"  kept",
+		"]]",
+	}, "\n")
+	want := []string{
+		"local function f(x)",
+		"    if x then",
+		"        return { a = 1,",
+		"            b = 2 }",
+		"    elseif x == nil then",
+		"        error('nil')",
+		"    else",
+		"        for i = 1, 3 do print(i) end",
+		"    end",
+		"end",
+		"s = [[",
+		"  kept",
+		"]]",
+	}
+	e := NewLuaEditor(nil, original, "", nil)
+	e.Reformat()
+	if !slices.Equal(e.content, want) {
+		t.Errorf("the buffer was reformatted as\n%s\nwant\n%s", strings.Join(e.content, "\n"), strings.Join(want, "\n"))
+	}
+	e.undo()
+	if got := strings.Join(e.content, "\n"); got != original {
+		t.Errorf("undo left\n%s\nwant the original\n%s", got, original)
+	}
+}
@@ -0,0 +1,59 @@
+package editorfunc
+
+import (
+	"fmt"
+	"gotulua/statefunc"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// parseLineNumber returns the 0-based row of the line number typed in the Go to Line prompt.
+// Numbers out of range go to the first or the last of lineCount lines.
+func parseLineNumber(text string, lineCount int) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil {
+		return 0, fmt.Errorf("Go to line: %q is not a line number", strings.TrimSpace(text))
+	}
+	return min(max(n, 1), max(lineCount, 1)) - 1, nil
+}
+
+// showGoToLine asks for a line number and moves the cursor to the start of that line
+func (e *LuaEditor) showGoToLine() {
+	input := tview.NewInputField().
+		SetLabel(fmt.Sprintf("Line (1-%d): ", len(e.content))).
+		SetFieldWidth(10)
+	input.SetBorder(true).SetTitle("Go to Line")
+	input.SetDoneFunc(func(key tcell.Key) {
+		statefunc.PopDialog()
+		if key != tcell.KeyEnter || strings.TrimSpace(input.GetText()) == "" {
+			return
+		}
+		e.goToLine(input.GetText())
+	})
+	root := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(input, 3, 0, true).
+			AddItem(nil, 0, 1, false), 30, 0, true).
+		AddItem(nil, 0, 1, false)
+	statefunc.PushDialog(root, input, statefunc.MainFlex)
+}
+
+// goToLine moves the cursor to column 0 of the line number typed and scrolls to it
+func (e *LuaEditor) goToLine(text string) {
+	row, err := parseLineNumber(text, len(e.content))
+	if err != nil {
+		e.SetErrorStatus(err.Error())
+		return
+	}
+	e.selection.active = false
+	e.cursorX = 0
+	e.currentFindY = row
+	e.currentFindX = 0
+	e.GoToAndHighlightLine(row)
+	e.FillStatusBar()
+}
@@ -0,0 +1,28 @@
+package editorfunc
+
+import "testing"
+
+func TestParseLineNumber(t *testing.T) {
+	tests := []struct {
+		text    string
+		lines   int
+		want    int
+		wantErr bool
+	}{
+		{"1", 10, 0, false},
+		{" 7 ", 10, 6, false},
+		{"10", 10, 9, false},
+		{"25", 10, 9, false}, // Past the end goes to the last line
+		{"0", 10, 0, false},
+		{"-3", 10, 0, false},
+		{"3", 0, 0, false}, // An empty text still has its first line
+		{"abc", 10, 0, true},
+		{"", 10, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseLineNumber(tt.text, tt.lines)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseLineNumber(%q, %d) = %d, %v; want %d, error %v", tt.text, tt.lines, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
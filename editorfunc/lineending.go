@@ -0,0 +1,68 @@
+package editorfunc
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Line ending styles written by SaveFile
+const (
+	LineEndingAuto = "auto" // Native line ending of the OS, \r\n on Windows and \n elsewhere
+	LineEndingLF   = "lf"
+	LineEndingCRLF = "crlf"
+)
+
+// DefaultLineEnding is the line ending style of new editors. It is read when an editor is created.
+var DefaultLineEnding = LineEndingAuto
+
+// ParseLineEnding checks a line ending style name, case does not matter
+func ParseLineEnding(style string) (string, error) {
+	switch s := strings.ToLower(strings.TrimSpace(style)); s {
+	case LineEndingAuto, LineEndingLF, LineEndingCRLF:
+		return s, nil
+	default:
+		return "", fmt.Errorf("unknown line ending %q, use lf, crlf or auto", style)
+	}
+}
+
+// SetLineEnding sets the line ending written by SaveFile: "lf", "crlf" or "auto" for the native one
+func (e *LuaEditor) SetLineEnding(style string) error {
+	s, err := ParseLineEnding(style)
+	if err != nil {
+		return err
+	}
+	e.lineEnding = s
+	return nil
+}
+
+// GetLineEnding returns the line ending style written by SaveFile
+func (e *LuaEditor) GetLineEnding() string {
+	return e.lineEnding
+}
+
+// lineSuffix returns the characters ending every saved line
+func (e *LuaEditor) lineSuffix() string {
+	switch e.lineEnding {
+	case LineEndingLF:
+		return "\n"
+	case LineEndingCRLF:
+		return "\r\n"
+	}
+	if runtime.GOOS == "windows" {
+		return "\r\n"
+	}
+	return "\n"
+}
+
+// fileContent returns the buffer as it is written to the file. The \r kept at the end
+// of the lines in memory is replaced by the line ending.
+func (e *LuaEditor) fileContent() []byte {
+	var sb strings.Builder
+	suffix := e.lineSuffix()
+	for _, line := range e.content {
+		sb.WriteString(strings.TrimRight(line, "\r\n"))
+		sb.WriteString(suffix)
+	}
+	return []byte(sb.String())
+}
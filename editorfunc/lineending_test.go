@@ -0,0 +1,34 @@
+package editorfunc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveFileWritesTheLineEnding(t *testing.T) {
+	tests := []struct {
+		style string
+		want  string
+	}{
+		{"lf", "a = 1\nb = 2\n"},
+		{"CRLF", "a = 1\r\nb = 2\r\n"},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "script.lua")
+		e := NewLuaEditor(nil, "", path, nil)
+		e.content = []string{"a = 1\r", "b = 2"} // A line read from a CRLF file keeps its \r
+		if err := e.SetLineEnding(tt.style); err != nil {
+			t.Fatal(err)
+		}
+		if err := e.SaveFile(); err != nil {
+			t.Fatal(err)
+		}
+		if data, _ := os.ReadFile(path); string(data) != tt.want {
+			t.Errorf("saved with %s as %q, want %q", tt.style, data, tt.want)
+		}
+	}
+	if err := NewLuaEditor(nil, "", "", nil).SetLineEnding("cr"); err == nil {
+		t.Error("an unknown line ending was accepted")
+	}
+}
@@ -0,0 +1,33 @@
+package editorfunc
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ShowLineNumbers is whether new editors draw line numbers in a gutter on their left
+var ShowLineNumbers = true
+
+// SetShowLineNumbers turns the line number gutter on the left of the editor on or off
+func (e *LuaEditor) SetShowLineNumbers(show bool) {
+	e.showLineNumbers = show
+	e.redraw()
+}
+
+// gutterWidth returns the number of columns taken by the line number gutter, 0 if it is off.
+// The numbers are as wide as the number of the last line, followed by a space.
+func (e *LuaEditor) gutterWidth() int {
+	if !e.showLineNumbers {
+		return 0
+	}
+	return len(strconv.Itoa(max(len(e.content), 1))) + 1
+}
+
+// gutter returns the dimmed, right-aligned number of the line at row y (0-based) that
+// starts the line when it is drawn
+func (e *LuaEditor) gutter(y int) string {
+	if !e.showLineNumbers {
+		return ""
+	}
+	return fmt.Sprintf("[gray::d]%*d[-::-] ", e.gutterWidth()-1, y+1)
+}
@@ -0,0 +1,31 @@
+package editorfunc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLineNumbersGutter(t *testing.T) {
+	lines := make([]string, 12)
+	for i := range lines {
+		lines[i] = "x = 1"
+	}
+	e := NewLuaEditor(nil, strings.Join(lines, "\n"), "", nil)
+	e.SetShowLineNumbers(true)
+	if got := e.gutterWidth(); got != 3 {
+		t.Errorf("gutterWidth of 12 lines = %d, want 3", got)
+	}
+	// redraw ends each line with \r
+	shown := strings.Split(e.GetText(true), "\r")
+	if !strings.HasPrefix(shown[0], " 1 x = 1") || !strings.HasPrefix(shown[11], "12 x = 1") {
+		t.Errorf("the lines are drawn as %q and %q, want numbers in front", shown[0], shown[11])
+	}
+
+	e.SetShowLineNumbers(false)
+	if got := e.gutterWidth(); got != 0 {
+		t.Errorf("gutterWidth without numbers = %d, want 0", got)
+	}
+	if shown := e.GetText(true); !strings.HasPrefix(shown, "x = 1") {
+		t.Errorf("the first line is drawn as %q without numbers", strings.SplitN(shown, "\r", 2)[0])
+	}
+}
@@ -0,0 +1,109 @@
+package editorfunc
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// ShowOverview is whether new editors draw the overview column on their right border
+var ShowOverview = true
+
+// Characters of the overview column
+const (
+	overviewTrack  = '│' // Part of the document out of view
+	overviewThumb  = '┃' // Part of the document in view
+	overviewMarker = '■' // Line with an error or a search match
+)
+
+// SetShowOverview turns the overview column on the right border of the editor on or off.
+// The column shows where the visible lines are in the whole document and marks the
+// highlighted error line and the lines matching the search term.
+func (e *LuaEditor) SetShowOverview(show bool) {
+	e.showOverview = show
+}
+
+// Draw draws the editor and its overview column
+func (e *LuaEditor) Draw(screen tcell.Screen) {
+	e.TextView.Draw(screen)
+	if e.showOverview {
+		e.drawOverview(screen)
+	}
+}
+
+// drawOverview draws the overview column over the right border of the editor
+func (e *LuaEditor) drawOverview(screen tcell.Screen) {
+	x, _, width, _ := e.GetRect()
+	_, top, _, height := e.GetInnerRect()
+	if width < 2 || height < 1 {
+		return
+	}
+	x += width - 1
+	offset, _ := e.GetScrollOffset()
+	start, size := overviewThumbRows(len(e.content), offset, height, height)
+	markers := e.overviewMarkers(height)
+	for row := 0; row < height; row++ {
+		ch := overviewTrack
+		style := tcell.StyleDefault.Foreground(tcell.ColorGray)
+		if row >= start && row < start+size {
+			ch = overviewThumb
+			style = style.Foreground(tcell.ColorWhite)
+		}
+		if color, ok := markers[row]; ok {
+			ch = overviewMarker
+			style = style.Foreground(color)
+		}
+		screen.SetContent(x, top+row, ch, nil, style)
+	}
+}
+
+// overviewMarkers returns the color of the marker of every row of the column that has one.
+// An error or warning line wins over search matches on the same row.
+func (e *LuaEditor) overviewMarkers(height int) map[int]tcell.Color {
+	markers := map[int]tcell.Color{}
+	total := len(e.content)
+	if e.findText != "" {
+		for y, line := range e.content {
+			if strings.Contains(line, e.findText) {
+				markers[overviewRow(y, total, height)] = tcell.ColorOlive
+			}
+		}
+	}
+	switch e.highlightType {
+	case IsErrorHighlight:
+		markers[overviewRow(e.highlightedLine, total, height)] = tcell.ColorRed
+	case IsWarningHighlight:
+		markers[overviewRow(e.highlightedLine, total, height)] = tcell.ColorYellow
+	}
+	return markers
+}
+
+// overviewThumbRows returns the first row and the number of rows of an overview column of
+// the height covered by the visible lines, when the document has total lines and is scrolled
+// by offset lines. The whole column is covered when all lines are visible, and the part in
+// view always covers at least one row.
+func overviewThumbRows(total, offset, visible, height int) (start, size int) {
+	if height <= 0 {
+		return 0, 0
+	}
+	if total <= visible || visible <= 0 {
+		return 0, height
+	}
+	offset = min(max(offset, 0), total-visible)
+	size = max(visible*height/total, 1)
+	start = offset * height / total
+	if offset+visible >= total {
+		// The last line is in view, the thumb touches the bottom
+		start = height - size
+	}
+	return min(start, height-size), size
+}
+
+// overviewRow returns the row of an overview column of the height standing for the line
+func overviewRow(line, total, height int) int {
+	if total <= 0 || height <= 0 {
+		return 0
+	}
+	line = min(max(line, 0), total-1)
+	return line * height / total
+}
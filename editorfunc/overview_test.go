@@ -0,0 +1,47 @@
+package editorfunc
+
+import "testing"
+
+func TestOverviewThumbRows(t *testing.T) {
+	tests := []struct {
+		total, offset, visible, height int
+		start, size                    int
+	}{
+		{10, 0, 20, 20, 0, 20},    // All lines are visible
+		{100, 0, 20, 20, 0, 4},    // At the top
+		{100, 40, 20, 20, 8, 4},   // In the middle
+		{100, 80, 20, 20, 16, 4},  // At the bottom
+		{100, 95, 20, 20, 16, 4},  // Scrolled past the end
+		{100, -5, 20, 20, 0, 4},   // Negative offset
+		{1000, 0, 10, 10, 0, 1},   // The thumb keeps one row
+		{1000, 500, 10, 10, 5, 1}, // Long document in the middle
+		{1000, 995, 10, 10, 9, 1}, // Long document at the bottom
+		{100, 0, 20, 0, 0, 0},     // No column
+	}
+	for _, tt := range tests {
+		start, size := overviewThumbRows(tt.total, tt.offset, tt.visible, tt.height)
+		if start != tt.start || size != tt.size {
+			t.Errorf("overviewThumbRows(%d, %d, %d, %d) = %d, %d, want %d, %d",
+				tt.total, tt.offset, tt.visible, tt.height, start, size, tt.start, tt.size)
+		}
+	}
+}
+
+func TestOverviewRow(t *testing.T) {
+	tests := []struct {
+		line, total, height int
+		want                int
+	}{
+		{0, 100, 20, 0},
+		{50, 100, 20, 10},
+		{99, 100, 20, 19},
+		{150, 100, 20, 19}, // Past the last line
+		{3, 10, 20, 6},     // Short document, lines spread over the column
+		{5, 0, 20, 0},      // Empty document
+	}
+	for _, tt := range tests {
+		if got := overviewRow(tt.line, tt.total, tt.height); got != tt.want {
+			t.Errorf("overviewRow(%d, %d, %d) = %d, want %d", tt.line, tt.total, tt.height, got, tt.want)
+		}
+	}
+}
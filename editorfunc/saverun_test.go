@@ -0,0 +1,65 @@
+package editorfunc
+
+import (
+	"gotulua/statefunc"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Shopify/go-lua"
+	"github.com/rivo/tview"
+)
+
+// stubRun sets up the run state with a script function that sends the content of the file it runs
+func stubRun(t *testing.T) <-chan string {
+	t.Helper()
+	statefunc.SetState(tview.NewFlex(), tview.NewFlex(), tview.NewPages(), tview.NewApplication())
+	statefunc.L = lua.NewState()
+	prev := statefunc.RunLuaScriptFunc
+	t.Cleanup(func() { statefunc.RunLuaScriptFunc = prev })
+	ran := make(chan string, 1)
+	statefunc.RunLuaScriptFunc = func(name string) error {
+		data, _ := os.ReadFile(name)
+		ran <- string(data)
+		return nil
+	}
+	return ran
+}
+
+func TestSaveAndRunSavesBeforeRunning(t *testing.T) {
+	ran := stubRun(t)
+	path := filepath.Join(t.TempDir(), "script.lua")
+	if err := os.WriteFile(path, []byte("x = 1"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	e := NewLuaEditor(nil, "", path, nil)
+	e.content = []string{"x = 2"}
+	e.dirty = true
+
+	e.SaveAndRun()
+	select {
+	case content := <-ran:
+		if content != string(e.fileContent()) {
+			t.Errorf("the script ran %q, want the editor content", content)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the script was not run")
+	}
+	if e.IsDirty() {
+		t.Error("the buffer is still dirty")
+	}
+}
+
+func TestSaveAndRunDoesNotRunAFailedSave(t *testing.T) {
+	ran := stubRun(t)
+	e := NewLuaEditor(nil, "x = 2", "", nil)
+	e.fileName = filepath.Join(t.TempDir(), "missing", "script.lua") // The directory does not exist
+
+	e.SaveAndRun()
+	select {
+	case <-ran:
+		t.Error("the script ran although the save failed")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,23 @@
+package errorhandlefunc
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Shopify/go-lua"
+)
+
+// ShowBatchError reports an error when the script runs without the UI.
+// Errors that must stop the script are raised as Lua errors, so the batch
+// runner gets them back from the interpreter; the rest go to stderr.
+func ShowBatchError(L *lua.State, msg string, doPanic bool) {
+	if !doPanic {
+		fmt.Fprintln(os.Stderr, msg)
+		return
+	}
+	lua.Where(L, 1)
+	where, _ := L.ToString(-1)
+	L.Pop(1)
+	L.PushString(where + msg)
+	L.Error()
+}
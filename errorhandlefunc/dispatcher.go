@@ -1,6 +1,10 @@
 package errorhandlefunc
 
-import "github.com/Shopify/go-lua"
+import (
+	"gotulua/statefunc"
+
+	"github.com/Shopify/go-lua"
+)
 
 const (
 	ErrorTypeScript = iota
@@ -13,7 +17,26 @@ func SetLuaState(l *lua.State) {
 	L = l
 }
 
+// ThrowError shows the error, stopping the script if doPanic is true.
+// The error handler set by the script is called first and may take over showing it.
 func ThrowError(msg string, errorType int, doPanic bool) {
+	if callErrorHandler(L, msg, errorType, doPanic) {
+		if doPanic {
+			stopScript(msg, errorType)
+		}
+		return
+	}
+	if errorSink != nil {
+		if doPanic {
+			stopScript(msg, errorType)
+		}
+		errorSink(msg)
+		return
+	}
+	if statefunc.IsBatchMode() {
+		ShowBatchError(L, msg, doPanic)
+		return
+	}
 	switch errorType {
 	case ErrorTypeScript:
 		ShowScriptError(L, msg, doPanic)
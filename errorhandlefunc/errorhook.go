@@ -0,0 +1,94 @@
+package errorhandlefunc
+
+import (
+	"gotulua/statefunc"
+
+	"github.com/Shopify/go-lua"
+)
+
+// errorHandler is the Lua function called with the errors before they are shown, "" if none
+var errorHandler string
+
+// inErrorHandler is set while the error handler runs, so its own errors are shown as usual
+var inErrorHandler bool
+
+// errorSink gets the errors instead of the error dialogs while it is set, see SetErrorSink
+var errorSink func(msg string)
+
+// SetErrorSink sends the errors to sink instead of showing them, nil shows them again.
+// Errors that stop the script are raised as Lua errors, for the caller of the code to report.
+// The console uses it to show the errors of the statements it runs.
+func SetErrorSink(sink func(msg string)) {
+	errorSink = sink
+}
+
+// SetErrorHandler sets the Lua function called with every error before it is shown.
+// An empty name removes the handler.
+func SetErrorHandler(funcName string) {
+	errorHandler = funcName
+}
+
+func GetErrorHandler() string {
+	return errorHandler
+}
+
+// callErrorHandler calls the error handler with a table describing the error:
+// message, type ("script" or "data"), fatal (the script stops), and script and line
+// when the error comes from a script line. Returns true if the handler returned true,
+// which means the error was handled and must not be shown.
+func callErrorHandler(L *lua.State, msg string, errorType int, doPanic bool) bool {
+	if errorHandler == "" || inErrorHandler || L == nil {
+		return false
+	}
+	inErrorHandler = true
+	defer func() { inErrorHandler = false }()
+
+	lua.Where(L, 1)
+	where, _ := L.ToString(-1)
+	L.Pop(1)
+	L.Global(errorHandler)
+	if !L.IsFunction(-1) {
+		L.Pop(1)
+		return false
+	}
+	L.NewTable()
+	L.PushString(msg)
+	L.SetField(-2, "message")
+	if errorType == ErrorTypeData {
+		L.PushString("data")
+	} else {
+		L.PushString("script")
+	}
+	L.SetField(-2, "type")
+	L.PushBoolean(doPanic)
+	L.SetField(-2, "fatal")
+	if script, line := parseScriptLocation(where); script != "" {
+		L.PushString(script)
+		L.SetField(-2, "script")
+		L.PushInteger(line + 1)
+		L.SetField(-2, "line")
+	}
+	if err := L.ProtectedCall(1, 1, 0); err != nil {
+		L.Pop(1) // the error of the handler, the original error is shown instead
+		return false
+	}
+	handled := L.ToBoolean(-1)
+	L.Pop(1)
+	return handled
+}
+
+// stopScript stops the script after an error handled by the error handler, without showing it
+func stopScript(msg string, errorType int) {
+	switch {
+	case errorSink != nil:
+		L.PushString(msg)
+		L.Error()
+	case statefunc.IsBatchMode():
+		ShowBatchError(L, msg, true)
+	case errorType == ErrorTypeData:
+		panic(msg)
+	default:
+		statefunc.SetErrorRun()
+		statefunc.InterruptScript(msg)
+	}
+}
@@ -0,0 +1,48 @@
+package errorhandlefunc
+
+import (
+	"testing"
+
+	"github.com/Shopify/go-lua"
+)
+
+func TestErrorHandlerGetsTheErrorAndCanSuppressIt(t *testing.T) {
+	state := lua.NewState()
+	lua.OpenLibraries(state)
+	prev := L
+	SetLuaState(state)
+	var shown []string
+	SetErrorSink(func(msg string) { shown = append(shown, msg) }) // Stands for the default dialog
+	t.Cleanup(func() {
+		SetErrorHandler("")
+		SetErrorSink(nil)
+		L = prev
+	})
+	if err := lua.DoString(state, `
+		handle = true
+		function onError(e)
+			got = e.message .. "/" .. e.type .. "/" .. tostring(e.fatal)
+			return handle
+		end`); err != nil {
+		t.Fatal(err)
+	}
+	SetErrorHandler("onError")
+
+	ThrowError("bad date", ErrorTypeData, false)
+	state.Global("got")
+	if got, _ := state.ToString(-1); got != "bad date/data/false" {
+		t.Errorf("the handler got %q, want the message, type and fatal flag", got)
+	}
+	state.Pop(1)
+	if len(shown) != 0 {
+		t.Errorf("the handled error was shown: %q", shown)
+	}
+
+	if err := lua.DoString(state, `handle = false`); err != nil {
+		t.Fatal(err)
+	}
+	ThrowError("other", ErrorTypeData, false)
+	if len(shown) != 1 || shown[0] != "other" {
+		t.Errorf("the errors shown are %q, want the one the handler left", shown)
+	}
+}
@@ -32,6 +32,10 @@ func ShowScriptError(L *lua.State, msg string, doPanic bool) {
 		msg = where + msg
 	}
 	script, line := parseScriptLocation(where)
+	if strings.TrimSpace(script) == statefunc.SelectionChunkName {
+		// Lines of the selection are counted from its first line
+		line += statefunc.GetSelectionStartLine()
+	}
 	statefunc.RunFlexLevel0.Clear()
 	if oldLine >= 0 {
 		line = oldLine
@@ -55,6 +59,7 @@ func ShowScriptError(L *lua.State, msg string, doPanic bool) {
 	}
 	if doPanic {
 		// statefunc.InterruptScript(fmt.Sprintf(":::%d:::%s", line, msg))
+		statefunc.SetErrorRun() // The error is shown, the runner must not show it again
 		statefunc.InterruptScript(msg)
 	}
 }
@@ -0,0 +1,14 @@
+-- Here we list the columns of the time sheet tables with their types.
+-- Run ts_init.lua first to create the database.
+DB = DBOpen("./Ts.db")
+
+for _, name in ipairs({"Project", "Task", "Sheet"}) do
+  local t = DBOpenTable(DB, name)
+  print(name)
+  -- GetColumns returns the column names in their order in the table
+  for _, col in ipairs(t:GetColumns()) do
+    print("  " .. col .. ": " .. t:GetFieldType(col))
+  end
+end
+
+DBClose(DB)
@@ -0,0 +1,109 @@
+package gormfunc
+
+import (
+	"database/sql"
+	"fmt"
+	"gotulua/errorhandlefunc"
+	"gotulua/i18nfunc"
+	"gotulua/statefunc"
+	"gotulua/typesfunc"
+)
+
+// Sum returns the sum of the numeric field over the rows matching the current filters, 0 if no row matches
+func (t *Table) Sum(field string) (float64, bool) {
+	defer t.timeOperation("Sum")()
+	return t.numericAggregate("SUM", field)
+}
+
+// Avg returns the average of the numeric field over the rows matching the current filters, 0 if no row matches
+func (t *Table) Avg(field string) (float64, bool) {
+	defer t.timeOperation("Avg")()
+	return t.numericAggregate("AVG", field)
+}
+
+// Min returns the smallest value of the field in the rows matching the current filters,
+// in user format. The value is nil if no row matches or the field is empty in all of them.
+func (t *Table) Min(field string) (interface{}, bool) {
+	defer t.timeOperation("Min")()
+	return t.valueAggregate("MIN", field)
+}
+
+// Max returns the largest value of the field in the rows matching the current filters,
+// in user format. The value is nil if no row matches or the field is empty in all of them.
+func (t *Table) Max(field string) (interface{}, bool) {
+	defer t.timeOperation("Max")()
+	return t.valueAggregate("MAX", field)
+}
+
+// Count returns the number of rows matching the current filters
+func (t *Table) Count() (int64, bool) {
+	defer t.timeOperation("Count")()
+	statefunc.ClearErrors()
+	var count int64
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s", t.Name) + t.whereClause()
+	if err := t.conn().Raw(query, t.rangeFilter...).Row().Scan(&count); err != nil {
+		statefunc.SetLastErrorText(err.Error())
+		return 0, false
+	}
+	return count, true
+}
+
+// numericAggregate runs SUM or AVG on an Integer or Real field honoring the filters of Find
+func (t *Table) numericAggregate(function, field string) (float64, bool) {
+	statefunc.ClearErrors()
+	if !t.checkAggregateField(field) {
+		return 0, false
+	}
+	if ft := t.fieldTypes[field]; ft != typesfunc.TypeInteger && ft != typesfunc.TypeReal {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.db_field_not_numeric", map[string]interface{}{
+			"Field": field,
+			"Table": t.Name,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0, false
+	}
+	var result sql.NullFloat64
+	query := fmt.Sprintf("SELECT %s(\"%s\") FROM %s", function, field, t.Name) + t.whereClause()
+	if err := t.conn().Raw(query, t.rangeFilter...).Row().Scan(&result); err != nil {
+		statefunc.SetLastErrorText(err.Error())
+		return 0, false
+	}
+	return result.Float64, true // NULL when no row matches, which is 0
+}
+
+// valueAggregate runs MIN or MAX on a field of any type honoring the filters of Find
+func (t *Table) valueAggregate(function, field string) (interface{}, bool) {
+	statefunc.ClearErrors()
+	if !t.checkAggregateField(field) {
+		return nil, false
+	}
+	var v interface{}
+	query := fmt.Sprintf("SELECT %s(\"%s\"%s) FROM %s", function, field, t.collateClause(field), t.Name) + t.whereClause()
+	if err := t.conn().Raw(query, t.rangeFilter...).Row().Scan(&v); err != nil {
+		statefunc.SetLastErrorText(err.Error())
+		return nil, false
+	}
+	if v == nil {
+		return nil, true
+	}
+	if b, ok := v.([]byte); ok {
+		v = string(b)
+	}
+	uv, err := t.valueToUserFormat(field, v)
+	if err != nil {
+		statefunc.SetLastErrorText(err.Error())
+		return nil, false
+	}
+	return uv, true
+}
+
+// checkAggregateField throws a script error if the table has no such field
+func (t *Table) checkAggregateField(field string) bool {
+	if t.GetFieldType(field) == "" {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.db_field_not_found", map[string]interface{}{
+			"Field": field,
+			"Table": t.Name,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return false
+	}
+	return true
+}
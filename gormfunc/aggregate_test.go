@@ -0,0 +1,49 @@
+package gormfunc
+
+import (
+	"gotulua/statefunc"
+	"testing"
+)
+
+func TestAggregatesFollowTheFilters(t *testing.T) {
+	db := newTestDB(t)
+	table := newTestTable(t, db, "Sales", "n::Name;t::Text;l::20|n::Qty;t::Integer|n::Price;t::Float")
+	for i, name := range []string{"d", "b", "a", "c"} {
+		insert(t, table, map[string]interface{}{"Name": name, "Qty": int64(i + 1), "Price": float64(i+1) * 2.5})
+	}
+
+	check := func(what string, got, want interface{}, ok bool) {
+		t.Helper()
+		if !ok {
+			t.Fatalf("%s failed: %s", what, statefunc.GetLastErrorText())
+		}
+		if got != want {
+			t.Errorf("%s = %v, want %v", what, got, want)
+		}
+	}
+	sum, ok := table.Sum("Qty")
+	check("Sum", sum, 10.0, ok)
+	avg, ok := table.Avg("Price")
+	check("Avg", avg, 6.25, ok)
+	minName, ok := table.Min("Name")
+	check("Min", minName, "a", ok)
+	maxQty, ok := table.Max("Qty")
+	check("Max", maxQty, int64(4), ok)
+	count, ok := table.Count()
+	check("Count", count, int64(4), ok)
+
+	table.SetRangeFilter("Qty", 2, 3)
+	sum, ok = table.Sum("Qty")
+	check("Sum with a filter", sum, 5.0, ok)
+	maxName, ok := table.Max("Name")
+	check("Max with a filter", maxName, "b", ok)
+	count, ok = table.Count()
+	check("Count with a filter", count, int64(2), ok)
+
+	// No matching row gives 0 and nil
+	table.SetRangeFilter("Qty", 10, 20)
+	sum, ok = table.Sum("Qty")
+	check("Sum of no rows", sum, 0.0, ok)
+	minName, ok = table.Min("Name")
+	check("Min of no rows", minName, nil, ok)
+}
@@ -0,0 +1,54 @@
+package gormfunc
+
+import (
+	"fmt"
+	"gotulua/errorhandlefunc"
+	"gotulua/i18nfunc"
+	"gotulua/statefunc"
+)
+
+// DeleteWhere deletes all rows matching the current filters in one statement, with the
+// same WHERE clause as Find, and returns how many were deleted. With soft delete on the
+// rows are flagged instead. The rows read by Find are cleared, and the after delete
+// callback is not called for the rows. Without any filter it throws a script error
+// unless force is set, so a forgotten filter does not empty the table.
+func (t *Table) DeleteWhere(force bool) (int64, bool) {
+	defer t.timeOperation("DeleteWhere")()
+	statefunc.ClearErrors()
+	if t.refuseWrite() {
+		return 0, false
+	}
+	if !force && !t.hasFilter() {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.db_delete_without_filter", map[string]interface{}{
+			"Table": t.Name,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0, false
+	}
+	var query string
+	var args []interface{}
+	if t.softDeleteField != "" {
+		query, args = t.softDeleteStatement()
+	} else {
+		query = fmt.Sprintf("DELETE FROM %s", t.Name)
+	}
+	result := t.conn().Exec(query+t.whereClause(), append(args, t.rangeFilter...)...)
+	if result.Error != nil {
+		statefunc.SetLastErrorText(t.writeErrorText(result.Error))
+		return 0, false
+	}
+	t.Rows = &Rowset{Rows: []Record{}, Pos: 0}
+	return result.RowsAffected, true
+}
+
+// hasFilter reports whether any filter of Find is set, leaving out soft delete
+func (t *Table) hasFilter() bool {
+	if t.plainFilter != "" || len(t.rangeFilter) == 2 {
+		return true
+	}
+	for _, v := range t.filteredFields {
+		if v != "" {
+			return true
+		}
+	}
+	return false
+}
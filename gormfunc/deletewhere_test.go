@@ -0,0 +1,34 @@
+package gormfunc
+
+import (
+	"gotulua/statefunc"
+	"testing"
+)
+
+func TestDeleteWhereDeletesTheFilteredRows(t *testing.T) {
+	db := newTestDB(t)
+	table := newTestTable(t, db, "Names", "n::Name;t::Text;l::20|n::Qty;t::Integer")
+	for i, name := range []string{"a", "b", "c", "d"} {
+		insert(t, table, map[string]interface{}{"Name": name, "Qty": int64(i + 1)})
+	}
+
+	table.SetRangeFilter("Qty", 2, 3)
+	deleted, ok := table.DeleteWhere(false)
+	if !ok || deleted != 2 {
+		t.Fatalf("DeleteWhere deleted %d rows, ok %v, want 2: %s", deleted, ok, statefunc.GetLastErrorText())
+	}
+	if len(table.Rows.Rows) != 0 {
+		t.Errorf("the loaded rows were not cleared: %d left", len(table.Rows.Rows))
+	}
+	rest := OpenTable(db, "Names")
+	rest.OrderBy("Name")
+	rest.Find()
+	if got := names(rest); len(got) != 2 || got[0] != "a" || got[1] != "d" {
+		t.Errorf("rows left %v, want [a d]", got)
+	}
+
+	deleted, ok = rest.DeleteWhere(true)
+	if !ok || deleted != 2 {
+		t.Errorf("forced DeleteWhere deleted %d rows, ok %v, want 2", deleted, ok)
+	}
+}
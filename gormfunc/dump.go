@@ -0,0 +1,141 @@
+package gormfunc
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// createTablePrefix matches the start of the DDL kept by SQLite for a table
+var createTablePrefix = regexp.MustCompile(`(?i)^CREATE\s+TABLE\s+(IF\s+NOT\s+EXISTS\s+)?`)
+
+// ExportDump writes the schema and rows of all user tables to a SQL file, with the metadata
+// of their special field types. Running the file with ExecScript recreates the tables
+// and their rows in another database.
+func ExportDump(db *gorm.DB, path string) error {
+	names, err := userTables(db)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	fmt.Fprintf(w, "-- Dump written %s\n", time.Now().Format("2006-01-02 15:04:05"))
+	// The tables are written by name, so a row may come before the row it references:
+	// all tables are created first and the references are checked at the end
+	fmt.Fprintln(w, "PRAGMA defer_foreign_keys = ON;")
+	for _, name := range names {
+		var ddl string
+		if err := db.Raw("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", name).Scan(&ddl).Error; err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "\n-- Table %s\n", name)
+		fmt.Fprintf(w, "%s;\n", createTablePrefix.ReplaceAllString(ddl, "CREATE TABLE IF NOT EXISTS "))
+	}
+	for _, name := range names {
+		fmt.Fprintf(w, "\n-- Rows of %s\n", name)
+		if err := dumpRows(db, w, name, "", nil); err != nil {
+			return err
+		}
+		// The metadata keeps the Date, Time, DateTime and Boolean types of the fields
+		fmt.Fprintf(w, "DELETE FROM %s WHERE table_name = %s;\n", SysMetaTable, sqlLiteral(name))
+		if err := dumpRows(db, w, SysMetaTable, "table_name = ?", []interface{}{name}, PrimaryKeyField); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// dumpRows writes an INSERT statement for every row of the table matching the condition.
+// The skipped columns are left out, so the target database gives them their values.
+func dumpRows(db *gorm.DB, w *bufio.Writer, table, where string, args []interface{}, skip ...string) error {
+	query := "SELECT * FROM " + table
+	if where != "" {
+		query += " WHERE " + where
+	}
+	rows, err := db.Raw(query, args...).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	var names []string
+	var kept []int
+	for i, col := range columns {
+		if !containsString(skip, col) {
+			names = append(names, `"`+col+`"`)
+			kept = append(kept, i)
+		}
+	}
+	values := make([]interface{}, len(columns))
+	ptrs := make([]interface{}, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return err
+		}
+		literals := make([]string, len(kept))
+		for j, i := range kept {
+			literals[j] = sqlLiteral(values[i])
+		}
+		fmt.Fprintf(w, "INSERT INTO %s (%s) VALUES (%s);\n", table, strings.Join(names, ", "), strings.Join(literals, ", "))
+	}
+	return rows.Err()
+}
+
+// sqlLiteral returns the value written as a SQLite literal.
+// Quotes in strings are doubled and binary values are written as blobs.
+func sqlLiteral(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return "'" + strings.ReplaceAll(x, "'", "''") + "'"
+	case []byte:
+		return "X'" + hex.EncodeToString(x) + "'"
+	case bool:
+		if x {
+			return "1"
+		}
+		return "0"
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case float64:
+		s := strconv.FormatFloat(x, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".eEIN") {
+			s += ".0" // Keep the value a REAL
+		}
+		return s
+	case time.Time:
+		return "'" + x.Format("2006-01-02 15:04:05") + "'"
+	}
+	return sqlLiteral(fmt.Sprint(v))
+}
+
+// containsString reports whether the list has the string
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package gormfunc
+
+import (
+	"gotulua/typesfunc"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// tableRows returns all rows of the table as the database stores them, by id
+func tableRows(t *testing.T, db *gorm.DB, name string) []map[string]interface{} {
+	t.Helper()
+	var rows []map[string]interface{}
+	if err := db.Raw("SELECT * FROM " + name + " ORDER BY id").Scan(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	return rows
+}
+
+func TestExportDumpRoundTrip(t *testing.T) {
+	src := newTestDB(t)
+	newTestTable(t, src, "People", "n::Name;t::Text;l::30|n::Age;t::Integer|n::Score;t::Float|n::Active;t::Boolean")
+	newTestTable(t, src, "Notes", "n::Text;t::Text;l::100")
+	statements := []struct {
+		sql  string
+		args []interface{}
+	}{
+		{"INSERT INTO People (Name, Age, Score, Active) VALUES (?, ?, ?, ?)", []interface{}{"O'Brien", 42, 7.5, 1}},
+		{"INSERT INTO People (Name, Age, Score, Active) VALUES (?, ?, ?, ?)", []interface{}{"Ann", 30, 3.0, 0}},
+		{"INSERT INTO People (Name, Age, Score, Active) VALUES (?, NULL, ?, ?)", []interface{}{"Bo", 1.25, 1}},
+		{"INSERT INTO Notes (Text) VALUES (?)", []interface{}{"line 1\nline 2; with a semicolon"}},
+	}
+	for _, s := range statements {
+		if _, err := Exec(src, s.sql, s.args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, ok := NextSequence(src, "kept out"); !ok {
+		t.Fatal("NextSequence failed")
+	}
+
+	path := filepath.Join(t.TempDir(), "dump.sql")
+	if err := ExportDump(src, path); err != nil {
+		t.Fatal(err)
+	}
+	dst := newTestDB(t)
+	if err := ExecScript(dst, path); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"People", "Notes"} {
+		if got, want := tableRows(t, dst, name), tableRows(t, src, name); !reflect.DeepEqual(got, want) {
+			t.Errorf("rows of %s after the round trip\n%v\nwant\n%v", name, got, want)
+		}
+	}
+	if tp := OpenTable(dst, "People").GetFieldType("Active"); tp != typesfunc.TypeBoolean {
+		t.Errorf("Active is of type %q after the round trip, want %q", tp, typesfunc.TypeBoolean)
+	}
+	if TableExists(dst, SysSequenceTable) {
+		t.Errorf("the dump holds the system table %s", SysSequenceTable)
+	}
+}
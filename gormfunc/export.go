@@ -0,0 +1,174 @@
+package gormfunc
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gotulua/boolfunc"
+	"gotulua/i18nfunc"
+	"gotulua/statefunc"
+	"gotulua/typesfunc"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatJSON = "json"
+)
+
+// exportColumns returns the columns to export, all table columns if none are given
+func (t *Table) exportColumns(columns []string) []string {
+	if len(columns) > 0 {
+		return columns
+	}
+	return t.Columns
+}
+
+// eachExportRow calls fn for every loaded row with the values of the columns in user format.
+// The current row position is kept.
+func (t *Table) eachExportRow(columns []string, fn func(values []interface{}) error) error {
+	if t.Rows == nil {
+		return nil
+	}
+	pos := t.Rows.Pos
+	defer func() { t.Rows.Pos = pos }()
+	for i := range t.Rows.Rows {
+		t.Rows.Pos = i
+		values := make([]interface{}, len(columns))
+		for j, col := range columns {
+			values[j] = t.exportValue(col)
+		}
+		if err := fn(values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportValue returns the field of the current row in user format.
+// Booleans come from SQLite as integers and are shown as true/false like in the browse.
+func (t *Table) exportValue(field string) interface{} {
+	v := t.GetField(field, "")
+	if t.fieldTypes[field] != typesfunc.TypeBoolean {
+		return v
+	}
+	switch val := v.(type) {
+	case int, int64:
+		if b, err := boolfunc.FormatBool(fmt.Sprintf("%d", val), boolfunc.ToUserFormat); err == nil {
+			return b
+		}
+	}
+	return v
+}
+
+// WriteCSV writes the loaded rows as CSV with a header line of column names
+func (t *Table) WriteCSV(w io.Writer, columns []string) error {
+	columns = t.exportColumns(columns)
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	err := t.eachExportRow(columns, func(values []interface{}) error {
+		record := make([]string, len(values))
+		for i, v := range values {
+			if v != nil {
+				record[i] = fmt.Sprint(v)
+			}
+		}
+		return cw.Write(record)
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the loaded rows as a JSON array of objects keeping the column order
+func (t *Table) WriteJSON(w io.Writer, columns []string) error {
+	columns = t.exportColumns(columns)
+	var buf bytes.Buffer
+	buf.WriteString("[")
+	first := true
+	err := t.eachExportRow(columns, func(values []interface{}) error {
+		if !first {
+			buf.WriteString(",")
+		}
+		first = false
+		buf.WriteString("\n  {")
+		for i, col := range columns {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			key, err := json.Marshal(col)
+			if err != nil {
+				return err
+			}
+			val, err := json.Marshal(values[i])
+			if err != nil {
+				return err
+			}
+			buf.Write(key)
+			buf.WriteString(": ")
+			buf.Write(val)
+		}
+		buf.WriteString("}")
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !first {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("]\n")
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// ExportFile writes the loaded rows to the file in the given format (csv or json)
+func (t *Table) ExportFile(path, format string, columns []string) error {
+	var buf bytes.Buffer
+	var err error
+	switch strings.ToLower(format) {
+	case ExportFormatCSV:
+		err = t.WriteCSV(&buf, columns)
+	case ExportFormatJSON:
+		err = t.WriteJSON(&buf, columns)
+	default:
+		return errors.New(i18nfunc.T("error.export_unsupported_format", map[string]interface{}{
+			"Format": format,
+		}))
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// ExportCSV writes the rows matching the current filters, in the current order, to a CSV file
+// with all table columns in user format. The rows loaded before stay loaded, with HasMore as it was.
+// Returns the number of rows written, false on error with the reason in the last error.
+func (t *Table) ExportCSV(path string) (int, bool) {
+	rows, hasMore := t.Rows, t.hasMore
+	defer func() { t.Rows, t.hasMore = rows, hasMore }()
+	t.Find()
+	if statefunc.GetLastErrorText() != "" {
+		return 0, false
+	}
+	if err := t.ExportFile(path, ExportFormatCSV, nil); err != nil {
+		statefunc.SetLastErrorText(i18nfunc.T("error.export_failed", map[string]interface{}{
+			"Path":  path,
+			"Error": err.Error(),
+		}))
+		return 0, false
+	}
+	if t.Rows == nil {
+		return 0, true
+	}
+	return len(t.Rows.Rows), true
+}
@@ -0,0 +1,43 @@
+package gormfunc
+
+import (
+	"gotulua/statefunc"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExportCSVWritesTheFilteredRowsAndKeepsTheLoadedOnes(t *testing.T) {
+	db := newTestDB(t)
+	table := newTestTable(t, db, "Names", "n::Name;t::Text;l::20|n::Qty;t::Integer")
+	for i, name := range []string{"a", "b", "c", "d"} {
+		insert(t, table, map[string]interface{}{"Name": name, "Qty": int64(i + 1)})
+	}
+	table.OrderBy("Name").SetLimit(2)
+	table.Find()
+	if !table.HasMore() {
+		t.Fatal("Find with limit 2 of 4 rows has no more rows")
+	}
+	loaded := table.Rows
+
+	table.SetLimit(0)
+	table.SetFilter("Qty", ">1")
+	path := filepath.Join(t.TempDir(), "names.csv")
+	n, ok := table.ExportCSV(path)
+	if !ok || n != 3 {
+		t.Fatalf("ExportCSV wrote %d rows, ok %v, want 3: %s", n, ok, statefunc.GetLastErrorText())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "id,Name,Qty\n2,b,2\n3,c,3\n4,d,4\n"; string(data) != want {
+		t.Errorf("the file holds %q, want %q", data, want)
+	}
+	if table.Rows != loaded {
+		t.Error("ExportCSV replaced the loaded rows")
+	}
+	if !table.HasMore() {
+		t.Error("ExportCSV cleared HasMore of the loaded rows")
+	}
+}
@@ -0,0 +1,99 @@
+package gormfunc
+
+import (
+	"fmt"
+	"gotulua/errorhandlefunc"
+	"gotulua/i18nfunc"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// foreignKeyActions maps the fkdel:: values of a field to the ON DELETE actions of SQLite
+var foreignKeyActions = map[string]string{
+	"Restrict": "RESTRICT",
+	"Cascade":  "CASCADE",
+	"SetNull":  "SET NULL",
+}
+
+// connDSN returns the data source name opening the database file with foreign keys enforced.
+// The option is applied to every connection of the pool, unlike a PRAGMA run once at open.
+func connDSN(path string) string {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + "_foreign_keys=on"
+}
+
+// referencesClause returns the REFERENCES clause of a field declared with "fk::refTable.refField"
+// and optionally "fkdel::Restrict|Cascade|SetNull". It throws a script error and returns false
+// if they are not valid.
+func referencesClause(field, ref, onDelete string) (string, bool) {
+	refTable, refField, ok := strings.Cut(ref, ".")
+	if !ok || refTable == "" || refField == "" {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.db_invalid_foreign_key", map[string]interface{}{
+			"Field": field,
+			"Value": ref,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return "", false
+	}
+	clause := fmt.Sprintf(" REFERENCES %s(%s)", refTable, refField)
+	if onDelete != "" {
+		action, ok := foreignKeyActions[onDelete]
+		if !ok {
+			errorhandlefunc.ThrowError(i18nfunc.T("error.db_invalid_foreign_key_action", map[string]interface{}{
+				"Field": field,
+				"Value": onDelete,
+			}), errorhandlefunc.ErrorTypeScript, true)
+			return "", false
+		}
+		clause += " ON DELETE " + action
+	}
+	return clause, true
+}
+
+// isNullDefault reports whether a column default read with PRAGMA table_info is NULL
+func isNullDefault(v interface{}) bool {
+	if b, ok := v.([]byte); ok {
+		v = string(b)
+	}
+	return v == nil || strings.EqualFold(fmt.Sprint(v), "NULL")
+}
+
+// foreignKeys returns the structure keys of the foreign keys of the table by field,
+// like "fk::orders.id;fkdel::Cascade"
+func foreignKeys(db *gorm.DB, name string) (map[string]string, error) {
+	rows, err := db.Raw("PRAGMA foreign_key_list(" + name + ")").Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	keys := make(map[string]string)
+	for rows.Next() {
+		var id, seq int
+		var refTable, from, to, onUpdate, onDelete, match string
+		if err := rows.Scan(&id, &seq, &refTable, &from, &to, &onUpdate, &onDelete, &match); err != nil {
+			return nil, err
+		}
+		key := "fk::" + refTable + "." + to
+		for k, action := range foreignKeyActions {
+			if action == onDelete {
+				key += ";fkdel::" + k
+			}
+		}
+		keys[from] = key
+	}
+	return keys, rows.Err()
+}
+
+// writeErrorText returns the text kept as the last error when a write to the table fails.
+// A violated foreign key gets a message naming the table instead of the one of SQLite.
+func (t *Table) writeErrorText(err error) string {
+	if strings.Contains(err.Error(), "FOREIGN KEY constraint failed") {
+		return i18nfunc.T("error.db_foreign_key_failed", map[string]interface{}{
+			"Table": t.Name,
+		})
+	}
+	return err.Error()
+}
@@ -0,0 +1,42 @@
+package gormfunc
+
+import (
+	"gotulua/statefunc"
+	"strings"
+	"testing"
+)
+
+func TestForeignKeysAreEnforced(t *testing.T) {
+	db := newTestDB(t)
+	orders := newTestTable(t, db, "Orders", "n::Name;t::Text;l::20")
+	lines := newTestTable(t, db, "Lines", "n::OrderID;t::Integer;fk::Orders.id;fkdel::Cascade|n::Item;t::Text;l::20")
+	order := insert(t, orders, map[string]interface{}{"Name": "first"})
+
+	insert(t, lines, map[string]interface{}{"OrderID": order, "Item": "kept"})
+	if lines.Insert(map[string]interface{}{"OrderID": order + 1, "Item": "orphan"}, new(int64)) {
+		t.Error("a line referencing a missing order was inserted")
+	} else if msg := statefunc.GetLastErrorText(); !strings.Contains(msg, "breaks a reference") {
+		t.Errorf("the error is %q, want the foreign key message", msg)
+	}
+	// A line without an order is allowed, the reference is NULL by default
+	insert(t, lines, map[string]interface{}{"Item": "loose"})
+
+	structure, err := tableStructure(db, "Lines")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(structure, "n::OrderID;t::Integer;fk::Orders.id;fkdel::Cascade") {
+		t.Errorf("the structure %q does not keep the foreign key", structure)
+	}
+
+	if _, err := Exec(db, "DELETE FROM Orders WHERE id = ?", order); err != nil {
+		t.Fatal(err)
+	}
+	var items []string
+	if err := db.Raw("SELECT Item FROM Lines ORDER BY id").Scan(&items).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0] != "loose" {
+		t.Errorf("the lines left are %v, want the lines of the deleted order deleted too", items)
+	}
+}
@@ -0,0 +1,163 @@
+package gormfunc
+
+import (
+	"encoding/csv"
+	"errors"
+	"gotulua/boolfunc"
+	"gotulua/errorhandlefunc"
+	"gotulua/i18nfunc"
+	"gotulua/statefunc"
+	"gotulua/timefunc"
+	"gotulua/typesfunc"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ImportCSV inserts the rows of a CSV file into the table. The header line names the columns
+// and the cells are in user format, like the files written by ExportCSV. Empty cells get the
+// default of the field and the id column is left to the database. All rows are inserted in
+// one transaction, so a row that can not be inserted leaves the table and its loaded rows as they were.
+// Returns the number of rows inserted, false on error with the reason in the last error.
+func (t *Table) ImportCSV(path string) (int, bool) {
+	statefunc.ClearErrors()
+	if t.refuseWrite() {
+		return 0, false
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		statefunc.SetLastErrorText(err.Error())
+		return 0, false
+	}
+	defer f.Close()
+	r := csv.NewReader(f)
+	header, err := r.Read()
+	if err != nil {
+		statefunc.SetLastErrorText(i18nfunc.T("error.db_import_no_header", map[string]interface{}{
+			"Path": path,
+		}))
+		return 0, false
+	}
+	var unknown []string
+	for i, col := range header {
+		col = strings.TrimSpace(strings.TrimPrefix(col, "\ufeff")) // Spreadsheets may start the file with a BOM
+		header[i] = col
+		if t.GetFieldType(col) == "" {
+			unknown = append(unknown, col)
+		}
+	}
+	if len(unknown) > 0 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.db_import_unknown_columns", map[string]interface{}{
+			"Columns": strings.Join(unknown, ", "),
+			"Table":   t.Name,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0, false
+	}
+	// The rows are inserted into a copy of the loaded rows, a failed import puts the loaded ones back
+	rows := t.Rows
+	if rows == nil {
+		t.Rows = &Rowset{}
+	} else {
+		t.Rows = &Rowset{Rows: slices.Clone(rows.Rows), Pos: rows.Pos}
+	}
+	count := 0
+	ok := RunInTransaction(t.db, func() bool {
+		for {
+			cells, err := r.Read()
+			if err == io.EOF {
+				return true
+			}
+			if err != nil {
+				statefunc.SetLastErrorText(err.Error())
+				return false
+			}
+			line, _ := r.FieldPos(0)
+			if err := t.importRow(header, cells); err != nil {
+				statefunc.SetLastErrorText(i18nfunc.T("error.db_import_line_failed", map[string]interface{}{
+					"Line":  line,
+					"Error": err.Error(),
+				}))
+				return false
+			}
+			count++
+		}
+	})
+	if !ok {
+		t.Rows = rows
+		return 0, false
+	}
+	return count, true
+}
+
+// importRow inserts the cells of a CSV line. The cells are checked before the insert,
+// so a wrong value is reported as an error of the line instead of stopping the script.
+func (t *Table) importRow(header, cells []string) error {
+	fields := make(Record)
+	for i, col := range header {
+		if col == PrimaryKeyField || i >= len(cells) || cells[i] == "" {
+			continue
+		}
+		v, err := t.importValue(col, cells[i])
+		if err != nil {
+			return err
+		}
+		fields[col] = v
+	}
+	var id int64
+	if !t.Insert(fields, &id) {
+		return errors.New(statefunc.GetLastErrorText())
+	}
+	return nil
+}
+
+// importValue converts the text of a CSV cell to the value Insert takes for the field.
+// Fields with a write transform get the text as it is, the transform decides about it.
+func (t *Table) importValue(field, text string) (interface{}, error) {
+	if _, ok := t.writeTransforms[field]; ok {
+		return text, nil
+	}
+	tp := t.GetFieldType(field)
+	switch tp {
+	case typesfunc.TypeInteger:
+		v, err := strconv.ParseInt(strings.TrimSpace(text), 10, 64)
+		if err != nil {
+			return nil, t.importValueError(field, text, tp)
+		}
+		return v, nil
+	case typesfunc.TypeReal:
+		v, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil {
+			return nil, t.importValueError(field, text, tp)
+		}
+		return v, nil
+	case typesfunc.TypeBoolean:
+		if _, err := boolfunc.FormatBool(text, boolfunc.ToInternalFormat); err != nil {
+			return nil, err
+		}
+	case typesfunc.TypeDate, typesfunc.TypeTime, typesfunc.TypeDateTime:
+		ft := t.checkFormatType(text, tp)
+		internal := text
+		var err error
+		if ft == 1 {
+			internal, err = timefunc.FormatDateTime(text, tp, timefunc.ToInternalFormat)
+		}
+		if err == nil {
+			err = timefunc.CheckDateTimeConsistent(internal, tp, timefunc.ToInternalFormat)
+		}
+		if ft == -1 || err != nil {
+			return nil, timefunc.FieldFormatError(t.fieldCaption(field), text, tp)
+		}
+	}
+	return text, nil
+}
+
+// importValueError returns the error of a cell that is not a value of the field type
+func (t *Table) importValueError(field, text, tp string) error {
+	return errors.New(i18nfunc.T("error.db_import_invalid_value", map[string]interface{}{
+		"Field": t.fieldCaption(field),
+		"Value": text,
+		"Type":  tp,
+	}))
+}
@@ -0,0 +1,48 @@
+package gormfunc
+
+import (
+	"gotulua/statefunc"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFile writes the text to a file in the temporary directory of the test and returns its path
+func writeFile(t *testing.T, name, text string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestImportCSVRollsBackOnABadLine(t *testing.T) {
+	db := newTestDB(t)
+	table := newTestTable(t, db, "Items", "n::Name;t::Text;l::20|n::Qty;t::Integer")
+
+	n, ok := table.ImportCSV(writeFile(t, "good.csv", "Name,Qty\na,1\nb,\n"))
+	if !ok || n != 2 {
+		t.Fatalf("imported %d rows, ok %v, want 2: %s", n, ok, statefunc.GetLastErrorText())
+	}
+
+	loaded := len(table.Rows.Rows)
+	table.Rows.Pos = 0
+	n, ok = table.ImportCSV(writeFile(t, "bad.csv", "Name,Qty\nc,3\nd,three\ne,5\n"))
+	if ok || n != 0 {
+		t.Errorf("a file with a bad line imported %d rows, ok %v", n, ok)
+	}
+	if msg := statefunc.GetLastErrorText(); !strings.Contains(msg, "three") {
+		t.Errorf("the error %q does not name the bad value", msg)
+	}
+	if len(table.Rows.Rows) != loaded || table.Rows.Pos != 0 {
+		t.Errorf("the failed import left %d loaded rows on %d, want %d on 0", len(table.Rows.Rows), table.Rows.Pos, loaded)
+	}
+	rows := OpenTable(db, "Items")
+	rows.OrderBy("Name")
+	rows.Find()
+	if got := names(rows); strings.Join(got, ",") != "a,b" {
+		t.Errorf("rows after the failed import %v, want [a b]", got)
+	}
+}
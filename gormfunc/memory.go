@@ -0,0 +1,20 @@
+package gormfunc
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// MemoryDB is the path of a database kept in memory instead of a file.
+// Every database opened with it is a new empty one, discarded when it is closed.
+const MemoryDB = ":memory:"
+
+// memoryDBCount numbers the memory databases, so each one gets its own name
+var memoryDBCount atomic.Int64
+
+// memoryDSN returns the data source name of a new memory database. A plain ":memory:" would give
+// every connection of the pool a database of its own; with a shared cache under a name the
+// connections of one database share it, and it is dropped when the last of them is closed.
+func memoryDSN() string {
+	return fmt.Sprintf("file:gotulua_memory_%d?mode=memory&cache=shared", memoryDBCount.Add(1))
+}
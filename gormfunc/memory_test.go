@@ -0,0 +1,39 @@
+package gormfunc
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMemoryDatabasesAreSharedByTheirConnectionsOnly(t *testing.T) {
+	first := newTestDB(t)
+	second := newTestDB(t)
+	newTestTable(t, first, "Names", "n::Name;t::Text;l::20")
+
+	if TableExists(second, "Names") {
+		t.Error("a table of one memory database shows in another")
+	}
+
+	// Another connection of the pool sees the same database
+	sqlDB, err := first.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	held, err := sqlDB.Conn(context.Background()) // Keeps the first connection busy
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer held.Close()
+	if !TableExists(first, "Names") {
+		t.Error("the table is missing on another connection of the database")
+	}
+
+	reopened := OpenDB(MemoryDB)
+	defer CloseDB(reopened)
+	if TableExists(reopened, "Names") {
+		t.Error("a new memory database is not empty")
+	}
+	if !TableExists(reopened, SysMetaTable) {
+		t.Error("a new memory database has no metadata table")
+	}
+}
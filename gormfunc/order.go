@@ -0,0 +1,195 @@
+package gormfunc
+
+import (
+	"fmt"
+	"gotulua/i18nfunc"
+	"gotulua/statefunc"
+	"gotulua/typesfunc"
+	"sort"
+)
+
+// SetOrderField sets the integer field keeping the order of the rows chosen by the user.
+// Find returns the rows in that order, new rows with the field unset or 0 go to the end and
+// MoveUp, MoveDown and MoveTo change it. Returns false if the field is not an integer field
+// of the table.
+func (t *Table) SetOrderField(field string) bool {
+	statefunc.ClearErrors()
+	if t.GetFieldType(field) != typesfunc.TypeInteger {
+		statefunc.SetLastErrorText(i18nfunc.T("error.db_order_field_invalid", map[string]interface{}{
+			"Field": field,
+			"Table": t.Name,
+		}))
+		return false
+	}
+	t.orderField = field
+	t.orderBy = fmt.Sprintf("\"%s\", %s", field, PrimaryKeyField)
+	return true
+}
+
+// HasOrderField reports whether the rows of the table have an order set with SetOrderField
+func (t *Table) HasOrderField() bool {
+	return t.orderField != ""
+}
+
+// nextOrderValue returns the order value that puts a new row after all others
+func (t *Table) nextOrderValue() (int64, error) {
+	var next int64
+	query := fmt.Sprintf("SELECT COALESCE(MAX(\"%s\"), 0) + 1 FROM %s", t.orderField, t.Name)
+	err := t.conn().Raw(query).Scan(&next).Error
+	return next, err
+}
+
+// MoveUp moves the current row one place up in the order
+func (t *Table) MoveUp() bool {
+	if t.Rows == nil {
+		return t.MoveTo(0)
+	}
+	return t.MoveTo(t.Rows.Pos)
+}
+
+// MoveDown moves the current row one place down in the order
+func (t *Table) MoveDown() bool {
+	if t.Rows == nil {
+		return t.MoveTo(0)
+	}
+	return t.MoveTo(t.Rows.Pos + 2)
+}
+
+// MoveTo moves the current row to the position, counted from 1, among the rows loaded by Find.
+// The rows in between shift by one place. The order values of the moved rows are written in
+// a transaction and the loaded rows are reordered the same way, the current row stays on the
+// moved row. Positions outside the rows move it to the first or the last row.
+func (t *Table) MoveTo(position int) bool {
+	statefunc.ClearErrors()
+	if t.orderField == "" {
+		statefunc.SetLastErrorText(i18nfunc.T("error.db_order_field_not_set", map[string]interface{}{
+			"Table": t.Name,
+		}))
+		return false
+	}
+	if t.refuseWrite() {
+		return false
+	}
+	if t.Rows == nil || t.Rows.Pos < 0 || t.Rows.Pos >= len(t.Rows.Rows) {
+		statefunc.SetLastErrorText(i18nfunc.T("error.db_find_not_called", map[string]interface{}{
+			"Table": t.Name,
+		}))
+		return false
+	}
+	from := t.Rows.Pos
+	to := min(max(position-1, 0), len(t.Rows.Rows)-1)
+	if from == to {
+		return true
+	}
+	lo, hi := min(from, to), max(from, to)
+	rows := t.Rows.Rows
+	saved := make([]interface{}, len(rows))
+	for i, r := range rows {
+		saved[i] = r[t.orderField]
+	}
+	ok := RunInTransaction(t.db, func() bool {
+		values := t.orderValues(lo, hi)
+		if values == nil {
+			// Rows sharing an order value can not be swapped, number all rows first
+			if !t.renumberOrder() {
+				return false
+			}
+			values = t.orderValues(lo, hi)
+		}
+		reordered := append(append([]Record(nil), rows[:from]...), rows[from+1:]...)
+		t.Rows.Rows = append(reordered[:to], append([]Record{rows[from]}, reordered[to:]...)...)
+		for i := lo; i <= hi; i++ {
+			if !t.setOrderValue(t.Rows.Rows[i], values[i-lo]) {
+				return false
+			}
+		}
+		return true
+	})
+	if !ok {
+		// The changes were rolled back, so are the loaded rows
+		for i, r := range rows {
+			r[t.orderField] = saved[i]
+		}
+		t.Rows.Rows = rows
+		return false
+	}
+	t.Rows.Pos = to
+	return true
+}
+
+// orderValues returns the sorted order values of the loaded rows lo..hi,
+// nil if two of them are the same
+func (t *Table) orderValues(lo, hi int) []int64 {
+	values := make([]int64, 0, hi-lo+1)
+	for _, r := range t.Rows.Rows[lo : hi+1] {
+		values = append(values, recordInt(r, t.orderField))
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	for i := 1; i < len(values); i++ {
+		if values[i] == values[i-1] {
+			return nil
+		}
+	}
+	return values
+}
+
+// renumberOrder numbers all rows of the table from 1 in their current order,
+// also those not loaded, and updates the order values of the loaded rows
+func (t *Table) renumberOrder() bool {
+	var ids []int64
+	query := fmt.Sprintf("SELECT %s FROM %s ORDER BY \"%s\", %s", PrimaryKeyField, t.Name, t.orderField, PrimaryKeyField)
+	if err := t.conn().Raw(query).Scan(&ids).Error; err != nil {
+		statefunc.SetLastErrorText(err.Error())
+		return false
+	}
+	numbers := make(map[int64]int64, len(ids))
+	update := fmt.Sprintf("UPDATE %s SET \"%s\" = ? WHERE %s = ?", t.Name, t.orderField, PrimaryKeyField)
+	for i, id := range ids {
+		numbers[id] = int64(i + 1)
+		if err := t.conn().Exec(update, i+1, id).Error; err != nil {
+			statefunc.SetLastErrorText(t.writeErrorText(err))
+			return false
+		}
+	}
+	for _, r := range t.Rows.Rows {
+		if n, ok := numbers[recordInt(r, PrimaryKeyField)]; ok {
+			r[t.orderField] = n
+		}
+	}
+	return true
+}
+
+// setOrderValue writes the order value of the row, in the database and in the loaded record
+func (t *Table) setOrderValue(r Record, value int64) bool {
+	if recordInt(r, t.orderField) == value {
+		return true
+	}
+	update := fmt.Sprintf("UPDATE %s SET \"%s\" = ? WHERE %s = ?", t.Name, t.orderField, PrimaryKeyField)
+	if err := t.conn().Exec(update, value, recordInt(r, PrimaryKeyField)).Error; err != nil {
+		statefunc.SetLastErrorText(t.writeErrorText(err))
+		return false
+	}
+	r[t.orderField] = value
+	return true
+}
+
+// recordInt returns the integer field of the record, 0 if it is not set
+func recordInt(r Record, field string) int64 {
+	return intValue(r[field])
+}
+
+// intValue returns the integer held by the value, 0 if it is not a number
+func intValue(v interface{}) int64 {
+	if vp, ok := v.(*interface{}); ok {
+		v = *vp
+	}
+	switch n := v.(type) {
+	case int64:
+		return n
+	case int:
+		return int64(n)
+	case float64:
+		return int64(n)
+	}
+	return 0
+}
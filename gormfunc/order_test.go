@@ -0,0 +1,46 @@
+package gormfunc
+
+import (
+	"gotulua/statefunc"
+	"slices"
+	"testing"
+)
+
+// orderValues returns the order field of the rows found, in the order Find returns them
+func orderValues(t *testing.T, table *Table) []int64 {
+	t.Helper()
+	if !table.Find() {
+		t.Fatalf("no rows found: %s", statefunc.GetLastErrorText())
+	}
+	var values []int64
+	for _, r := range table.Rows.Rows {
+		values = append(values, recordInt(r, table.orderField))
+	}
+	return values
+}
+
+func TestInsertPutsRowsWithoutOrderValueAtTheEnd(t *testing.T) {
+	db := newTestDB(t)
+	table := newTestTable(t, db, "Items", "n::Name;t::Text;l::20|n::Pos;t::Integer")
+	if !table.SetOrderField("Pos") {
+		t.Fatal(statefunc.GetLastErrorText())
+	}
+
+	insert(t, table, map[string]interface{}{"Name": "a"})
+	insert(t, table, map[string]interface{}{"Name": "b", "Pos": int64(0)}) // As Lua passes an unset field
+	insert(t, table, map[string]interface{}{"Name": "c", "Pos": 0.0})
+	insert(t, table, map[string]interface{}{"Name": "d", "Pos": int64(0)})
+	insert(t, table, map[string]interface{}{"Name": "e", "Pos": int64(0)})
+	want := []int64{1, 2, 3, 4, 5}
+	if got := orderValues(t, table); !slices.Equal(got, want) {
+		t.Errorf("order values %v, want %v", got, want)
+	}
+
+	// A row with its own order value keeps it
+	insert(t, table, map[string]interface{}{"Name": "f", "Pos": int64(10)})
+	insert(t, table, map[string]interface{}{"Name": "g"})
+	want = []int64{1, 2, 3, 4, 5, 10, 11}
+	if got := orderValues(t, table); !slices.Equal(got, want) {
+		t.Errorf("order values %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,48 @@
+package gormfunc
+
+import (
+	"gorm.io/gorm"
+)
+
+// Query runs a SQL query on the database, in its open transaction if there is one, and returns
+// its rows. The arguments are bound to the ? placeholders of the query. Values come as they are
+// stored: dates in the internal format and booleans as 1 and 0. NULL values are nil.
+func Query(db *gorm.DB, query string, args ...interface{}) ([]Record, error) {
+	rows, err := dbConn(db).Raw(query, args...).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, len(columns))
+	scanArgs := make([]interface{}, len(columns))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+	records := []Record{}
+	for rows.Next() {
+		if err := rows.Scan(scanArgs...); err != nil {
+			return nil, err
+		}
+		row := make(Record, len(columns))
+		for i, col := range columns {
+			if b, ok := values[i].([]byte); ok {
+				row[col] = string(b)
+			} else {
+				row[col] = values[i]
+			}
+		}
+		records = append(records, row)
+	}
+	return records, rows.Err()
+}
+
+// Exec runs a SQL statement on the database, in its open transaction if there is one,
+// and returns the number of rows it changed. The arguments are bound to the ? placeholders.
+func Exec(db *gorm.DB, statement string, args ...interface{}) (int64, error) {
+	result := dbConn(db).Exec(statement, args...)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,46 @@
+package gormfunc
+
+import (
+	"errors"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// openDBs keeps the open database connections, so they can be closed when the application exits
+var (
+	openDBsMu sync.Mutex
+	openDBs   = map[*gorm.DB]bool{}
+)
+
+// registerDB adds the database to the open connections
+func registerDB(db *gorm.DB) {
+	openDBsMu.Lock()
+	defer openDBsMu.Unlock()
+	openDBs[db] = true
+}
+
+// unregisterDB removes the database from the open connections
+func unregisterDB(db *gorm.DB) {
+	openDBsMu.Lock()
+	defer openDBsMu.Unlock()
+	delete(openDBs, db)
+}
+
+// CloseAllDBs closes all open database connections. A transaction left open is rolled back,
+// as its changes were never committed by the script. Returns the errors of all connections.
+func CloseAllDBs() error {
+	openDBsMu.Lock()
+	dbs := make([]*gorm.DB, 0, len(openDBs))
+	for db := range openDBs {
+		dbs = append(dbs, db)
+	}
+	openDBsMu.Unlock()
+	var errs []error
+	for _, db := range dbs {
+		if err := CloseDB(db); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
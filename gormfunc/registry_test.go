@@ -0,0 +1,40 @@
+package gormfunc
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCloseAllDBsClosesOpenDatabasesAndRollsBack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.db")
+	db, err := CreateDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := newTestTable(t, db, "Names", "n::Name;t::Text;l::20")
+	insertNames(t, table, "kept")
+	if err := BeginTransaction(db); err != nil {
+		t.Fatal(err)
+	}
+	insertNames(t, table, "open")
+
+	if err := CloseAllDBs(); err != nil {
+		t.Fatal(err)
+	}
+	if sqlDB, _ := db.DB(); sqlDB.Ping() == nil {
+		t.Error("the database is still open")
+	}
+	if InTransaction(db) {
+		t.Error("the transaction is still registered")
+	}
+
+	reopened := OpenDB(path)
+	defer CloseDB(reopened)
+	var got []string
+	if err := reopened.Raw("SELECT Name FROM Names").Scan(&got).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0] != "kept" {
+		t.Errorf("the database holds %v, want only the committed row", got)
+	}
+}
@@ -0,0 +1,122 @@
+package gormfunc
+
+import (
+	"encoding/json"
+	"fmt"
+	"gotulua/i18nfunc"
+	"gotulua/statefunc"
+	"maps"
+	"math"
+)
+
+// SysFilterTable keeps the filters saved with SaveFilter, by table and name
+const SysFilterTable = "table_filters"
+
+// savedFilter is the filter state of a table as it is stored
+type savedFilter struct {
+	Fields     map[string]string `json:"fields,omitempty"`
+	Mode       string            `json:"mode,omitempty"`
+	Plain      string            `json:"plain,omitempty"`
+	RangeField string            `json:"range_field,omitempty"`
+	Range      []interface{}     `json:"range,omitempty"`
+}
+
+// createFilterTable creates the table of the saved filters if the database does not have it
+func createFilterTable(t *Table) bool {
+	query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS \"%s\" (table_name TEXT NOT NULL, name TEXT NOT NULL, filter TEXT NOT NULL, PRIMARY KEY (table_name, name))", SysFilterTable)
+	if err := t.conn().Exec(query).Error; err != nil {
+		statefunc.SetLastErrorText(err.Error())
+		return false
+	}
+	return true
+}
+
+// SaveFilter stores the field, range and plain filters of the table and its filter mode under
+// the name, replacing a filter saved before with the same name. LoadFilter sets them again,
+// also on the table opened in another session. Returns false on errors, which are kept as
+// the last error text.
+func (t *Table) SaveFilter(name string) bool {
+	statefunc.ClearErrors()
+	f := savedFilter{
+		Fields: t.filteredFields,
+		Mode:   t.filterMode,
+		Plain:  t.plainFilter,
+	}
+	if len(t.rangeFilter) == 2 {
+		f.RangeField = t.filterByField
+		f.Range = t.rangeFilter
+	}
+	data, err := json.Marshal(f)
+	if err != nil {
+		statefunc.SetLastErrorText(err.Error())
+		return false
+	}
+	if !createFilterTable(t) {
+		return false
+	}
+	query := fmt.Sprintf("INSERT OR REPLACE INTO \"%s\" (table_name, name, filter) VALUES (?, ?, ?)", SysFilterTable)
+	if err := t.conn().Exec(query, t.Name, name, string(data)).Error; err != nil {
+		statefunc.SetLastErrorText(err.Error())
+		return false
+	}
+	return true
+}
+
+// LoadFilter replaces the filters of the table with the ones saved under the name.
+// Call Find to load the rows they select. Returns false if there is no such filter
+// or on errors, which are kept as the last error text.
+func (t *Table) LoadFilter(name string) bool {
+	statefunc.ClearErrors()
+	if !createFilterTable(t) {
+		return false
+	}
+	var data []string
+	query := fmt.Sprintf("SELECT filter FROM \"%s\" WHERE table_name = ? AND name = ?", SysFilterTable)
+	if err := t.conn().Raw(query, t.Name, name).Scan(&data).Error; err != nil {
+		statefunc.SetLastErrorText(err.Error())
+		return false
+	}
+	if len(data) == 0 {
+		statefunc.SetLastErrorText(i18nfunc.T("error.db_filter_not_found", map[string]interface{}{
+			"Table": t.Name,
+			"Name":  name,
+		}))
+		return false
+	}
+	var f savedFilter
+	if err := json.Unmarshal([]byte(data[0]), &f); err != nil {
+		statefunc.SetLastErrorText(err.Error())
+		return false
+	}
+	t.filteredFields = make(map[string]string)
+	maps.Copy(t.filteredFields, f.Fields)
+	t.filterMode = f.Mode
+	t.plainFilter = f.Plain
+	t.filterByField = f.RangeField
+	t.rangeFilter = []interface{}{}
+	if len(f.Range) == 2 {
+		// JSON numbers come back as float64, whole ones are the integers that were saved
+		for _, v := range f.Range {
+			if n, ok := v.(float64); ok && n == math.Trunc(n) && math.Abs(n) < 1<<53 {
+				v = int64(n)
+			}
+			t.rangeFilter = append(t.rangeFilter, v)
+		}
+	}
+	return true
+}
+
+// ListFilters returns the names of the filters saved for the table in alphabetical order.
+// ok is false on errors, which are kept as the last error text.
+func (t *Table) ListFilters() (names []string, ok bool) {
+	statefunc.ClearErrors()
+	if !createFilterTable(t) {
+		return nil, false
+	}
+	query := fmt.Sprintf("SELECT name FROM \"%s\" WHERE table_name = ? ORDER BY name", SysFilterTable)
+	if err := t.conn().Raw(query, t.Name).Scan(&names).Error; err != nil {
+		statefunc.SetLastErrorText(err.Error())
+		return nil, false
+	}
+	return names, true
+}
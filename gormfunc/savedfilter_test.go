@@ -0,0 +1,52 @@
+package gormfunc
+
+import (
+	"gotulua/statefunc"
+	"reflect"
+	"testing"
+)
+
+func TestSaveFilterAndLoadFilter(t *testing.T) {
+	db := newTestDB(t)
+	table := newTestTable(t, db, "Items", "n::Name;t::Text;l::20|n::Qty;t::Integer")
+	for i, name := range []string{"a", "b", "c", "d", "e"} {
+		insert(t, table, map[string]interface{}{"Name": name, "Qty": int64(i + 1)})
+	}
+
+	table.SetRangeFilter("Qty", 2, 4)
+	table.SetFilterMode("OR")
+	if !table.SaveFilter("middle") {
+		t.Fatal(statefunc.GetLastErrorText())
+	}
+	table.SetRangeFilter("Qty", 5, 5)
+	if !table.SaveFilter("last") {
+		t.Fatal(statefunc.GetLastErrorText())
+	}
+
+	// Another session opens the table without filters
+	other := OpenTable(db, "Items")
+	other.OrderBy("Name")
+	if !other.LoadFilter("middle") {
+		t.Fatal(statefunc.GetLastErrorText())
+	}
+	if !other.Find() {
+		t.Fatal(statefunc.GetLastErrorText())
+	}
+	if got := names(other); !reflect.DeepEqual(got, []string{"b", "c", "d"}) {
+		t.Errorf("rows of the loaded filter %v, want [b c d]", got)
+	}
+	if !reflect.DeepEqual(other.rangeFilter, []interface{}{int64(2), int64(4)}) || other.filterMode != "OR" {
+		t.Errorf("loaded range %#v and mode %q, want the integers 2 and 4 and OR", other.rangeFilter, other.filterMode)
+	}
+
+	list, ok := other.ListFilters()
+	if !ok || !reflect.DeepEqual(list, []string{"last", "middle"}) {
+		t.Errorf("saved filters %v, ok %v, want [last middle]", list, ok)
+	}
+	if other.LoadFilter("missing") || statefunc.GetLastErrorText() == "" {
+		t.Error("loading a filter that was not saved did not fail with an error")
+	}
+	if userNames, _ := userTables(db); !reflect.DeepEqual(userNames, []string{"Items"}) {
+		t.Errorf("user tables %v, want the filter table left out", userNames)
+	}
+}
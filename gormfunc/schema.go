@@ -0,0 +1,149 @@
+package gormfunc
+
+import (
+	"errors"
+	"gotulua/i18nfunc"
+	"gotulua/typesfunc"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ExportSchema describes the user tables of the database in the structure format
+// of CreateTable. Every table takes one line: "name=n::Field;t::Type|...".
+func ExportSchema(db *gorm.DB) (string, error) {
+	names, err := userTables(db)
+	if err != nil {
+		return "", err
+	}
+	var lines []string
+	for _, name := range names {
+		structure, err := tableStructure(db, name)
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, name+"="+structure)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// userTables returns the names of the tables made by scripts, leaving out the system tables
+func userTables(db *gorm.DB) ([]string, error) {
+	var names []string
+	err := db.Raw("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name NOT IN (?, ?, ?) ORDER BY name", SysMetaTable, SysSequenceTable, SysFilterTable).Scan(&names).Error
+	return names, err
+}
+
+// TableExists reports whether the database has a table with the name
+func TableExists(db *gorm.DB, name string) bool {
+	return tableExists(db, name)
+}
+
+// ColumnExists reports whether the table has a column with the name, false if there is no such table
+func ColumnExists(db *gorm.DB, table, column string) bool {
+	var count int64
+	db.Raw("SELECT count(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&count)
+	return count > 0
+}
+
+// textLength matches the length of a TEXT(n) column type
+var textLength = regexp.MustCompile(`^TEXT\s*\((\d+)\)$`)
+
+// tableStructure returns the CreateTable structure of the table
+func tableStructure(db *gorm.DB, name string) (string, error) {
+	var metadata []TableMetadata
+	if err := db.Where("table_name = ?", name).Find(&metadata).Error; err != nil {
+		return "", err
+	}
+	logicalTypes := make(map[string]string)
+	for _, m := range metadata {
+		logicalTypes[m.FieldName] = m.LogicalType
+	}
+	references, err := foreignKeys(db, name)
+	if err != nil {
+		return "", err
+	}
+	rows, err := db.Raw("PRAGMA table_info(" + name + ")").Rows()
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	var fields []string
+	for rows.Next() {
+		var cid int
+		var colName, colType string
+		var notnull, pk int
+		var dfltValue interface{}
+		if err := rows.Scan(&cid, &colName, &colType, &notnull, &dfltValue, &pk); err != nil {
+			return "", err
+		}
+		if colName == PrimaryKeyField {
+			continue
+		}
+		field := "n::" + colName + ";t::"
+		colType = strings.ToUpper(colType)
+		switch logicalTypes[colName] {
+		case typesfunc.TypeBoolean:
+			field += "Boolean"
+		case typesfunc.TypeDate:
+			field += "Date"
+		case typesfunc.TypeTime:
+			field += "Time"
+		case typesfunc.TypeDateTime:
+			field += "DateTime"
+		default:
+			switch {
+			case strings.HasPrefix(colType, "INT"):
+				field += "Integer"
+			case strings.HasPrefix(colType, "REAL"), strings.HasPrefix(colType, "FLOAT"), strings.HasPrefix(colType, "DOUBLE"):
+				field += "Float"
+			case strings.HasPrefix(colType, "TEXT"), strings.HasPrefix(colType, "CHAR"), strings.HasPrefix(colType, "VARCHAR"):
+				field += "Text"
+				if m := textLength.FindStringSubmatch(colType); m != nil {
+					field += ";l::" + m[1]
+				}
+			default:
+				return "", errors.New(i18nfunc.T("error.db_schema_unsupported_type", map[string]interface{}{
+					"Table": name,
+					"Field": colName,
+					"Type":  colType,
+				}))
+			}
+		}
+		if ref, ok := references[colName]; ok {
+			field += ";" + ref
+		}
+		fields = append(fields, field)
+	}
+	return strings.Join(fields, "|"), nil
+}
+
+// ImportSchema creates the tables of a schema made by ExportSchema that do not
+// exist in the database yet. Returns the number of created tables.
+func ImportSchema(db *gorm.DB, schema string) (int, error) {
+	created := 0
+	for _, line := range strings.Split(schema, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, structure, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return created, errors.New(i18nfunc.T("error.db_schema_invalid_line", map[string]interface{}{
+				"Line": line,
+			}))
+		}
+		if tableExists(db, name) {
+			continue
+		}
+		if CreateTable(db, name, structure, false, false) == nil {
+			return created, errors.New(i18nfunc.T("error.db_table_create_failed", map[string]interface{}{
+				"Name": name,
+			}))
+		}
+		created++
+	}
+	return created, nil
+}
@@ -0,0 +1,57 @@
+package gormfunc
+
+import "testing"
+
+func TestImportedSchemaReproducesTheTables(t *testing.T) {
+	db := newTestDB(t)
+	newTestTable(t, db, "People", "n::Name;t::Text;l::40|n::Born;t::Date|n::Active;t::Boolean")
+	newTestTable(t, db, "Orders", "n::Total;t::Float|n::Items;t::Integer|n::At;t::DateTime")
+	schema, err := ExportSchema(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Orders=n::Total;t::Float|n::Items;t::Integer|n::At;t::DateTime\n" +
+		"People=n::Name;t::Text;l::40|n::Born;t::Date|n::Active;t::Boolean"
+	if schema != want {
+		t.Errorf("ExportSchema returned\n%s\nwant\n%s", schema, want)
+	}
+
+	fresh := newTestDB(t)
+	newTestTable(t, fresh, "People", "n::Name;t::Text;l::40") // Kept as it is
+	created, err := ImportSchema(fresh, schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created != 1 {
+		t.Errorf("ImportSchema created %d tables, want only the missing one", created)
+	}
+	structure, err := tableStructure(fresh, "Orders")
+	if err != nil || structure != "n::Total;t::Float|n::Items;t::Integer|n::At;t::DateTime" {
+		t.Errorf("the imported Orders table has the structure %q: %v", structure, err)
+	}
+	if _, err := ImportSchema(fresh, "no structure"); err == nil {
+		t.Error("a line without a table name was imported")
+	}
+}
+
+func TestTableAndColumnExists(t *testing.T) {
+	db := newTestDB(t)
+	newTestTable(t, db, "People", "n::Name;t::Text;l::40")
+	tests := []struct {
+		table, column         string
+		tableWant, columnWant bool
+	}{
+		{"People", "Name", true, true},
+		{"People", "id", true, true},
+		{"People", "Age", true, false},
+		{"Nobody", "Name", false, false},
+	}
+	for _, tt := range tests {
+		if got := TableExists(db, tt.table); got != tt.tableWant {
+			t.Errorf("TableExists(%q) = %v, want %v", tt.table, got, tt.tableWant)
+		}
+		if got := ColumnExists(db, tt.table, tt.column); got != tt.columnWant {
+			t.Errorf("ColumnExists(%q, %q) = %v, want %v", tt.table, tt.column, got, tt.columnWant)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package gormfunc
+
+import (
+	"fmt"
+	"gotulua/i18nfunc"
+	"gotulua/statefunc"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// SysSequenceTable keeps the named counters of NextSequence
+const SysSequenceTable = "table_sequences"
+
+// sequenceBusyTimeout is how long, in milliseconds, a session waits for another one
+// that is taking a number of the same database
+const sequenceBusyTimeout = 5000
+
+// sequenceMu lets one goroutine at a time take a number on a connection of its own.
+// The connections of a memory database share its cache, where a locked table is not
+// waited for like a busy database file.
+var sequenceMu sync.Mutex
+
+// NextSequence returns the next number of the named counter and stores it.
+// Counters are created on first use and start at 1. Inside a transaction the script opened
+// the number is taken in it and kept only if the script commits. Otherwise it is taken in a
+// transaction of its own that begins with the write lock, so sessions sharing the database
+// never get the same number. ok is false on errors, which are kept as the last error text.
+func NextSequence(db *gorm.DB, name string) (value int64, ok bool) {
+	statefunc.ClearErrors()
+	var err error
+	if tx, open := openTransaction(db); open {
+		value, err = takeSequence(tx, name)
+	} else {
+		value, err = takeSequenceAlone(db, name)
+	}
+	if err != nil {
+		statefunc.SetLastErrorText(err.Error())
+		return 0, false
+	}
+	return value, true
+}
+
+// takeSequenceAlone takes the number in a transaction of its own on a connection of its own.
+// BEGIN IMMEDIATE takes the write lock before the counter is read, so a session of another
+// process waits for it up to the busy timeout instead of reading the same value.
+func takeSequenceAlone(db *gorm.DB, name string) (value int64, err error) {
+	sequenceMu.Lock()
+	defer sequenceMu.Unlock()
+	err = db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec(fmt.Sprintf("PRAGMA busy_timeout = %d", sequenceBusyTimeout)).Error; err != nil {
+			return err
+		}
+		if err := conn.Exec("BEGIN IMMEDIATE").Error; err != nil {
+			return err
+		}
+		v, err := takeSequence(conn, name)
+		if err == nil {
+			err = conn.Exec("COMMIT").Error
+		}
+		if err != nil {
+			conn.Exec("ROLLBACK")
+			return err
+		}
+		value = v
+		return nil
+	})
+	return value, err
+}
+
+// takeSequence increments the counter on the connection and returns its new value
+func takeSequence(conn *gorm.DB, name string) (value int64, err error) {
+	statements := []struct {
+		sql  string
+		args []interface{}
+	}{
+		{fmt.Sprintf("CREATE TABLE IF NOT EXISTS \"%s\" (name TEXT PRIMARY KEY, value INTEGER NOT NULL)", SysSequenceTable), nil},
+		{fmt.Sprintf("INSERT OR IGNORE INTO \"%s\" (name, value) VALUES (?, 0)", SysSequenceTable), []interface{}{name}},
+		{fmt.Sprintf("UPDATE \"%s\" SET value = value + 1 WHERE name = ?", SysSequenceTable), []interface{}{name}},
+	}
+	for _, s := range statements {
+		if err := conn.Exec(s.sql, s.args...).Error; err != nil {
+			return 0, err
+		}
+	}
+	query := fmt.Sprintf("SELECT value FROM \"%s\" WHERE name = ?", SysSequenceTable)
+	err = conn.Raw(query, name).Scan(&value).Error
+	return value, err
+}
+
+// ResetSequence sets the autoincrement sequence of the table so the next inserted row gets
+// the id next, e.g. 1 after the table was cleared. The table must have an AUTOINCREMENT key,
+// and next must be above the largest id in the table. Returns false on errors, which are
+// kept as the last error text.
+func ResetSequence(db *gorm.DB, table string, next int64) bool {
+	statefunc.ClearErrors()
+	var ddl string
+	if err := db.Raw("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&ddl).Error; err != nil {
+		statefunc.SetLastErrorText(err.Error())
+		return false
+	}
+	if ddl == "" {
+		statefunc.SetLastErrorText(i18nfunc.T("error.table_not_exists", map[string]interface{}{
+			"Name": table,
+		}))
+		return false
+	}
+	if !strings.Contains(strings.ToUpper(ddl), "AUTOINCREMENT") {
+		statefunc.SetLastErrorText(i18nfunc.T("error.db_no_autoincrement", map[string]interface{}{
+			"Table": table,
+		}))
+		return false
+	}
+	return RunInTransaction(db, func() bool {
+		tx := dbConn(db)
+		var maxID int64
+		if err := tx.Raw(fmt.Sprintf("SELECT COALESCE(MAX(%s), 0) FROM \"%s\"", PrimaryKeyField, table)).Scan(&maxID).Error; err != nil {
+			statefunc.SetLastErrorText(err.Error())
+			return false
+		}
+		if next < 1 || next <= maxID {
+			statefunc.SetLastErrorText(i18nfunc.T("error.db_sequence_below_max", map[string]interface{}{
+				"Table": table,
+				"Value": next,
+				"Max":   maxID,
+			}))
+			return false
+		}
+		// SQLite gives the next row the stored value plus one
+		result := tx.Exec("UPDATE sqlite_sequence SET seq = ? WHERE name = ?", next-1, table)
+		if result.Error == nil && result.RowsAffected == 0 {
+			result = tx.Exec("INSERT INTO sqlite_sequence (name, seq) VALUES (?, ?)", table, next-1)
+		}
+		if result.Error != nil {
+			statefunc.SetLastErrorText(result.Error.Error())
+			return false
+		}
+		return true
+	})
+}
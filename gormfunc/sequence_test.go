@@ -0,0 +1,98 @@
+package gormfunc
+
+import (
+	"gotulua/statefunc"
+	"sync"
+	"testing"
+)
+
+func TestNextSequenceCountsEveryNameApart(t *testing.T) {
+	db := newTestDB(t)
+	for want := int64(1); want <= 3; want++ {
+		if got, ok := NextSequence(db, "invoice"); !ok || got != want {
+			t.Errorf("invoice number %d, ok %v, want %d", got, ok, want)
+		}
+	}
+	if got, ok := NextSequence(db, "order"); !ok || got != 1 {
+		t.Errorf("first order number %d, ok %v, want 1", got, ok)
+	}
+}
+
+func TestNextSequenceNeverRepeatsANumber(t *testing.T) {
+	db := newTestDB(t)
+	const takers, each = 4, 25
+	var mu sync.Mutex
+	seen := map[int64]bool{}
+	var wg sync.WaitGroup
+	for range takers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range each {
+				n, ok := NextSequence(db, "ticket")
+				mu.Lock()
+				if !ok || seen[n] {
+					t.Errorf("number %d, ok %v, taken twice or failed", n, ok)
+				}
+				seen[n] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(seen) != takers*each {
+		t.Errorf("%d numbers taken, want %d", len(seen), takers*each)
+	}
+}
+
+func TestNextSequenceJoinsTheScriptTransaction(t *testing.T) {
+	db := newTestDB(t)
+	if got, ok := NextSequence(db, "invoice"); !ok || got != 1 {
+		t.Fatalf("first invoice number %d, ok %v, want 1", got, ok)
+	}
+	if err := BeginTransaction(db); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := NextSequence(db, "invoice"); !ok || got != 2 {
+		t.Errorf("invoice number in the transaction %d, ok %v, want 2", got, ok)
+	}
+	if err := RollbackTransaction(db); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := NextSequence(db, "invoice"); !ok || got != 2 {
+		t.Errorf("invoice number after the rollback %d, ok %v, want 2 again", got, ok)
+	}
+}
+
+func TestResetSequenceSetsTheNextID(t *testing.T) {
+	db := newTestDB(t)
+	table := newTestTable(t, db, "Names", "n::Name;t::Text;l::20")
+	insertNames(t, table, "a", "b", "c")
+	if _, err := Exec(db, "DELETE FROM Names"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !ResetSequence(db, "Names", 1) {
+		t.Fatal(statefunc.GetLastErrorText())
+	}
+	table.Find()
+	if id := insert(t, table, map[string]interface{}{"Name": "d"}); id != 1 {
+		t.Errorf("the first row after the reset got id %d, want 1", id)
+	}
+	if !ResetSequence(db, "Names", 100) {
+		t.Fatal(statefunc.GetLastErrorText())
+	}
+	if id := insert(t, table, map[string]interface{}{"Name": "e"}); id != 100 {
+		t.Errorf("the row got id %d, want the configured 100", id)
+	}
+
+	if ResetSequence(db, "Names", 50) {
+		t.Error("the sequence was set below the largest id")
+	}
+	if _, err := Exec(db, "CREATE TABLE plain (id INTEGER PRIMARY KEY, a TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+	if ResetSequence(db, "plain", 1) {
+		t.Error("the sequence of a table without AUTOINCREMENT was set")
+	}
+}
@@ -0,0 +1,72 @@
+package gormfunc
+
+import (
+	"fmt"
+	"gotulua/i18nfunc"
+	"gotulua/statefunc"
+	"gotulua/typesfunc"
+	"time"
+)
+
+// DeletedAtField is the field set to the time of a soft delete, when the table has it
+const DeletedAtField = "deleted_at"
+
+// SetSoftDelete makes deletes set the flag field to 1 instead of removing the row.
+// Find leaves out the flagged rows unless IncludeDeleted is set. An empty field turns
+// soft delete off. Returns false if the table has no such field.
+func (t *Table) SetSoftDelete(flagField string) bool {
+	statefunc.ClearErrors()
+	if flagField != "" {
+		if _, ok := t.fieldTypes[flagField]; !ok {
+			statefunc.SetLastErrorText(i18nfunc.T("error.db_field_not_found", map[string]interface{}{
+				"Field": flagField,
+				"Table": t.Name,
+			}))
+			return false
+		}
+	}
+	t.softDeleteField = flagField
+	return true
+}
+
+// IncludeDeleted sets whether Find returns the soft deleted rows too
+func (t *Table) IncludeDeleted(include bool) {
+	t.includeDeleted = include
+}
+
+// softDeleteClause returns the condition leaving out the soft deleted rows, "" if there is none
+func (t *Table) softDeleteClause() string {
+	if t.softDeleteField == "" || t.includeDeleted {
+		return ""
+	}
+	return fmt.Sprintf("(\"%s\" IS NULL OR \"%s\" <> 1)", t.softDeleteField, t.softDeleteField)
+}
+
+// softDelete flags the row as deleted and sets its deleted_at field if the table has one
+func (t *Table) softDelete(id interface{}) error {
+	query, args := t.softDeleteStatement()
+	query += " WHERE ID = ?"
+	return t.conn().Exec(query, append(args, id)...).Error
+}
+
+// softDeleteStatement returns the UPDATE flagging rows as deleted, without its WHERE clause, and its arguments
+func (t *Table) softDeleteStatement() (string, []interface{}) {
+	query := fmt.Sprintf("UPDATE %s SET \"%s\" = 1", t.Name, t.softDeleteField)
+	args := []interface{}{}
+	if ft, ok := t.fieldTypes[DeletedAtField]; ok {
+		query += fmt.Sprintf(", \"%s\" = ?", DeletedAtField)
+		args = append(args, deletedAtValue(ft, time.Now()))
+	}
+	return query, args
+}
+
+// deletedAtValue returns the time stored in a deleted_at field of the type
+func deletedAtValue(fieldType string, now time.Time) string {
+	switch fieldType {
+	case typesfunc.TypeDate:
+		return now.Format("20060102")
+	case typesfunc.TypeDateTime:
+		return now.Format("20060102150405")
+	}
+	return now.Format("2006-01-02 15:04:05")
+}
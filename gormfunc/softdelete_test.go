@@ -0,0 +1,50 @@
+package gormfunc
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSoftDeleteHidesTheRowAndSetsTheFlag(t *testing.T) {
+	db := newTestDB(t)
+	table := newTestTable(t, db, "Items", "n::Name;t::Text;l::20|n::Deleted;t::Integer")
+	insertNames(t, table, "a", "b")
+	if !table.SetSoftDelete("Deleted") {
+		t.Fatal("SetSoftDelete failed")
+	}
+	table.Find()
+	if !table.DeleteRow() {
+		t.Fatal("DeleteRow failed")
+	}
+
+	table.Find()
+	if got := names(table); !slices.Equal(got, []string{"b"}) {
+		t.Errorf("Find returned %v, want [b]", got)
+	}
+	var flags []int
+	db.Raw("SELECT Deleted FROM Items ORDER BY ID").Scan(&flags)
+	if !slices.Equal(flags, []int{1, 0}) {
+		t.Errorf("the flags are %v, want [1 0]", flags)
+	}
+
+	table.IncludeDeleted(true)
+	table.Find()
+	if got := names(table); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("Find with IncludeDeleted returned %v, want [a b]", got)
+	}
+}
+
+func TestSoftDeleteAppliesToAnOrPlainFilter(t *testing.T) {
+	db := newTestDB(t)
+	table := newTestTable(t, db, "Items", "n::Name;t::Text;l::20|n::Deleted;t::Integer")
+	insertNames(t, table, "a", "b", "c")
+	table.SetSoftDelete("Deleted")
+	table.Find()
+	table.DeleteRow()
+
+	table.plainFilter = "Name = 'a' OR Name = 'b'"
+	table.Find()
+	if got := names(table); !slices.Equal(got, []string{"b"}) {
+		t.Errorf("Find returned %v, want [b] without the soft deleted row", got)
+	}
+}
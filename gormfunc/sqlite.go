@@ -11,9 +11,12 @@ import (
 	"gotulua/timefunc"
 	"gotulua/typesfunc"
 	"log"
+	"maps"
 	"os"
 	"reflect"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/Shopify/go-lua"
@@ -54,12 +57,26 @@ type Table struct {
 	orderBy            string
 	defaultFieldValues map[string]interface{}
 	filteredFields     map[string]string
+	filterMode         string            // AND or OR, how the SetFilter conditions of the fields combine; "" means AND
 	fieldTypes         map[string]string // Maps field names to their types
 	Rows               *Rowset
 	XRecord            Record
 	OnAfterInsert      string
 	OnAfterUpdate      string
 	OnAfterDelete      string
+	OnValidate         string
+	lastInsertID       int64             // Primary key of the last row inserted through this table
+	readTransforms     map[string]string // Lua functions applied to field values on read
+	writeTransforms    map[string]string // Lua functions applied to field values on write
+	collations         map[string]string // Collations used to compare and order text fields
+	fieldCaptions      map[string]string // Captions used to name the fields in messages
+	readOnly           bool              // Set for views, which refuse inserts, updates and deletes
+	limit              int               // Maximum number of rows returned by Find, 0 if unset
+	offset             int               // Number of rows skipped by Find, 0 if unset
+	hasMore            bool              // Find stopped at the limit and more rows follow
+	softDeleteField    string            // Field flagging the soft deleted rows, "" if rows are deleted
+	includeDeleted     bool              // Set when Find returns the soft deleted rows too
+	orderField         string            // Integer field keeping the order of the rows set by the user, "" if none
 }
 
 // TableWrapper wraps a gormfunc.Table for Lua
@@ -84,10 +101,14 @@ type TableMetadataWrapper struct {
 	Drop bool
 }
 
-// CreateDB creates a new SQLite database with system metadata table
+// CreateDB creates a new SQLite database with system metadata table.
+// The path MemoryDB creates a database kept in memory.
 func CreateDB(dbPath string) (*gorm.DB, error) {
-	// Check if file already exists
-	if _, err := os.Stat(dbPath); err == nil {
+	dsn := dbPath
+	if dbPath == MemoryDB {
+		dsn = memoryDSN()
+	} else if _, err := os.Stat(dbPath); err == nil {
+		// Check if file already exists
 		return OpenDB(dbPath), nil
 	}
 
@@ -95,7 +116,7 @@ func CreateDB(dbPath string) (*gorm.DB, error) {
 		Logger: logger.Default.LogMode(logger.Silent),
 	}
 	// Create database connection which will create the file
-	db, err := gorm.Open(sqlite.Open(dbPath), gormConfig)
+	db, err := gorm.Open(sqlite.Open(connDSN(dsn)), gormConfig)
 	if err != nil {
 		return nil, errors.New(i18nfunc.T("error.db_create_failed", nil))
 	}
@@ -106,26 +127,43 @@ func CreateDB(dbPath string) (*gorm.DB, error) {
 		return nil, errors.New(i18nfunc.T("error.db_metadata_create_failed", nil))
 	}
 	clearTempMeta(db)
+	registerDB(db)
 	return db, nil
 }
 
-// OpenDB initializes and returns a GORM DB connection
+// OpenDB initializes and returns a GORM DB connection.
+// The path MemoryDB opens a new database kept in memory.
 func OpenDB(dbName string) *gorm.DB {
+	if dbName == MemoryDB {
+		// A memory database starts empty, it needs the metadata table like a created one
+		db, err := CreateDB(dbName)
+		if err != nil {
+			log.Fatal(i18nfunc.T("error.db_open_failed", map[string]interface{}{
+				"Name": dbName,
+			}))
+		}
+		return db
+	}
 	gormConfig := &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Silent),
 	}
-	db, err := gorm.Open(sqlite.Open(dbName), gormConfig)
+	db, err := gorm.Open(sqlite.Open(connDSN(dbName)), gormConfig)
 	if err != nil {
 		log.Fatal(i18nfunc.T("error.db_open_failed", map[string]interface{}{
 			"Name": dbName,
 		}))
 	}
 	clearTempMeta(db)
+	registerDB(db)
 	return db
 }
 
-// CloseDB closes the database connection
+// CloseDB closes the database connection, rolling back a transaction left open
 func CloseDB(db *gorm.DB) error {
+	if InTransaction(db) {
+		RollbackTransaction(db)
+	}
+	unregisterDB(db)
 	sqlDB, err := db.DB()
 	if err != nil {
 		return err
@@ -141,6 +179,8 @@ func clearTempMeta(db *gorm.DB) error {
 // CreateTable creates a new table in the database with the specified name and description.
 // The description is a string describing the fields, using the format "n::FieldName;t::FieldType;l::Length|..."
 // For example: "n::Name;t::Text;l::100|n::Age;t::Integer"
+// A field declared with "fk::refTable.refField" references a row of another table, it is empty by default.
+// "fkdel::Restrict|Cascade|SetNull" sets what deleting the referenced row does.
 // If a table with the given name already exists and openIfExists is true, it opens and returns the table.
 // Otherwise, it throws an error if the table exists and openIfExists is false.
 // The function also stores metadata for special field types (Boolean, Date, Time, DateTime).
@@ -175,7 +215,7 @@ func CreateTable(db *gorm.DB, name, structure string, openIfExists bool, tempora
 
 	for _, field := range fields {
 		parts := strings.Split(field, ";")
-		var fieldName, fieldType, fieldLength string
+		var fieldName, fieldType, fieldLength, fieldRef, fieldOnDelete string
 		for _, part := range parts {
 			params := strings.Split(part, "::")
 			if len(params) == 2 {
@@ -186,11 +226,16 @@ func CreateTable(db *gorm.DB, name, structure string, openIfExists bool, tempora
 					fieldType = params[1]
 				case "l":
 					fieldLength = params[1]
+				case "fk":
+					fieldRef = params[1]
+				case "fkdel":
+					fieldOnDelete = params[1]
 				}
 			}
 		}
 		if fieldName != "" {
 			var actualType, logicalType, defaultValue string
+			colStart := len(createTable)
 			switch fieldType {
 			case "Text":
 				actualType = "TEXT"
@@ -235,6 +280,16 @@ func CreateTable(db *gorm.DB, name, structure string, openIfExists bool, tempora
 				}), errorhandlefunc.ErrorTypeScript, true)
 				return nil
 			}
+			if fieldRef != "" {
+				references, ok := referencesClause(fieldName, fieldRef, fieldOnDelete)
+				if !ok {
+					return nil
+				}
+				// A reference is NULL until it is set, a default value would point to no row
+				column, _, _ := strings.Cut(createTable[colStart:], " DEFAULT ")
+				createTable = createTable[:colStart] + column + " DEFAULT NULL" + references
+				defaultValue = ""
+			}
 
 			// Add field info to metadata
 			metadata = append(metadata, TableMetadata{
@@ -461,6 +516,7 @@ func OpenTable(db *gorm.DB, name string) *Table {
 		defaultFieldValues: make(map[string]interface{}),
 		fieldTypes:         make(map[string]string),
 		filteredFields:     make(map[string]string),
+		readOnly:           viewExists(db, name),
 	}
 	rows, err := db.Raw("PRAGMA table_info(" + name + ")").Rows()
 	if err != nil {
@@ -490,6 +546,69 @@ func OpenTable(db *gorm.DB, name string) *Table {
 	return &t
 }
 
+// SetFieldCaption sets the caption that names the field in error messages
+func (t *Table) SetFieldCaption(field, caption string) {
+	if t.fieldCaptions == nil {
+		t.fieldCaptions = make(map[string]string)
+	}
+	t.fieldCaptions[field] = caption
+}
+
+// fieldCaption returns the caption of the field, or its name if no caption is set
+func (t *Table) fieldCaption(field string) string {
+	if c := t.fieldCaptions[field]; c != "" {
+		return c
+	}
+	return field
+}
+
+// SetCollation sets the collation used for the text field in filters and ordering,
+// e.g. NOCASE to compare names regardless of case. An empty collation restores the default.
+func (t *Table) SetCollation(field, collation string) bool {
+	collation = strings.ToUpper(strings.TrimSpace(collation))
+	switch collation {
+	case "":
+		delete(t.collations, field)
+		return true
+	case "BINARY", "NOCASE", "RTRIM":
+	default:
+		errorhandlefunc.ThrowError(i18nfunc.T("error.db_invalid_collation", map[string]interface{}{
+			"Collation": collation,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return false
+	}
+	if t.collations == nil {
+		t.collations = make(map[string]string)
+	}
+	t.collations[field] = collation
+	return true
+}
+
+// collateClause returns the COLLATE clause for the field or "" if none is set
+func (t *Table) collateClause(field string) string {
+	if c, ok := t.collations[field]; ok {
+		return " COLLATE " + c
+	}
+	return ""
+}
+
+// orderByClause adds the field collations to the terms of the ORDER BY clause
+func (t *Table) orderByClause() string {
+	if len(t.collations) == 0 {
+		return t.orderBy
+	}
+	terms := strings.Split(t.orderBy, ",")
+	for i, term := range terms {
+		parts := strings.Fields(term)
+		if len(parts) == 0 || strings.Contains(strings.ToUpper(term), "COLLATE") {
+			continue
+		}
+		parts[0] += t.collateClause(strings.Trim(parts[0], "\"`"))
+		terms[i] = strings.Join(parts, " ")
+	}
+	return strings.Join(terms, ", ")
+}
+
 // SetFilter sets a filter for the table by field and plain filter string
 func (t *Table) SetFilter(field, filter string) *Table {
 	t.rangeFilter = []interface{}{}
@@ -500,6 +619,20 @@ func (t *Table) SetFilter(field, filter string) *Table {
 	return t
 }
 
+// SetFilterMode sets how the SetFilter conditions of different fields combine: AND or OR.
+// The plain, range and soft delete filters are always combined with AND.
+func (t *Table) SetFilterMode(mode string) bool {
+	mode = strings.ToUpper(strings.TrimSpace(mode))
+	if mode != "AND" && mode != "OR" {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.db_invalid_filter_mode", map[string]interface{}{
+			"Mode": mode,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return false
+	}
+	t.filterMode = mode
+	return true
+}
+
 // SetRangeFilter sets a range filter for the table
 func (t *Table) SetRangeFilter(field string, min, max interface{}) *Table {
 	t.rangeFilter = []interface{}{min, max}
@@ -514,13 +647,44 @@ func (t *Table) OrderBy(order string) *Table {
 	return t
 }
 
+// SetLimit sets the maximum number of rows Find returns, 0 or less removes the limit (chainable)
+func (t *Table) SetLimit(limit int) *Table {
+	t.limit = max(limit, 0)
+	return t
+}
+
+// SetOffset sets the number of rows Find skips, 0 or less removes the offset (chainable)
+func (t *Table) SetOffset(offset int) *Table {
+	t.offset = max(offset, 0)
+	return t
+}
+
+// limitClause returns the LIMIT and OFFSET of Find, SQLite needs a LIMIT for an OFFSET.
+// One row more than the limit is read, it tells whether another page follows.
+func (t *Table) limitClause() string {
+	switch {
+	case t.limit > 0 && t.offset > 0:
+		return fmt.Sprintf(" LIMIT %d OFFSET %d", t.limit+1, t.offset)
+	case t.limit > 0:
+		return fmt.Sprintf(" LIMIT %d", t.limit+1)
+	case t.offset > 0:
+		return fmt.Sprintf(" LIMIT -1 OFFSET %d", t.offset)
+	}
+	return ""
+}
+
 // Insert inserts a new record into the table using a map of field names to values
 func (t *Table) Insert(fields map[string]interface{}, id *int64) bool {
+	defer t.timeOperation("Insert")()
 	var cols []string
 	var placeholders []string
 	var vals []interface{}
 	statefunc.ClearErrors()
 	*id = 0
+	if t.refuseWrite() {
+		return false
+	}
+	candidate := make(Record)
 	for k, v := range t.defaultFieldValues {
 		if k != PrimaryKeyField {
 			value, exists := fields[k]
@@ -531,15 +695,30 @@ func (t *Table) Insert(fields map[string]interface{}, id *int64) bool {
 					return false
 				}
 			}
+			if k == t.orderField && (!exists || intValue(v) == 0 || intValue(v) == intValue(t.defaultFieldValues[k])) {
+				// A new row without its own order value goes after all others
+				next, err := t.nextOrderValue()
+				if err != nil {
+					statefunc.SetLastErrorText(err.Error())
+					return false
+				}
+				v = next
+			}
 			cols = append(cols, "\""+k+"\"")
 			placeholders = append(placeholders, "?")
 			vals = append(vals, v)
+			if t.OnValidate != "" {
+				candidate[k] = v
+			}
 		}
 	}
+	if t.OnValidate != "" && !t.runOnValidate(candidate) {
+		return false
+	}
 	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", t.Name, strings.Join(cols, ","), strings.Join(placeholders, ","))
-	result := t.db.Exec(query, vals...)
+	result := t.conn().Exec(query, vals...)
 	if result.Error != nil {
-		statefunc.SetLastErrorText(result.Error.Error())
+		statefunc.SetLastErrorText(t.writeErrorText(result.Error))
 		return false
 	}
 
@@ -548,7 +727,7 @@ func (t *Table) Insert(fields map[string]interface{}, id *int64) bool {
 		ID int64 `gorm:"column:id"`
 	}
 	var lastID LastID
-	if err := t.db.Raw("SELECT last_insert_rowid() as id").Scan(&lastID).Error; err != nil {
+	if err := t.conn().Raw("SELECT last_insert_rowid() as id").Scan(&lastID).Error; err != nil {
 		statefunc.SetLastErrorText(err.Error())
 		return false
 	}
@@ -566,18 +745,20 @@ func (t *Table) Insert(fields map[string]interface{}, id *int64) bool {
 		t.Rows.Pos = len(t.Rows.Rows) - 1
 	}
 	*id = lastID.ID
+	t.lastInsertID = lastID.ID
 	if t.OnAfterInsert != "" {
-		t.runOnAfterInsert()
+		t.afterCommit((*Table).runOnAfterInsert, r)
 	}
 	return true
 }
 
 // Update updates an existing record in the table by ID using a map of field names to values
 func (t *Table) Update(id int64, fields Record) bool {
+	defer t.timeOperation("Update")()
 	var setClauses []string
 	var vals []interface{}
 	statefunc.ClearErrors()
-	if id < 1 {
+	if id < 1 || t.refuseWrite() {
 		return false
 	}
 	if t.OnAfterUpdate != "" {
@@ -586,6 +767,13 @@ func (t *Table) Update(id int64, fields Record) bool {
 			return false
 		}
 	}
+	var candidate Record
+	if t.OnValidate != "" {
+		candidate = t.getRecordById(id)
+		if candidate == nil {
+			return false
+		}
+	}
 	for k, v := range fields {
 		setClauses = append(setClauses, fmt.Sprintf("%s = ?", "\""+k+"\""))
 		v, ok := t.fieldUserFormatToInternalFormat(k, v, "")
@@ -593,13 +781,18 @@ func (t *Table) Update(id int64, fields Record) bool {
 			return false
 		}
 		vals = append(vals, v)
+		if candidate != nil {
+			candidate[k] = v
+		}
+	}
+	if candidate != nil && !t.runOnValidate(candidate) {
+		return false
 	}
 	vals = append(vals, id)
 	query := fmt.Sprintf("UPDATE %s SET %s WHERE ID = ?", t.Name, strings.Join(setClauses, ", "))
-	result := t.db.Exec(query, vals...).Error == nil
-	if !result {
+	if err := t.conn().Exec(query, vals...).Error; err != nil {
 		t.XRecord = nil
-		statefunc.SetLastErrorText(t.db.Error.Error())
+		statefunc.SetLastErrorText(t.writeErrorText(err))
 		return false
 	}
 	r := t.getRecordById(id)
@@ -608,7 +801,7 @@ func (t *Table) Update(id int64, fields Record) bool {
 	}
 	t.Rows.Rows[t.Rows.Pos] = r
 	if t.OnAfterUpdate != "" {
-		t.runOnAfterUpdate()
+		t.afterCommit((*Table).runOnAfterUpdate, r)
 	}
 	return true
 }
@@ -616,17 +809,25 @@ func (t *Table) Update(id int64, fields Record) bool {
 // delete deletes a record by ID from the table
 func (t *Table) delete(id interface{}) bool {
 	statefunc.ClearErrors()
+	if t.refuseWrite() {
+		return false
+	}
 	if t.OnAfterDelete != "" {
 		t.XRecord = t.getRecordById(id)
 	}
-	result := t.db.Exec(fmt.Sprintf("DELETE FROM %s WHERE ID = ?", t.Name), id).Error == nil
-	if !result {
+	var err error
+	if t.softDeleteField != "" {
+		err = t.softDelete(id)
+	} else {
+		err = t.conn().Exec(fmt.Sprintf("DELETE FROM %s WHERE ID = ?", t.Name), id).Error
+	}
+	if err != nil {
 		t.XRecord = nil
-		statefunc.SetLastErrorText(t.db.Error.Error())
+		statefunc.SetLastErrorText(t.writeErrorText(err))
 		return false
 	}
 	if t.OnAfterDelete != "" {
-		t.runOnAfterDelete()
+		t.afterCommit((*Table).runOnAfterDelete, t.XRecord)
 	}
 	return true
 }
@@ -646,6 +847,140 @@ func (t *Table) SetOnAfterInsert(funcName string) {
 	t.OnAfterInsert = funcName
 }
 
+// LastInsertID returns the primary key of the last row inserted through the table, or 0
+func (t *Table) LastInsertID() int64 {
+	return t.lastInsertID
+}
+
+// SetOnValidate sets the function called before a record is inserted or updated.
+// The function gets the record to be written and aborts the operation by
+// returning false, optionally followed by a message.
+func (t *Table) SetOnValidate(funcName string) {
+	t.OnValidate = funcName
+}
+
+// SetReadTransform sets the Lua function applied to the field value every time it is read.
+// The function gets the value in user format and returns the value to use instead.
+func (t *Table) SetReadTransform(field, funcName string) {
+	if t.readTransforms == nil {
+		t.readTransforms = make(map[string]string)
+	}
+	if funcName == "" {
+		delete(t.readTransforms, field)
+		return
+	}
+	t.readTransforms[field] = funcName
+}
+
+// SetWriteTransform sets the Lua function applied to the field value before it is stored.
+// The function gets the value as it was set and returns the value to store instead.
+func (t *Table) SetWriteTransform(field, funcName string) {
+	if t.writeTransforms == nil {
+		t.writeTransforms = make(map[string]string)
+	}
+	if funcName == "" {
+		delete(t.writeTransforms, field)
+		return
+	}
+	t.writeTransforms[field] = funcName
+}
+
+// runTransform calls the Lua function funcName with the value and returns its result
+func runTransform(funcName string, value interface{}) interface{} {
+	statefunc.L.Global(funcName)
+	if !statefunc.L.IsFunction(-1) {
+		statefunc.L.Pop(1)
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_a_function", map[string]interface{}{
+			"Name": funcName,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return value
+	}
+	switch v := value.(type) {
+	case string:
+		statefunc.L.PushString(v)
+	case int:
+		statefunc.L.PushInteger(v)
+	case int64:
+		statefunc.L.PushInteger(int(v))
+	case float64:
+		statefunc.L.PushNumber(v)
+	case bool:
+		statefunc.L.PushBoolean(v)
+	case nil:
+		statefunc.L.PushNil()
+	default:
+		statefunc.L.PushString(fmt.Sprintf("%v", v))
+	}
+	statefunc.L.Call(1, 1)
+	defer statefunc.L.Pop(1)
+	switch statefunc.L.TypeOf(-1) {
+	case lua.TypeNumber:
+		n, _ := statefunc.L.ToNumber(-1)
+		if n == float64(int64(n)) {
+			return int64(n)
+		}
+		return n
+	case lua.TypeBoolean:
+		return statefunc.L.ToBoolean(-1)
+	case lua.TypeNil:
+		return nil
+	default:
+		str, _ := statefunc.L.ToString(-1)
+		return str
+	}
+}
+
+// runOnValidate calls the OnValidate function with the candidate record in internal format.
+// Returns false if the function rejected the record; the reason is kept as the last error.
+func (t *Table) runOnValidate(candidate Record) (valid bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			valid = false
+			errorhandlefunc.ThrowError(fmt.Sprintf("%v", r), errorhandlefunc.ErrorTypeScript, true)
+		}
+	}()
+	statefunc.L.Global(t.OnValidate)
+	if !statefunc.L.IsFunction(-1) {
+		statefunc.L.Pop(1)
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_a_function", map[string]interface{}{
+			"Name": t.OnValidate,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return false
+	}
+	// The record is passed as a table positioned on it, so fields read as usual
+	tValidate := Table{
+		Name:               t.Name,
+		Columns:            t.Columns,
+		defaultFieldValues: t.defaultFieldValues,
+		fieldTypes:         t.fieldTypes,
+		Rows:               &Rowset{Rows: []Record{candidate}, Pos: 0},
+	}
+	statefunc.L.PushUserData(&TableWrapper{Table: &tValidate})
+	statefunc.L.PushString("TableMT")
+	statefunc.L.RawGet(lua.RegistryIndex)
+	if statefunc.L.IsNil(-1) {
+		statefunc.L.Pop(3)
+		errorhandlefunc.ThrowError(i18nfunc.T("error.tablemt_metatable_not_found", map[string]interface{}{
+			"Name": t.OnValidate,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return false
+	}
+	statefunc.L.SetMetaTable(-2)
+	statefunc.L.Call(1, 2)
+	defer statefunc.L.Pop(2)
+	if !statefunc.L.IsBoolean(-2) || statefunc.L.ToBoolean(-2) {
+		return true
+	}
+	msg, ok := statefunc.L.ToString(-1)
+	if !ok || msg == "" {
+		msg = i18nfunc.T("error.db_record_invalid", map[string]interface{}{
+			"Table": t.Name,
+		})
+	}
+	statefunc.SetLastErrorText(msg)
+	return false
+}
+
 func (t *Table) runOnAfterInsert() {
 	defer func() {
 		if r := recover(); r != nil {
@@ -772,8 +1107,96 @@ func (t *Table) runOnAfterDelete() {
 	statefunc.L.Call(1, 0)
 }
 
+// Walk calls the Lua function funcName once for every row of the current filtered set.
+// Before each call the table is positioned on the row, so the function receives
+// the table and can read or change its fields. If the function returns true
+// the current record is written back with Update.
+// All updates are done in a single transaction: when any of them fails nothing is saved.
+// Returns the number of updated rows and true on success.
+func (t *Table) Walk(funcName string) (int, bool) {
+	statefunc.ClearErrors()
+	statefunc.L.Global(funcName)
+	if !statefunc.L.IsFunction(-1) {
+		statefunc.L.Pop(1)
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_a_function", map[string]interface{}{
+			"Name": funcName,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0, false
+	}
+	statefunc.L.Pop(1)
+	if !t.Find() {
+		return 0, statefunc.GetLastErrorText() == ""
+	}
+
+	updated := 0
+	result := RunInTransaction(t.db, func() bool {
+		for i := range t.Rows.Rows {
+			t.Rows.Pos = i
+			if ok, _ := t.runTableFunction(funcName); !ok {
+				continue
+			}
+			id, ok := t.Rows.Rows[i][PrimaryKeyField].(int64)
+			if !ok || !t.Update(id, t.Rows.Rows[i]) {
+				return false
+			}
+			updated++
+		}
+		return true
+	})
+	if !result {
+		return 0, false
+	}
+	t.ScrollToBeginning()
+	return updated, true
+}
+
+// Transaction calls the Lua function funcName inside a transaction, passing the table to it.
+// The transaction is committed when the function finishes and rolled back when
+// the function raises an error or returns false.
+// Returns true if the transaction was committed.
+func (t *Table) Transaction(funcName string) bool {
+	statefunc.ClearErrors()
+	statefunc.L.Global(funcName)
+	if !statefunc.L.IsFunction(-1) {
+		statefunc.L.Pop(1)
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_a_function", map[string]interface{}{
+			"Name": funcName,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return false
+	}
+	statefunc.L.Pop(1)
+	return RunInTransaction(t.db, func() bool {
+		result, returned := t.runTableFunction(funcName)
+		return result || !returned
+	})
+}
+
+// runTableFunction calls the Lua function funcName with the table as the only argument.
+// It returns the boolean value of the result and whether the function returned anything but nil.
+func (t *Table) runTableFunction(funcName string) (bool, bool) {
+	statefunc.L.Global(funcName)
+	wrapper := &TableWrapper{Table: t}
+	statefunc.L.PushUserData(wrapper)
+	statefunc.L.PushString("TableMT")
+	statefunc.L.RawGet(lua.RegistryIndex)
+	if statefunc.L.IsNil(-1) {
+		statefunc.L.Pop(3)
+		errorhandlefunc.ThrowError(i18nfunc.T("error.tablemt_metatable_not_found", map[string]interface{}{
+			"Name": funcName,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return false, false
+	}
+	statefunc.L.SetMetaTable(-2)
+	statefunc.L.Call(1, 1)
+	returned := !statefunc.L.IsNil(-1)
+	result := statefunc.L.ToBoolean(-1)
+	statefunc.L.Pop(1)
+	return result, returned
+}
+
 // FindByID retrieves a record by ID from the table
 func (t *Table) FindByID(id interface{}) bool {
+	defer t.timeOperation("FindByID")()
 	colStr := "*"
 	if len(t.Columns) > 0 {
 		var prep []string
@@ -786,7 +1209,7 @@ func (t *Table) FindByID(id interface{}) bool {
 	var rows []Record
 	var result = make(Record) //map[string]interface{}
 	var r2 = make(map[string]interface{})
-	err := t.db.Raw(query, id).Scan(&r2).Error
+	err := t.conn().Raw(query, id).Scan(&r2).Error
 	if err != nil {
 		return false
 	}
@@ -842,7 +1265,7 @@ func (t *Table) getRecordById(id interface{}) Record {
 	query := fmt.Sprintf("SELECT %s FROM %s WHERE ID = ?", colStr, t.Name)
 	var result = make(map[string]interface{})
 	var r2 = make(map[string]interface{})
-	tx := t.db.Raw(query, id)
+	tx := t.conn().Raw(query, id)
 	tx.Take(&r2)
 	if tx.Error != nil {
 		statefunc.SetLastErrorText(tx.Error.Error())
@@ -983,7 +1406,7 @@ func (t *Table) parseFilterByType(field, filter, fType string) string {
 				result += v
 			case typesfunc.TypeText:
 				v = strings.Trim(v, "'")
-				result += "'" + v + "'"
+				result += "'" + v + "'" + t.collateClause(field)
 			default:
 				result += "'" + v + "'"
 			}
@@ -1008,10 +1431,53 @@ func (t *Table) parseFilter(field string, filter string) string {
 	return t.parseFilterByType(field, filter, fType)
 }
 
+// whereClause builds the WHERE part of a query from the current filters.
+// Range filter values are passed as t.rangeFilter arguments. Soft deleted rows are left out.
+func (t *Table) whereClause() string {
+	query := ""
+	where := false
+	if len(t.plainFilter) > 0 {
+		query += " WHERE (" + t.plainFilter + ")"
+		where = true
+	} else if len(t.rangeFilter) == 2 {
+		query += fmt.Sprintf(" WHERE %s BETWEEN ? AND ?", t.filterByField)
+		where = true
+	}
+	conditions := []string{t.softDeleteClause()}
+	var fieldConditions []string
+	for k, v := range t.filteredFields {
+		if len(v) == 0 {
+			continue
+		}
+		if f := t.parseFilter(k, v); f != "" {
+			fieldConditions = append(fieldConditions, f)
+		}
+	}
+	if t.filterMode == "OR" && len(fieldConditions) > 1 {
+		conditions = append(conditions, "("+strings.Join(fieldConditions, " OR ")+")")
+	} else {
+		conditions = append(conditions, fieldConditions...)
+	}
+	for _, f := range conditions {
+		if f == "" {
+			continue
+		}
+		if !where {
+			query += " WHERE "
+			where = true
+		} else {
+			query += " AND "
+		}
+		query += f
+	}
+	return query
+}
+
 // Find retrieves all rows from the table based on current filters and ordering.
 //
 // This method executes a SELECT query on the table using any filters that have been
 // set via SetFilter or SetRangeFilter, and any ordering specified via OrderBy.
+// SetLimit and SetOffset return one page of the rows.
 //
 // Parameters:
 //   - None
@@ -1027,7 +1493,63 @@ func (t *Table) parseFilter(field string, filter string) string {
 //	  end
 //	end
 func (t *Table) Find() bool {
+	defer t.timeOperation("Find")()
 	statefunc.ClearErrors()
+	f := t.newRowFetch()
+	results, err := f.fetch(t.limitClause())
+	if err != nil {
+		statefunc.SetLastErrorText(err.Error())
+		return false
+	}
+
+	t.hasMore = t.limit > 0 && len(results) > t.limit
+	if t.hasMore {
+		results = results[:t.limit]
+	}
+	t.Rows = &Rowset{Rows: results, Pos: 0}
+	return len(t.Rows.Rows) > 0
+}
+
+// FindFirst is Find loading only the first n rows. The rows after them are read with
+// the returned RowFetch, which is nil if there are no more. A table limited with
+// SetLimit loads all its rows at once, as Find does.
+func (t *Table) FindFirst(n int) (*RowFetch, bool) {
+	// Inside a transaction all rows are read now, the transaction may be closed
+	// before the rest would be read
+	if t.limit > 0 || InTransaction(t.db) {
+		return nil, t.Find()
+	}
+	defer t.timeOperation("Find")()
+	statefunc.ClearErrors()
+	f := t.newRowFetch()
+	results, err := f.fetch(fmt.Sprintf(" LIMIT %d OFFSET %d", n+1, t.offset))
+	if err != nil {
+		statefunc.SetLastErrorText(err.Error())
+		return nil, false
+	}
+	t.hasMore = false
+	if len(results) > n {
+		results = results[:n]
+		f.offset = t.offset + n
+	} else {
+		f = nil
+	}
+	t.Rows = &Rowset{Rows: results, Pos: 0}
+	return f, len(t.Rows.Rows) > 0
+}
+
+// RowFetch reads the rows of a query of Find. It keeps what it needs from the table,
+// so Fetch can run on another goroutine while the table is used.
+type RowFetch struct {
+	db       *gorm.DB
+	query    string                 // The query without its LIMIT clause
+	args     []interface{}          // Arguments of the range filter
+	defaults map[string]interface{} // Values of the fields that are NULL
+	offset   int                    // Number of rows Fetch skips
+}
+
+// newRowFetch prepares the query Find runs for the current filters and order of the table
+func (t *Table) newRowFetch() *RowFetch {
 	colStr := "*"
 	if len(t.Columns) > 0 {
 		var prep []string
@@ -1036,53 +1558,41 @@ func (t *Table) Find() bool {
 		}
 		colStr = strings.Join(prep, ", ")
 	}
-	query := fmt.Sprintf("SELECT %s FROM %s", colStr, t.Name)
-	where := false
-	if len(t.plainFilter) > 0 {
-		query += " WHERE " + t.plainFilter
-		where = true
-	} else if len(t.rangeFilter) == 2 {
-		query += fmt.Sprintf(" WHERE %s BETWEEN ? AND ?", t.filterByField)
-		where = true
+	query := fmt.Sprintf("SELECT %s FROM %s", colStr, t.Name) + t.whereClause()
+	if t.orderBy != "" {
+		query += " ORDER BY " + t.orderByClause()
 	}
-	for k, v := range t.filteredFields {
-		if len(v) == 0 {
-			continue
-		}
-		f := t.parseFilter(k, v)
-		if f == "" {
-			continue
-		}
-		if !where {
-			query += " WHERE "
-			where = true
-		} else {
-			query += " AND "
-		}
-		query += f
+	if t.defaultFieldValues == nil && !t.fillFieldsMeta() {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.db_field_scan_failed", nil), errorhandlefunc.ErrorTypeScript, true)
 	}
-	if t.orderBy != "" {
-		query += " ORDER BY " + t.orderBy
+	return &RowFetch{
+		db:       t.conn(),
+		query:    query,
+		args:     slices.Clone(t.rangeFilter),
+		defaults: maps.Clone(t.defaultFieldValues),
 	}
+}
 
-	var results []Record
-	tx := t.db.Raw(query, t.rangeFilter...)
+// Fetch returns the rows left out by FindFirst. It does not change the table.
+func (f *RowFetch) Fetch() ([]Record, error) {
+	return f.fetch(fmt.Sprintf(" LIMIT -1 OFFSET %d", f.offset))
+}
+
+// fetch runs the query with the limit clause and returns its rows
+func (f *RowFetch) fetch(limit string) ([]Record, error) {
+	tx := f.db.Raw(f.query+limit, f.args...)
 	if tx.Error != nil {
-		statefunc.SetLastErrorText(tx.Error.Error())
-		return false
+		return nil, tx.Error
 	}
-
 	rows, err := tx.Rows()
 	if err != nil {
-		statefunc.SetLastErrorText(err.Error())
-		return false
+		return nil, err
 	}
 	defer rows.Close()
 
 	columns, err := rows.Columns()
 	if err != nil {
-		statefunc.SetLastErrorText(err.Error())
-		return false
+		return nil, err
 	}
 	values := make([]interface{}, len(columns))
 	scanArgs := make([]interface{}, len(columns))
@@ -1090,11 +1600,10 @@ func (t *Table) Find() bool {
 		scanArgs[i] = &values[i]
 	}
 
+	var results []Record
 	for rows.Next() {
-		err := rows.Scan(scanArgs...)
-		if err != nil {
-			statefunc.SetLastErrorText(err.Error())
-			return false
+		if err := rows.Scan(scanArgs...); err != nil {
+			return nil, err
 		}
 
 		row := make(Record)
@@ -1108,26 +1617,38 @@ func (t *Table) Find() bool {
 					row[col] = v
 				}
 			} else {
-				row[col] = t.GetDefaultValueForTheField(col)
+				row[col] = f.defaults[col]
 			}
 		}
 		results = append(results, row)
 	}
+	return results, rows.Err()
+}
 
-	if err = rows.Err(); err != nil {
-		statefunc.SetLastErrorText(err.Error())
-		return false
+// RowCountLoaded returns the number of rows loaded by the last Find,
+// without the empty rows added for new records
+func (t *Table) RowCountLoaded() int {
+	if t.Rows == nil {
+		return 0
 	}
+	n := 0
+	for _, r := range t.Rows.Rows {
+		if len(r) > 0 {
+			n++
+		}
+	}
+	return n
+}
 
-	t.Rows = &Rowset{Rows: results, Pos: 0}
-	return len(t.Rows.Rows) > 0
+// HasMore reports whether the last Find stopped at the limit set with SetLimit
+// and more rows follow, to be loaded with SetOffset
+func (t *Table) HasMore() bool {
+	return t.hasMore
 }
 
 // FindLast retrieves the last row from the table based on current filters and ordering.
+// It runs the same query as Find, so it works before Find was ever called.
 func (t *Table) FindLast() bool {
-	if t.Rows == nil || len(t.Rows.Rows) == 0 {
-		return false
-	}
 	if !t.Find() {
 		return false
 	}
@@ -1135,6 +1656,65 @@ func (t *Table) FindLast() bool {
 	return true
 }
 
+// DistinctValues returns the distinct values of the field in the rows matching the current filters.
+// The values are sorted, NULLs are left out and typed fields are converted to user format.
+func (t *Table) DistinctValues(field string) ([]interface{}, bool) {
+	statefunc.ClearErrors()
+	if _, ok := t.fieldTypes[field]; !ok {
+		statefunc.SetLastErrorText(i18nfunc.T("error.db_field_not_found", map[string]interface{}{
+			"Field": field,
+			"Table": t.Name,
+		}))
+		return nil, false
+	}
+	query := fmt.Sprintf("SELECT DISTINCT \"%s\" FROM %s", field, t.Name) + t.whereClause()
+	query += fmt.Sprintf(" ORDER BY \"%s\"%s", field, t.collateClause(field))
+	rows, err := t.conn().Raw(query, t.rangeFilter...).Rows()
+	if err != nil {
+		statefunc.SetLastErrorText(err.Error())
+		return nil, false
+	}
+	defer rows.Close()
+	var values []interface{}
+	for rows.Next() {
+		var v interface{}
+		if err := rows.Scan(&v); err != nil {
+			statefunc.SetLastErrorText(err.Error())
+			return nil, false
+		}
+		if v == nil {
+			continue
+		}
+		if b, ok := v.([]byte); ok {
+			v = string(b)
+		}
+		uv, err := t.valueToUserFormat(field, v)
+		if err != nil {
+			statefunc.SetLastErrorText(err.Error())
+			return nil, false
+		}
+		values = append(values, uv)
+	}
+	if err := rows.Err(); err != nil {
+		statefunc.SetLastErrorText(err.Error())
+		return nil, false
+	}
+	return values, true
+}
+
+// valueToUserFormat converts a stored value of a date, time or boolean field to user format
+func (t *Table) valueToUserFormat(field string, v interface{}) (interface{}, error) {
+	switch ft := t.fieldTypes[field]; ft {
+	case typesfunc.TypeDate, typesfunc.TypeTime, typesfunc.TypeDateTime:
+		if s, ok := v.(string); ok && s != "" {
+			return timefunc.FormatDateTime(s, ft, timefunc.ToUserFormat)
+		}
+	case typesfunc.TypeBoolean:
+		return boolfunc.FormatBool(fmt.Sprint(v), boolfunc.ToUserFormat)
+	}
+	return v, nil
+}
+
 func (t *Table) ScrollToBeginning() {
 	t.Rows.Pos = 0
 }
@@ -1150,7 +1730,7 @@ func (t *Table) ScrollToRow(row int) {
 // getFieldMetadata retrieves metadata for a specific field
 func (t *Table) getFieldMetadata(fieldName string) (*TableMetadata, error) {
 	var metadata TableMetadata
-	result := t.db.Where("table_name = ? AND field_name = ?", t.Name, fieldName).First(&metadata)
+	result := t.conn().Where("table_name = ? AND field_name = ?", t.Name, fieldName).First(&metadata)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
 			return nil, nil // No metadata found
@@ -1167,8 +1747,60 @@ func (t *Table) GetCurrentRecord() Record {
 	return t.Rows.Rows[t.Rows.Pos]
 }
 
-// GetField gets a field value with type conversion based on metadata
+// Records returns the rows loaded by the last Find with their fields in user format,
+// as GetField returns them. The current row stays where it is. Returns false if Find
+// was not called, so a script can not load a whole table by mistake; SetLimit bounds
+// the number of rows.
+func (t *Table) Records() ([]Record, bool) {
+	statefunc.ClearErrors()
+	if t.Rows == nil {
+		statefunc.SetLastErrorText(i18nfunc.T("error.db_find_not_called", map[string]interface{}{
+			"Table": t.Name,
+		}))
+		return nil, false
+	}
+	pos := t.Rows.Pos
+	defer func() { t.Rows.Pos = pos }()
+	records := make([]Record, 0, len(t.Rows.Rows))
+	for i := range t.Rows.Rows {
+		t.Rows.Pos = i
+		rec := Record{}
+		for _, col := range t.Columns {
+			rec[col] = t.GetField(col, "")
+		}
+		records = append(records, rec)
+	}
+	return records, true
+}
+
+// GetField gets a field value with type conversion based on metadata.
+// If a read transform is set for the field, the value is passed through it.
 func (t *Table) GetField(field, dtType string) interface{} {
+	value := t.getFieldValue(field, dtType)
+	if funcName, ok := t.readTransforms[field]; ok && value != nil {
+		return runTransform(funcName, value)
+	}
+	return value
+}
+
+// GetRawField returns the value of the field of the current row as it is stored,
+// without the user format of dates, times and booleans and without read transforms.
+// exists is false if the row has no such field.
+func (t *Table) GetRawField(field string) (value interface{}, exists bool) {
+	if t.GetFieldType(field) == "" {
+		return nil, false
+	}
+	if t.Rows == nil || t.Rows.Pos < 0 || t.Rows.Pos >= len(t.Rows.Rows) {
+		return nil, true
+	}
+	value, exists = t.Rows.Rows[t.Rows.Pos][field]
+	if vp, ok := value.(*interface{}); ok {
+		value = *vp
+	}
+	return value, exists
+}
+
+func (t *Table) getFieldValue(field, dtType string) interface{} {
 	if t.Rows == nil {
 		return nil
 	}
@@ -1183,6 +1815,10 @@ func (t *Table) GetField(field, dtType string) interface{} {
 	switch t.fieldTypes[field] {
 	case typesfunc.TypeDate, typesfunc.TypeTime, typesfunc.TypeDateTime:
 		if str, ok := value.(string); ok && str != "" {
+			if t.checkFormatType(str, t.fieldTypes[field]) == 1 {
+				// Set by the script and not saved yet
+				return str
+			}
 			formatted, err := timefunc.FormatDateTime(str, t.fieldTypes[field], timefunc.ToUserFormat)
 			if err != nil {
 				errorhandlefunc.ThrowError(err.Error(), errorhandlefunc.ErrorTypeData, false)
@@ -1276,6 +1912,9 @@ func (t *Table) checkFormatType(value string, extType string) int {
 }
 
 func (t *Table) fieldUserFormatToInternalFormat(field string, value interface{}, extType string) (any, bool) {
+	if funcName, ok := t.writeTransforms[field]; ok {
+		value = runTransform(funcName, value)
+	}
 	metadata, _ := t.getFieldMetadata(field)
 
 	var finalValue interface{}
@@ -1288,18 +1927,13 @@ func (t *Table) fieldUserFormatToInternalFormat(field string, value interface{},
 				} else {
 					ft := t.checkFormatType(str, metadata.LogicalType)
 					if ft == -1 {
-						errorhandlefunc.ThrowError(i18nfunc.T("error.db_invalid_type", map[string]interface{}{
-							"Type":     metadata.LogicalType,
-							"Date":     typesfunc.TypeDate,
-							"Time":     typesfunc.TypeTime,
-							"DateTime": typesfunc.TypeDateTime,
-						}), errorhandlefunc.ErrorTypeScript, true)
+						errorhandlefunc.ThrowError(timefunc.FieldFormatError(t.fieldCaption(field), str, metadata.LogicalType).Error(), errorhandlefunc.ErrorTypeScript, true)
 						return nil, false
 					}
 					if ft == 1 {
 						formatted, err := timefunc.FormatDateTime(str, metadata.LogicalType, timefunc.ToInternalFormat)
 						if err != nil {
-							errorhandlefunc.ThrowError(err.Error(), errorhandlefunc.ErrorTypeData, false)
+							errorhandlefunc.ThrowError(timefunc.FieldFormatError(t.fieldCaption(field), str, metadata.LogicalType).Error(), errorhandlefunc.ErrorTypeData, false)
 							return nil, false
 						}
 						finalValue = formatted
@@ -1325,7 +1959,7 @@ func (t *Table) fieldUserFormatToInternalFormat(field string, value interface{},
 			}
 			err := timefunc.CheckDateTimeConsistent(finalValue.(string), metadata.LogicalType, timefunc.ToInternalFormat)
 			if err != nil {
-				errorhandlefunc.ThrowError(err.Error(), errorhandlefunc.ErrorTypeData, false)
+				errorhandlefunc.ThrowError(timefunc.FieldFormatError(t.fieldCaption(field), fmt.Sprintf("%v", value), metadata.LogicalType).Error(), errorhandlefunc.ErrorTypeData, false)
 				return nil, false
 			}
 		case typesfunc.TypeBoolean:
@@ -1399,11 +2033,19 @@ func (t *Table) fieldUserFormatToInternalFormat(field string, value interface{},
 }
 
 func (t *Table) DeleteRow() bool {
+	defer t.timeOperation("Delete")()
 	if t.Rows == nil || t.Rows.Pos < 0 || t.Rows.Pos >= len(t.Rows.Rows) {
 		return false //, fmt.Errorf("no current row")
 	}
 	row := t.Rows.Rows[t.Rows.Pos]
 	res := t.delete(row[PrimaryKeyField])
+	if res && t.softDeleteField != "" && t.includeDeleted {
+		// The flagged row stays in the rows, read it again
+		pos := t.Rows.Pos
+		t.Find()
+		t.Rows.Pos = min(pos, max(len(t.Rows.Rows)-1, 0))
+		return true
+	}
 	if res {
 		if len(t.Rows.Rows) == 1 {
 			t.Rows.Rows = []Record{}
@@ -1481,6 +2123,87 @@ func (t *Table) GetDefaultValueForTheField(field string) interface{} {
 	return t.defaultFieldValues[field]
 }
 
+// SetFieldDefault overrides the default value the field gets in new rows.
+// The value is given in user format and must match the field type.
+func (t *Table) SetFieldDefault(field string, value interface{}) bool {
+	if t.defaultFieldValues == nil && !t.fillFieldsMeta() {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.db_field_scan_failed", nil), errorhandlefunc.ErrorTypeScript, true)
+		return false
+	}
+	if _, ok := t.defaultFieldValues[field]; !ok || field == PrimaryKeyField {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.db_field_not_found", map[string]interface{}{
+			"Field": field,
+			"Table": t.Name,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return false
+	}
+	fType := t.fieldTypes[field]
+	var v interface{}
+	var err error
+	str, isString := value.(string)
+	switch fType {
+	case typesfunc.TypeDate, typesfunc.TypeTime, typesfunc.TypeDateTime:
+		if !isString {
+			break
+		}
+		if str == "" || t.checkFormatType(str, fType) == 0 {
+			v = str
+		} else if v, err = timefunc.FormatDateTime(str, fType, timefunc.ToInternalFormat); err != nil {
+			v = nil
+		}
+	case typesfunc.TypeBoolean:
+		switch x := value.(type) {
+		case bool:
+			v = fmt.Sprintf("%v", x)
+		case string:
+			v = x
+		}
+		if v != nil {
+			v, _ = boolfunc.FormatBool(v.(string), boolfunc.ToInternalFormat)
+		}
+	case typesfunc.TypeInteger:
+		switch x := value.(type) {
+		case int, int64:
+			v = x
+		case float64:
+			if x == float64(int64(x)) {
+				v = int64(x)
+			}
+		case string:
+			if i, err := strconv.ParseInt(strings.TrimSpace(x), 10, 64); err == nil {
+				v = i
+			}
+		}
+	case typesfunc.TypeReal:
+		switch x := value.(type) {
+		case int:
+			v = float64(x)
+		case int64:
+			v = float64(x)
+		case float64:
+			v = x
+		case string:
+			if f, err := strconv.ParseFloat(strings.TrimSpace(x), 64); err == nil {
+				v = f
+			}
+		}
+	default:
+		if value != nil {
+			v = fmt.Sprintf("%v", value)
+		}
+	}
+	if v == nil {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.db_default_type_mismatch", map[string]interface{}{
+			"Field": field,
+			"Type":  fType,
+			"Value": value,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return false
+	}
+	t.defaultFieldValues[field] = v
+	return true
+}
+
 func (t *Table) GetFieldType(field string) string {
 	if t.fieldTypes == nil {
 		if !t.fillFieldsMeta() {
@@ -1493,7 +2216,7 @@ func (t *Table) GetFieldType(field string) string {
 
 func (t *Table) fillFieldsMeta() bool {
 	// Get column types from PRAGMA table_info
-	rows, err := t.db.Raw("PRAGMA table_info(" + t.Name + ")").Rows()
+	rows, err := t.conn().Raw("PRAGMA table_info(" + t.Name + ")").Rows()
 	if err != nil {
 		return false
 	}
@@ -1527,6 +2250,10 @@ func (t *Table) fillFieldsMeta() bool {
 			case typesfunc.TypeReal, "FLOAT", "DOUBLE":
 				t.defaultFieldValues[colName] = 0.0
 			}
+			if isNullDefault(dfltValue) {
+				// A reference to another table stays NULL until it is set
+				t.defaultFieldValues[colName] = nil
+			}
 		} else {
 			// Set default value based on type if dfltValue is nil
 			if dfltValue != nil {
@@ -1574,7 +2301,7 @@ func (t *Table) Init() {
 	fields := &t.Rows.Rows[t.Rows.Pos]
 
 	//Get column types from PRAGMA table_info
-	rows, err := t.db.Raw("PRAGMA table_info(" + t.Name + ")").Rows()
+	rows, err := t.conn().Raw("PRAGMA table_info(" + t.Name + ")").Rows()
 	if err != nil {
 		return
 	}
@@ -1610,6 +2337,50 @@ func DropTable(db *gorm.DB, name string) error {
 	return nil
 }
 
+// RenameTable renames a table together with its metadata and returns the reopened table.
+// The system metadata table can not be renamed and the new name must not be in use.
+func RenameTable(db *gorm.DB, oldName, newName string) (*Table, error) {
+	if oldName == SysMetaTable || newName == SysMetaTable {
+		return nil, errors.New(i18nfunc.T("error.db_rename_sysmeta", map[string]interface{}{
+			"Name": SysMetaTable,
+		}))
+	}
+	if !tableExists(db, oldName) {
+		return nil, errors.New(i18nfunc.T("error.db_rename_table_missing", map[string]interface{}{
+			"Name": oldName,
+		}))
+	}
+	if tableExists(db, newName) {
+		return nil, errors.New(i18nfunc.T("error.db_rename_table_exists", map[string]interface{}{
+			"Name": newName,
+		}))
+	}
+	var failed error
+	statefunc.ClearErrors()
+	ok := RunInTransaction(db, func() bool {
+		conn := dbConn(db)
+		if err := conn.Exec(fmt.Sprintf("ALTER TABLE \"%s\" RENAME TO \"%s\"", oldName, newName)).Error; err != nil {
+			failed = err
+			return false
+		}
+		if err := conn.Model(&TableMetadata{}).Where("table_name = ?", oldName).Update("table_name", newName).Error; err != nil {
+			failed = err
+			return false
+		}
+		return true
+	})
+	if failed == nil && !ok {
+		failed = errors.New(statefunc.GetLastErrorText())
+	}
+	if failed != nil {
+		return nil, errors.New(i18nfunc.T("error.db_rename_table_failed", map[string]interface{}{
+			"Name":  oldName,
+			"Error": failed.Error(),
+		}))
+	}
+	return OpenTable(db, newName), nil
+}
+
 // tableExists checks if a table exists in the database
 func tableExists(db *gorm.DB, tableName string) bool {
 	// For SQLite, we can check sqlite_master table
@@ -0,0 +1,361 @@
+package gormfunc
+
+import (
+	"fmt"
+	"gotulua/i18nfunc"
+	"gotulua/statefunc"
+	"os"
+	"slices"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	i18nfunc.InitI18n("en") // The errors are checked in their English text
+	os.Exit(m.Run())
+}
+
+// newTestDB creates a database in memory that is closed when the test ends
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := CreateDB(MemoryDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { CloseDB(db) })
+	return db
+}
+
+// newTestTable creates a table with the structure in the format of DBCreateTable.
+// Its rows are loaded with Find, as Insert needs them.
+func newTestTable(t *testing.T, db *gorm.DB, name, structure string) *Table {
+	t.Helper()
+	table := CreateTable(db, name, structure, false, false)
+	if table == nil {
+		t.Fatalf("table %s was not created", name)
+	}
+	table.Find()
+	return table
+}
+
+// insert inserts the row into the table and returns its id
+func insert(t *testing.T, table *Table, fields map[string]interface{}) int64 {
+	t.Helper()
+	var id int64
+	if !table.Insert(fields, &id) {
+		t.Fatalf("insert of %v failed: %s", fields, statefunc.GetLastErrorText())
+	}
+	return id
+}
+
+// insertNames inserts a row with each name into a table with a Name field
+func insertNames(t *testing.T, table *Table, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		insert(t, table, map[string]interface{}{"Name": name})
+	}
+}
+
+// names returns the Name field of the rows loaded by the last Find
+func names(table *Table) []string {
+	var list []string
+	for _, r := range table.Rows.Rows {
+		list = append(list, fmt.Sprint(r["Name"]))
+	}
+	return list
+}
+
+func TestLastInsertIDIsKeptPerTable(t *testing.T) {
+	db := newTestDB(t)
+	names := newTestTable(t, db, "Names", "n::Name;t::Text;l::20")
+	codes := newTestTable(t, db, "Codes", "n::Code;t::Text;l::20")
+	if id := names.LastInsertID(); id != 0 {
+		t.Fatalf("LastInsertID before any insert is %d, want 0", id)
+	}
+
+	insert(t, names, map[string]interface{}{"Name": "a"})
+	second := insert(t, names, map[string]interface{}{"Name": "b"})
+	if id := names.LastInsertID(); id != second {
+		t.Errorf("LastInsertID is %d, want %d", id, second)
+	}
+	if id := codes.LastInsertID(); id != 0 {
+		t.Errorf("LastInsertID of the other table is %d, want 0", id)
+	}
+
+	code := insert(t, codes, map[string]interface{}{"Code": "x"})
+	if id := codes.LastInsertID(); id != code {
+		t.Errorf("LastInsertID of Codes is %d, want %d", id, code)
+	}
+	if id := names.LastInsertID(); id != second {
+		t.Errorf("an insert into Codes changed LastInsertID of Names to %d", id)
+	}
+}
+
+func TestNocaseCollationInFiltersAndOrdering(t *testing.T) {
+	db := newTestDB(t)
+	table := newTestTable(t, db, "Names", "n::Name;t::Text;l::20")
+	insertNames(t, table, "bob", "Anna", "alice", "Bea")
+
+	table.SetFilter("Name", "ANNA")
+	if table.Find() {
+		t.Errorf("the binary filter found %q", names(table))
+	}
+	if !table.SetCollation("Name", "nocase") {
+		t.Fatal(statefunc.GetLastErrorText())
+	}
+	if !table.Find() || !slices.Equal(names(table), []string{"Anna"}) {
+		t.Errorf("the NOCASE filter found %q, want Anna", names(table))
+	}
+
+	table.SetFilter("Name", "")
+	table.OrderBy("Name")
+	want := []string{"alice", "Anna", "Bea", "bob"}
+	if !table.Find() || !slices.Equal(names(table), want) {
+		t.Errorf("the NOCASE order is %q, want %q", names(table), want)
+	}
+	table.SetCollation("Name", "")
+	want = []string{"Anna", "Bea", "alice", "bob"}
+	if !table.Find() || !slices.Equal(names(table), want) {
+		t.Errorf("the binary order is %q, want %q", names(table), want)
+	}
+}
+
+func TestSetFieldDefaultIsUsedByNewRows(t *testing.T) {
+	db := newTestDB(t)
+	table := newTestTable(t, db, "Items", "n::Name;t::Text;l::20|n::Qty;t::Integer")
+	if !table.SetFieldDefault("Qty", 5.0) { // As Lua passes a number
+		t.Fatal(statefunc.GetLastErrorText())
+	}
+
+	table.Init()
+	if qty := table.Rows.Rows[table.Rows.Pos]["Qty"]; qty != int64(5) {
+		t.Errorf("the new row has Qty %v, want the default 5", qty)
+	}
+	if !table.AddRow("Name", "a") {
+		t.Fatal(statefunc.GetLastErrorText())
+	}
+	var qty int64
+	if err := db.Raw("SELECT Qty FROM Items WHERE Name = 'a'").Scan(&qty).Error; err != nil || qty != 5 {
+		t.Errorf("the added row holds Qty %d, want the default 5: %v", qty, err)
+	}
+}
+
+func TestRenameTableMovesDataAndMetadata(t *testing.T) {
+	db := newTestDB(t)
+	table := newTestTable(t, db, "Events", "n::Name;t::Text;l::20|n::Day;t::Date")
+	insert(t, table, map[string]interface{}{"Name": "a", "Day": "20240229"})
+	newTestTable(t, db, "Taken", "n::Name;t::Text;l::20")
+
+	renamed, err := RenameTable(db, "Events", "Meetings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if TableExists(db, "Events") || !TableExists(db, "Meetings") {
+		t.Fatal("the table was not renamed")
+	}
+	if renamed.Name != "Meetings" || !renamed.Find() || len(renamed.Rows.Rows) != 1 {
+		t.Fatalf("the reopened table %s does not hold the row", renamed.Name)
+	}
+	if day := renamed.GetField("Day", ""); day != "29.02.2024" {
+		t.Errorf("Day reads %v, want the date through its metadata", day)
+	}
+	var left int64
+	db.Model(&TableMetadata{}).Where("table_name = ?", "Events").Count(&left)
+	if left != 0 {
+		t.Errorf("%d metadata rows kept the old name", left)
+	}
+
+	if _, err := RenameTable(db, "Meetings", "Taken"); err == nil {
+		t.Error("the table was renamed to the name of another table")
+	}
+	if _, err := RenameTable(db, SysMetaTable, "Other"); err == nil {
+		t.Error("the metadata table was renamed")
+	}
+}
+
+func TestDistinctValuesAreUniqueAndSorted(t *testing.T) {
+	db := newTestDB(t)
+	table := newTestTable(t, db, "Visits", "n::Name;t::Text;l::20|n::Day;t::Date")
+	for _, v := range []struct{ name, day string }{
+		{"c", "20240301"}, {"a", "20240229"}, {"c", "20240301"}, {"b", "20240101"}, {"a", "20240229"},
+	} {
+		insert(t, table, map[string]interface{}{"Name": v.name, "Day": v.day})
+	}
+
+	values, ok := table.DistinctValues("Name")
+	if !ok || !slices.Equal(values, []interface{}{"a", "b", "c"}) {
+		t.Errorf("DistinctValues(Name) = %v, want a, b, c", values)
+	}
+	values, ok = table.DistinctValues("Day")
+	if want := []interface{}{"01.01.2024", "29.02.2024", "01.03.2024"}; !ok || !slices.Equal(values, want) {
+		t.Errorf("DistinctValues(Day) = %v, want %v", values, want)
+	}
+	table.SetFilter("Name", "c")
+	values, ok = table.DistinctValues("Day")
+	if !ok || !slices.Equal(values, []interface{}{"01.03.2024"}) {
+		t.Errorf("DistinctValues(Day) of the filtered rows = %v, want 01.03.2024", values)
+	}
+	if _, ok := table.DistinctValues("Missing"); ok {
+		t.Error("DistinctValues of a missing field succeeded")
+	}
+}
+
+func TestGetRawFieldReadsTheStoredValue(t *testing.T) {
+	db := newTestDB(t)
+	table := newTestTable(t, db, "Events", "n::Day;t::Date")
+	insert(t, table, map[string]interface{}{"Day": "20240229"})
+	table.Find()
+
+	if day := table.GetField("Day", ""); day != "29.02.2024" {
+		t.Errorf("GetField(Day) = %v, want the user format", day)
+	}
+	if day, ok := table.GetRawField("Day"); !ok || day != "20240229" {
+		t.Errorf("GetRawField(Day) = %v, %v, want the stored 20240229", day, ok)
+	}
+	if _, ok := table.GetRawField("Missing"); ok {
+		t.Error("GetRawField of a missing field reports it exists")
+	}
+}
+
+func TestFindHonorsLimitAndOffset(t *testing.T) {
+	db := newTestDB(t)
+	table := newTestTable(t, db, "Names", "n::Name;t::Text;l::20")
+	insertNames(t, table, "e", "b", "d", "a", "c")
+	table.OrderBy("Name")
+
+	table.SetLimit(2).SetOffset(2)
+	if !table.Find() || !slices.Equal(names(table), []string{"c", "d"}) {
+		t.Errorf("limit 2 offset 2 found %q, want c, d", names(table))
+	}
+	table.SetLimit(0)
+	if !table.Find() || !slices.Equal(names(table), []string{"c", "d", "e"}) {
+		t.Errorf("offset 2 found %q, want c, d, e", names(table))
+	}
+	table.SetOffset(-1)
+	if !table.Find() || len(names(table)) != 5 {
+		t.Errorf("without limit and offset %q were found, want all 5", names(table))
+	}
+}
+
+func TestFindLastStandsOnTheLastRow(t *testing.T) {
+	db := newTestDB(t)
+	table := newTestTable(t, db, "Names", "n::Name;t::Text;l::20")
+	insertNames(t, table, "b", "c", "a")
+
+	// Before any Find of its own
+	fresh := OpenTable(db, "Names")
+	fresh.OrderBy("Name")
+	if !fresh.FindLast() {
+		t.Fatalf("FindLast found no rows: %s", statefunc.GetLastErrorText())
+	}
+	if got := fresh.GetCurrentRecord()["Name"]; got != "c" {
+		t.Errorf("FindLast stands on %v, want c", got)
+	}
+
+	fresh.SetFilter("Name", "a")
+	if !fresh.FindLast() {
+		t.Fatalf("FindLast found no rows: %s", statefunc.GetLastErrorText())
+	}
+	if got := fresh.GetCurrentRecord()["Name"]; got != "a" || len(fresh.Rows.Rows) != 1 {
+		t.Errorf("FindLast with a filter stands on %v of %d rows, want a of 1", got, len(fresh.Rows.Rows))
+	}
+
+	empty := newTestTable(t, db, "Empty", "n::Name;t::Text;l::20")
+	if empty.FindLast() {
+		t.Error("FindLast found a row in an empty table")
+	}
+}
+
+func TestFindPagesThroughAHundredRows(t *testing.T) {
+	db := newTestDB(t)
+	table := newTestTable(t, db, "Names", "n::Name;t::Text;l::20")
+	for i := 1; i <= 100; i++ {
+		insertNames(t, table, fmt.Sprintf("row %03d", i))
+	}
+	table.OrderBy("id")
+
+	table.SetLimit(10).SetOffset(20)
+	if !table.Find() {
+		t.Fatal(statefunc.GetLastErrorText())
+	}
+	var want []string
+	for i := 21; i <= 30; i++ {
+		want = append(want, fmt.Sprintf("row %03d", i))
+	}
+	if got := names(table); !slices.Equal(got, want) {
+		t.Errorf("limit 10 offset 20 found %q, want rows 21 to 30", got)
+	}
+
+	table.SetOffset(95)
+	if !table.Find() || len(names(table)) != 5 {
+		t.Errorf("limit 10 offset 95 found %d rows, want the last 5", len(names(table)))
+	}
+}
+
+func TestFilterModeOrCombinesTheFieldFilters(t *testing.T) {
+	db := newTestDB(t)
+	table := newTestTable(t, db, "Names", "n::Name;t::Text;l::20|n::Qty;t::Integer")
+	for i, name := range []string{"a", "b", "c", "d"} {
+		insert(t, table, map[string]interface{}{"Name": name, "Qty": int64(i + 1)})
+	}
+	table.OrderBy("Name")
+	table.SetFilter("Name", "a").SetFilter("Qty", ">3")
+
+	table.Find()
+	if got := names(table); len(got) != 0 {
+		t.Errorf("AND found %q, want no rows", got)
+	}
+	if !table.SetFilterMode("or") {
+		t.Fatal("SetFilterMode refused OR")
+	}
+	table.Find()
+	if got := names(table); !slices.Equal(got, []string{"a", "d"}) {
+		t.Errorf("OR found %q, want a, d", got)
+	}
+	// A range filter still narrows the OR of the field filters
+	table.SetRangeFilter("Qty", 2, 4)
+	table.Find()
+	if got := names(table); !slices.Equal(got, []string{"d"}) {
+		t.Errorf("OR within the range found %q, want d", got)
+	}
+}
+
+func TestRowCountLoadedAndHasMoreFollowThePages(t *testing.T) {
+	db := newTestDB(t)
+	table := newTestTable(t, db, "Names", "n::Name;t::Text;l::20")
+	insertNames(t, table, "a", "b", "c", "d", "e")
+	table.OrderBy("Name").SetLimit(2)
+
+	pages := []struct {
+		offset int
+		count  int
+		more   bool
+	}{
+		{0, 2, true},
+		{2, 2, true},
+		{4, 1, false},
+		{6, 0, false},
+	}
+	for _, p := range pages {
+		table.SetOffset(p.offset)
+		table.Find()
+		if n := table.RowCountLoaded(); n != p.count {
+			t.Errorf("offset %d loaded %d rows, want %d", p.offset, n, p.count)
+		}
+		if more := table.HasMore(); more != p.more {
+			t.Errorf("offset %d HasMore is %v, want %v", p.offset, more, p.more)
+		}
+	}
+
+	// An empty row added for a new record is not a loaded row
+	table.SetLimit(0).SetOffset(0)
+	table.Find()
+	table.Rows.Rows = append(table.Rows.Rows, Record{})
+	if n := table.RowCountLoaded(); n != 5 {
+		t.Errorf("RowCountLoaded is %d with a new row, want 5", n)
+	}
+	if table.HasMore() {
+		t.Error("HasMore without a limit")
+	}
+}
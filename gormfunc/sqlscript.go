@@ -0,0 +1,97 @@
+package gormfunc
+
+import (
+	"errors"
+	"gotulua/i18nfunc"
+	"gotulua/statefunc"
+	"os"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ExecScript runs the SQL statements of the file in a single transaction.
+// When a statement fails the changes are rolled back and the error names the statement.
+func ExecScript(db *gorm.DB, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.New(i18nfunc.T("error.db_script_read_failed", map[string]interface{}{
+			"Name":  path,
+			"Error": err.Error(),
+		}))
+	}
+	statements := SplitSQLStatements(string(data))
+	var failed error
+	statefunc.ClearErrors()
+	ok := RunInTransaction(db, func() bool {
+		conn := dbConn(db)
+		for _, stmt := range statements {
+			if err := conn.Exec(stmt).Error; err != nil {
+				failed = errors.New(i18nfunc.T("error.db_script_statement_failed", map[string]interface{}{
+					"Statement": stmt,
+					"Error":     err.Error(),
+				}))
+				return false
+			}
+		}
+		return true
+	})
+	if failed != nil {
+		return failed
+	}
+	if !ok {
+		return errors.New(statefunc.GetLastErrorText())
+	}
+	return nil
+}
+
+// SplitSQLStatements splits the script into statements on semicolons.
+// Semicolons inside quoted strings, identifiers and comments do not end a statement.
+// Empty statements and comments are left out.
+func SplitSQLStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	runes := []rune(script)
+	flush := func() {
+		stmt := strings.TrimSpace(current.String())
+		if stmt != "" {
+			statements = append(statements, stmt)
+		}
+		current.Reset()
+	}
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\'' || r == '"' || r == '`':
+			// Copy the quoted text up to the closing quote; doubled quotes are escapes
+			current.WriteRune(r)
+			for i++; i < len(runes); i++ {
+				current.WriteRune(runes[i])
+				if runes[i] == r {
+					if i+1 < len(runes) && runes[i+1] == r {
+						i++
+						current.WriteRune(runes[i])
+						continue
+					}
+					break
+				}
+			}
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			current.WriteRune('\n')
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			for i += 2; i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/'); i++ {
+			}
+			i++
+			current.WriteRune(' ')
+		case r == ';':
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+	return statements
+}
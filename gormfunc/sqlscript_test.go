@@ -0,0 +1,86 @@
+package gormfunc
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestSplitSQLStatements(t *testing.T) {
+	tests := []struct {
+		script string
+		want   []string
+	}{
+		{"SELECT 1; SELECT 2;", []string{"SELECT 1", "SELECT 2"}},
+		{"INSERT INTO t VALUES ('a;b')", []string{"INSERT INTO t VALUES ('a;b')"}},
+		{"INSERT INTO t VALUES ('it''s; fine')", []string{"INSERT INTO t VALUES ('it''s; fine')"}},
+		{"SELECT \"a;b\" FROM t", []string{"SELECT \"a;b\" FROM t"}},
+		{"-- a comment; still a comment\nSELECT 1", []string{"SELECT 1"}},
+		{"SELECT /* ; */ 1", []string{"SELECT   1"}},
+		{" ; ;\n", nil},
+	}
+	for _, tt := range tests {
+		if got := SplitSQLStatements(tt.script); !slices.Equal(got, tt.want) {
+			t.Errorf("SplitSQLStatements(%q) = %q, want %q", tt.script, got, tt.want)
+		}
+	}
+}
+
+// writeScript writes the SQL script to a file in a temporary directory and returns its path
+func writeScript(t *testing.T, script string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "script.sql")
+	if err := os.WriteFile(path, []byte(script), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// countRows returns the number of rows of the table, or -1 if it cannot be read
+func countRows(db *gorm.DB, table string) int64 {
+	var n int64
+	if err := db.Table(table).Count(&n).Error; err != nil {
+		return -1
+	}
+	return n
+}
+
+func TestExecScriptRunsAllStatements(t *testing.T) {
+	db := newTestDB(t)
+	path := writeScript(t, `
+		CREATE TABLE Names (id INTEGER PRIMARY KEY, Name TEXT);
+		INSERT INTO Names (Name) VALUES ('a; b'), ('c');`)
+	if err := ExecScript(db, path); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRows(db, "Names"); n != 2 {
+		t.Errorf("the script inserted %d rows, want 2", n)
+	}
+}
+
+func TestExecScriptRollsBackOnAFailedStatement(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := Exec(db, "CREATE TABLE Names (id INTEGER PRIMARY KEY, Name TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+	path := writeScript(t, `
+		INSERT INTO Names (Name) VALUES ('a');
+		INSERT INTO Missing (Name) VALUES ('b');`)
+	err := ExecScript(db, path)
+	if err == nil {
+		t.Fatal("the script with an invalid statement succeeded")
+	}
+	if !strings.Contains(err.Error(), "INSERT INTO Missing") {
+		t.Errorf("the error %q does not name the failed statement", err)
+	}
+	if n := countRows(db, "Names"); n != 0 {
+		t.Errorf("the table has %d rows, want the first insert rolled back", n)
+	}
+	if InTransaction(db) {
+		t.Error("the transaction is still open")
+	}
+}
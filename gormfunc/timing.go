@@ -0,0 +1,69 @@
+package gormfunc
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// The timing mode measures the table operations to find slow filters and missing indexes.
+// The duration of the last operation is kept, and every operation is logged if a log file is set.
+var (
+	timingMu   sync.Mutex
+	timingOn   bool
+	timingLog  io.WriteCloser
+	lastTiming time.Duration
+)
+
+// SetTiming turns the timing mode on or off. With a log path every timed operation is
+// appended to the file; if it can not be opened timing stays off. Turning it off closes
+// the log and clears the last timing.
+func SetTiming(on bool, logPath string) error {
+	timingMu.Lock()
+	defer timingMu.Unlock()
+	if timingLog != nil {
+		timingLog.Close()
+		timingLog = nil
+	}
+	timingOn = on
+	lastTiming = 0
+	if on && logPath != "" {
+		f, err := os.OpenFile(logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			timingOn = false
+			return err
+		}
+		timingLog = f
+	}
+	return nil
+}
+
+// LastTiming returns the duration of the last timed operation, 0 if timing is off
+func LastTiming() time.Duration {
+	timingMu.Lock()
+	defer timingMu.Unlock()
+	return lastTiming
+}
+
+// timeOperation starts timing an operation of the table when the timing mode is on.
+// The returned function ends it: defer t.timeOperation("Find")()
+func (t *Table) timeOperation(operation string) func() {
+	timingMu.Lock()
+	on := timingOn
+	timingMu.Unlock()
+	if !on {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+		timingMu.Lock()
+		defer timingMu.Unlock()
+		lastTiming = elapsed
+		if timingLog != nil {
+			fmt.Fprintf(timingLog, "%s %s %s %s\n", start.Format("2006-01-02 15:04:05.000"), t.Name, operation, elapsed)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package gormfunc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLastTimingFollowsTheTimingMode(t *testing.T) {
+	db := newTestDB(t)
+	table := newTestTable(t, db, "Names", "n::Name;t::Text;l::20")
+	insertNames(t, table, "a", "b")
+	t.Cleanup(func() { SetTiming(false, "") })
+
+	table.Find()
+	if d := LastTiming(); d != 0 {
+		t.Errorf("LastTiming is %s with timing off, want 0", d)
+	}
+
+	logPath := filepath.Join(t.TempDir(), "timing.log")
+	if err := SetTiming(true, logPath); err != nil {
+		t.Fatal(err)
+	}
+	table.Find()
+	if d := LastTiming(); d <= 0 {
+		t.Errorf("LastTiming is %s after a Find, want a positive duration", d)
+	}
+	SetTiming(false, "") // Closes the log
+	if data, _ := os.ReadFile(logPath); !strings.Contains(string(data), "Names Find ") {
+		t.Errorf("the log holds %q, want the Find of Names", data)
+	}
+
+	table.Find()
+	if d := LastTiming(); d != 0 {
+		t.Errorf("LastTiming is %s after timing was turned off, want 0", d)
+	}
+}
@@ -0,0 +1,162 @@
+package gormfunc
+
+import (
+	"errors"
+	"gotulua/i18nfunc"
+	"gotulua/statefunc"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// transactions keeps the transaction the script opened on every database connection.
+// While a transaction is open all tables of the database run their statements inside it.
+// The script and the UI loop take turns on the Lua state, so they share it; work done on
+// other goroutines, like the background load of a browse, never runs inside it.
+var (
+	transactionsMu sync.Mutex
+	transactions   = map[*gorm.DB]*gorm.DB{}
+	afterCommits   = map[*gorm.DB][]func(){} // Callbacks waiting for the open transaction to be committed
+)
+
+// conn returns the connection the table must use for its statements:
+// the open transaction of its database or the database itself.
+func (t *Table) conn() *gorm.DB {
+	return dbConn(t.db)
+}
+
+// dbConn returns the open transaction of the database or the database itself
+func dbConn(db *gorm.DB) *gorm.DB {
+	if tx, ok := openTransaction(db); ok {
+		return tx
+	}
+	return db
+}
+
+// openTransaction returns the transaction the script opened on the database, if there is one
+func openTransaction(db *gorm.DB) (*gorm.DB, bool) {
+	transactionsMu.Lock()
+	defer transactionsMu.Unlock()
+	tx, ok := transactions[db]
+	return tx, ok
+}
+
+// InTransaction reports whether a transaction is open on the database
+func InTransaction(db *gorm.DB) bool {
+	_, ok := openTransaction(db)
+	return ok
+}
+
+// BeginTransaction opens a transaction on the database
+func BeginTransaction(db *gorm.DB) error {
+	_, err := beginTransaction(db, false)
+	return err
+}
+
+// beginTransaction opens a transaction on the database and reports whether it did.
+// With join an open transaction is not an error, the caller runs inside it.
+// The check and the begin hold the lock, so two callers never both begin one.
+func beginTransaction(db *gorm.DB, join bool) (bool, error) {
+	transactionsMu.Lock()
+	defer transactionsMu.Unlock()
+	if _, ok := transactions[db]; ok {
+		if join {
+			return false, nil
+		}
+		return false, errors.New(i18nfunc.T("error.db_transaction_active", nil))
+	}
+	tx := db.Begin()
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	transactions[db] = tx
+	return true, nil
+}
+
+// CommitTransaction commits the open transaction of the database.
+// The after insert, update and delete callbacks of its changes run once it is committed.
+func CommitTransaction(db *gorm.DB) error {
+	tx, callbacks, err := takeTransaction(db)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+	for _, fn := range callbacks {
+		fn()
+	}
+	return nil
+}
+
+// RollbackTransaction rolls back the open transaction of the database.
+// The callbacks of its changes are dropped, as the changes never happened.
+func RollbackTransaction(db *gorm.DB) error {
+	tx, _, err := takeTransaction(db)
+	if err != nil {
+		return err
+	}
+	return tx.Rollback().Error
+}
+
+// takeTransaction removes the open transaction of the database from the registry
+// and returns it with its after commit callbacks
+func takeTransaction(db *gorm.DB) (*gorm.DB, []func(), error) {
+	transactionsMu.Lock()
+	defer transactionsMu.Unlock()
+	tx, ok := transactions[db]
+	if !ok {
+		return nil, nil, errors.New(i18nfunc.T("error.db_transaction_not_active", nil))
+	}
+	callbacks := afterCommits[db]
+	delete(transactions, db)
+	delete(afterCommits, db)
+	return tx, callbacks, nil
+}
+
+// afterCommit runs the callback of a change to the row now, or when the open transaction
+// of the database is committed. A waiting callback gets a copy of the table holding the row,
+// since the table may have moved on by then.
+func (t *Table) afterCommit(callback func(*Table), row Record) {
+	transactionsMu.Lock()
+	if _, ok := transactions[t.db]; !ok {
+		transactionsMu.Unlock()
+		callback(t)
+		return
+	}
+	snapshot := *t
+	snapshot.Rows = &Rowset{Rows: []Record{row}, Pos: 0}
+	afterCommits[t.db] = append(afterCommits[t.db], func() { callback(&snapshot) })
+	transactionsMu.Unlock()
+}
+
+// RunInTransaction calls fn inside a transaction on the database.
+// The transaction is committed when fn returns true and rolled back when fn
+// returns false or raises an error. If a transaction is already open, fn runs
+// inside it and the outer caller decides whether to commit.
+func RunInTransaction(db *gorm.DB, fn func() bool) bool {
+	begun, err := beginTransaction(db, true)
+	if err != nil {
+		statefunc.SetLastErrorText(err.Error())
+		return false
+	}
+	if !begun {
+		return fn()
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			RollbackTransaction(db)
+		}
+	}()
+	if !fn() {
+		return false
+	}
+	if err := CommitTransaction(db); err != nil {
+		committed = true // the transaction is already closed
+		statefunc.SetLastErrorText(err.Error())
+		return false
+	}
+	committed = true
+	return true
+}
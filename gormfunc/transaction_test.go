@@ -0,0 +1,33 @@
+package gormfunc
+
+import (
+	"gotulua/statefunc"
+	"testing"
+)
+
+func TestFindFirstInsideATransactionReadsAllRows(t *testing.T) {
+	db := newTestDB(t)
+	table := newTestTable(t, db, "Names", "n::Name;t::Text;l::20")
+	for _, name := range []string{"a", "b", "c"} {
+		insert(t, table, map[string]interface{}{"Name": name})
+	}
+
+	if rest, ok := table.FindFirst(2); !ok || rest == nil || len(table.Rows.Rows) != 2 {
+		t.Fatalf("FindFirst outside a transaction loaded %d rows, rest %v", len(table.Rows.Rows), rest)
+	}
+
+	ok := RunInTransaction(db, func() bool {
+		insert(t, table, map[string]interface{}{"Name": "d"})
+		rest, ok := table.FindFirst(2)
+		if !ok || rest != nil || len(table.Rows.Rows) != 4 {
+			t.Errorf("FindFirst in a transaction loaded %d rows, rest %v, want all 4 now", len(table.Rows.Rows), rest)
+		}
+		return false
+	})
+	if ok || InTransaction(db) {
+		t.Fatal("the transaction was not rolled back")
+	}
+	if count, _ := table.Count(); count != 3 {
+		t.Errorf("the table has %d rows after the rollback, want 3: %s", count, statefunc.GetLastErrorText())
+	}
+}
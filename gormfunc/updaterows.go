@@ -0,0 +1,44 @@
+package gormfunc
+
+import (
+	"gotulua/i18nfunc"
+	"gotulua/statefunc"
+)
+
+// UpdateRows sets the field to the value in the rows loaded by Find at the positions, counted
+// from 0. The rows are updated in one transaction, so a row that can not be updated leaves all
+// of them as they were. The after update function runs for every row once it is committed.
+func (t *Table) UpdateRows(positions []int, field string, value interface{}) bool {
+	statefunc.ClearErrors()
+	if t.refuseWrite() {
+		return false
+	}
+	if t.Rows == nil {
+		statefunc.SetLastErrorText(i18nfunc.T("error.db_find_not_called", map[string]interface{}{
+			"Table": t.Name,
+		}))
+		return false
+	}
+	pos := t.Rows.Pos
+	saved := append([]Record(nil), t.Rows.Rows...)
+	ok := RunInTransaction(t.db, func() bool {
+		for _, p := range positions {
+			if p < 0 || p >= len(t.Rows.Rows) {
+				continue
+			}
+			// Update keeps the record read back from the database at the current row
+			t.Rows.Pos = p
+			if !t.Update(recordInt(t.Rows.Rows[p], PrimaryKeyField), Record{field: value}) {
+				return false
+			}
+		}
+		return true
+	})
+	t.Rows.Pos = pos
+	if !ok {
+		// The changes were rolled back, so are the loaded rows
+		t.Rows.Rows = saved
+		return false
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+package gormfunc
+
+import (
+	"fmt"
+	"gotulua/i18nfunc"
+	"gotulua/statefunc"
+	"strings"
+)
+
+// Upsert updates the row whose key fields have the values of the record, or inserts the record
+// if there is no such row. keyFields name the fields that identify a row, the primary key if none
+// are given. The lookup and the write run in one transaction. Insert and Update do the writing,
+// so validation, transforms and the after insert/update functions work as usual.
+// Returns the primary key of the row and whether it was inserted; ok is false on errors,
+// which are kept as the last error text.
+func (t *Table) Upsert(fields Record, keyFields []string) (id int64, inserted bool, ok bool) {
+	statefunc.ClearErrors()
+	if t.refuseWrite() {
+		return 0, false, false
+	}
+	if len(keyFields) == 0 {
+		keyFields = []string{PrimaryKeyField}
+	}
+	ok = RunInTransaction(t.db, func() bool {
+		var found, valid bool
+		id, found, valid = t.findIDByKey(fields, keyFields)
+		if !valid {
+			return false
+		}
+		if found {
+			return t.upsertUpdate(id, fields)
+		}
+		inserted = true
+		if t.Rows == nil {
+			t.Rows = &Rowset{}
+		}
+		return t.Insert(fields, &id)
+	})
+	if !ok {
+		return 0, false, false
+	}
+	return id, inserted, true
+}
+
+// findIDByKey returns the primary key of the row with the key values of the record.
+// A key matching more than one row is an error, as the row to update is not known.
+// valid is false when the key can not be looked up.
+func (t *Table) findIDByKey(fields Record, keyFields []string) (id int64, found bool, valid bool) {
+	var where []string
+	var vals []interface{}
+	for _, k := range keyFields {
+		if t.GetFieldType(k) == "" {
+			statefunc.SetLastErrorText(i18nfunc.T("error.db_field_not_found", map[string]interface{}{
+				"Field": k,
+				"Table": t.Name,
+			}))
+			return 0, false, false
+		}
+		value, exists := fields[k]
+		if !exists || value == nil {
+			if k == PrimaryKeyField {
+				return 0, false, true // A record without id is always new
+			}
+			statefunc.SetLastErrorText(i18nfunc.T("error.db_upsert_key_missing", map[string]interface{}{
+				"Field": k,
+			}))
+			return 0, false, false
+		}
+		v, ok := t.fieldUserFormatToInternalFormat(k, value, "")
+		if !ok {
+			return 0, false, false
+		}
+		where = append(where, fmt.Sprintf("\"%s\" = ?", k))
+		vals = append(vals, v)
+	}
+	var ids []int64
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s LIMIT 2", PrimaryKeyField, t.Name, strings.Join(where, " AND "))
+	if err := t.conn().Raw(query, vals...).Scan(&ids).Error; err != nil {
+		statefunc.SetLastErrorText(err.Error())
+		return 0, false, false
+	}
+	if len(ids) > 1 {
+		statefunc.SetLastErrorText(i18nfunc.T("error.db_upsert_key_not_unique", map[string]interface{}{
+			"Fields": strings.Join(keyFields, ", "),
+		}))
+		return 0, false, false
+	}
+	if len(ids) == 0 {
+		return 0, false, true
+	}
+	return ids[0], true, true
+}
+
+// upsertUpdate writes the table fields of the record to the row with the id
+func (t *Table) upsertUpdate(id int64, fields Record) bool {
+	changes := make(Record)
+	for k, v := range fields {
+		if k != PrimaryKeyField && t.GetFieldType(k) != "" {
+			changes[k] = v
+		}
+	}
+	if !t.FindByID(id) {
+		statefunc.SetLastErrorText(i18nfunc.T("error.db_row_not_found", map[string]interface{}{
+			"ID": id,
+		}))
+		return false
+	}
+	if len(changes) == 0 {
+		return true
+	}
+	return t.Update(id, changes)
+}
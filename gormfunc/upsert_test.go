@@ -0,0 +1,34 @@
+package gormfunc
+
+import (
+	"gotulua/statefunc"
+	"testing"
+)
+
+func TestUpsertInsertsThenUpdatesByKey(t *testing.T) {
+	db := newTestDB(t)
+	table := newTestTable(t, db, "Stock", "n::Code;t::Text;l::10|n::Qty;t::Integer")
+
+	id, inserted, ok := table.Upsert(Record{"Code": "A1", "Qty": int64(5)}, []string{"Code"})
+	if !ok || !inserted || id == 0 {
+		t.Fatalf("first Upsert: id %d, inserted %v, ok %v: %s", id, inserted, ok, statefunc.GetLastErrorText())
+	}
+	again, inserted, ok := table.Upsert(Record{"Code": "A1", "Qty": int64(7)}, []string{"Code"})
+	if !ok || inserted || again != id {
+		t.Fatalf("second Upsert: id %d, inserted %v, ok %v, want id %d updated: %s", again, inserted, ok, id, statefunc.GetLastErrorText())
+	}
+	if count, _ := table.Count(); count != 1 {
+		t.Errorf("the table has %d rows, want 1", count)
+	}
+	if !table.FindByID(id) || recordInt(table.GetCurrentRecord(), "Qty") != 7 {
+		t.Errorf("the row was not updated: %v", table.GetCurrentRecord())
+	}
+
+	if _, _, ok := table.Upsert(Record{"Qty": int64(1)}, []string{"Code"}); ok {
+		t.Error("Upsert without the key field succeeded")
+	}
+	insert(t, table, map[string]interface{}{"Code": "A1", "Qty": int64(1)})
+	if _, _, ok := table.Upsert(Record{"Code": "A1", "Qty": int64(2)}, []string{"Code"}); ok {
+		t.Error("Upsert with a key matching two rows succeeded")
+	}
+}
@@ -0,0 +1,32 @@
+package gormfunc
+
+import (
+	"fmt"
+	"gotulua/statefunc"
+
+	"gorm.io/gorm"
+)
+
+// GetVersion returns the schema version of the database kept in PRAGMA user_version,
+// 0 for a new database. Returns false on error with the reason in the last error.
+func GetVersion(db *gorm.DB) (int64, bool) {
+	statefunc.ClearErrors()
+	var version int64
+	if err := dbConn(db).Raw("PRAGMA user_version").Scan(&version).Error; err != nil {
+		statefunc.SetLastErrorText(err.Error())
+		return 0, false
+	}
+	return version, true
+}
+
+// SetVersion sets the schema version of the database kept in PRAGMA user_version.
+// Set in a transaction, the version is rolled back with the migration that failed.
+func SetVersion(db *gorm.DB, version int64) bool {
+	statefunc.ClearErrors()
+	// PRAGMA takes no bound parameters, the version is an integer so it is safe to format
+	if err := dbConn(db).Exec(fmt.Sprintf("PRAGMA user_version = %d", version)).Error; err != nil {
+		statefunc.SetLastErrorText(err.Error())
+		return false
+	}
+	return true
+}
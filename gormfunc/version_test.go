@@ -0,0 +1,45 @@
+package gormfunc
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestVersionIsKeptAndRolledBack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.db")
+	db, err := CreateDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version, ok := GetVersion(db); !ok || version != 0 {
+		t.Errorf("GetVersion of a new database = %d, %v, want 0, true", version, ok)
+	}
+	if !SetVersion(db, 3) {
+		t.Fatal("SetVersion failed")
+	}
+	if err := CloseDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	db = OpenDB(path)
+	defer CloseDB(db)
+	if version, _ := GetVersion(db); version != 3 {
+		t.Errorf("GetVersion after reopening = %d, want 3", version)
+	}
+
+	if err := BeginTransaction(db); err != nil {
+		t.Fatal(err)
+	}
+	if !SetVersion(db, 4) {
+		t.Fatal("SetVersion in the transaction failed")
+	}
+	if version, _ := GetVersion(db); version != 4 {
+		t.Errorf("GetVersion in the transaction = %d, want 4", version)
+	}
+	if err := RollbackTransaction(db); err != nil {
+		t.Fatal(err)
+	}
+	if version, _ := GetVersion(db); version != 3 {
+		t.Errorf("GetVersion after the rollback = %d, want 3", version)
+	}
+}
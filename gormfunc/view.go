@@ -0,0 +1,66 @@
+package gormfunc
+
+import (
+	"errors"
+	"fmt"
+	"gotulua/i18nfunc"
+	"gotulua/statefunc"
+
+	"gorm.io/gorm"
+)
+
+// CreateView creates a view from a SELECT statement.
+// The view can be opened with OpenTable and is read-only.
+func CreateView(db *gorm.DB, name, selectSQL string) error {
+	if name == SysMetaTable {
+		return errors.New(i18nfunc.T("error.db_can_not_create_sysmeta", map[string]interface{}{
+			"Name": name,
+		}))
+	}
+	if tableExists(db, name) || viewExists(db, name) {
+		return errors.New(i18nfunc.T("error.db_view_name_in_use", map[string]interface{}{
+			"Name": name,
+		}))
+	}
+	if err := dbConn(db).Exec(fmt.Sprintf("CREATE VIEW \"%s\" AS %s", name, selectSQL)).Error; err != nil {
+		return errors.New(i18nfunc.T("error.db_view_create_failed", map[string]interface{}{
+			"Name":  name,
+			"Error": err.Error(),
+		}))
+	}
+	return nil
+}
+
+// DropView drops a view, doing nothing if it does not exist
+func DropView(db *gorm.DB, name string) error {
+	if err := dbConn(db).Exec(fmt.Sprintf("DROP VIEW IF EXISTS \"%s\"", name)).Error; err != nil {
+		return errors.New(i18nfunc.T("error.db_view_drop_failed", map[string]interface{}{
+			"Name":  name,
+			"Error": err.Error(),
+		}))
+	}
+	return nil
+}
+
+// viewExists checks if a view exists in the database
+func viewExists(db *gorm.DB, name string) bool {
+	var count int64
+	db.Raw("SELECT count(*) FROM sqlite_master WHERE type='view' AND name=?", name).Count(&count)
+	return count > 0
+}
+
+// IsReadOnly reports whether the table is a view that can not be changed
+func (t *Table) IsReadOnly() bool {
+	return t.readOnly
+}
+
+// refuseWrite sets the last error and returns true if the table is read-only
+func (t *Table) refuseWrite() bool {
+	if !t.readOnly {
+		return false
+	}
+	statefunc.SetLastErrorText(i18nfunc.T("error.db_table_read_only", map[string]interface{}{
+		"Name": t.Name,
+	}))
+	return true
+}
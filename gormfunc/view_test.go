@@ -0,0 +1,53 @@
+package gormfunc
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestViewIsOpenedReadOnly(t *testing.T) {
+	db := newTestDB(t)
+	table := newTestTable(t, db, "Names", "n::Name;t::Text;l::20|n::Active;t::Integer")
+	insert(t, table, map[string]interface{}{"Name": "a", "Active": int64(1)})
+	insert(t, table, map[string]interface{}{"Name": "b", "Active": int64(0)})
+	insert(t, table, map[string]interface{}{"Name": "c", "Active": int64(1)})
+	if err := CreateView(db, "ActiveNames", "SELECT id, Name FROM Names WHERE Active = 1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := CreateView(db, "Names", "SELECT 1"); err == nil {
+		t.Error("a view was created with the name of a table")
+	}
+
+	view := OpenTable(db, "ActiveNames")
+	if view == nil || !view.IsReadOnly() {
+		t.Fatal("the view was not opened read-only")
+	}
+	if !slices.Contains(view.Columns, "Name") {
+		t.Errorf("the view columns are %q, want Name among them", view.Columns)
+	}
+	view.OrderBy("Name")
+	if !view.Find() || !slices.Equal(names(view), []string{"a", "c"}) {
+		t.Errorf("the view found %q, want a and c", names(view))
+	}
+
+	var id int64
+	if view.Insert(map[string]interface{}{"Name": "d"}, &id) {
+		t.Error("an insert into the view was done")
+	}
+	if view.Update(1, Record{"Name": "x"}) {
+		t.Error("an update of the view was done")
+	}
+	if view.DeleteRow() {
+		t.Error("a delete from the view was done")
+	}
+	if count, _ := table.Count(); count != 3 {
+		t.Errorf("the table has %d rows after the refused writes, want 3", count)
+	}
+
+	if err := DropView(db, "ActiveNames"); err != nil {
+		t.Fatal(err)
+	}
+	if viewExists(db, "ActiveNames") {
+		t.Error("the view was not dropped")
+	}
+}
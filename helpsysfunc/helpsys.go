@@ -152,7 +152,7 @@ func RegisterBrowseFunctions() {
 		FunctionHelp{
 			Name:        "AddField",
 			Parameters:  "<description> string",
-			Description: "AddField adds a field to the browse. Description is a string that contains field definitions separated by '|', where each field is defined by semicolon-separated key-value pairs (e.g., \"n::Name;c::Caption;f::Function;e::true;t::Type\"). Recognized keys are: \"n\": field name (required); \"c\": field caption (optional); \"f\": function name for computed fields (optional); \"e\": editable flag (\"true\" or \"false\", optional); \"t\": extra type information (optional). If a function is specified, AddFuncField is called; otherwise, AddTableField is used.",
+			Description: "AddField adds a field to the browse. Description is a string that contains field definitions separated by '|', where each field is defined by semicolon-separated key-value pairs (e.g., \"n::Name;c::Caption;f::Function;e::true;t::Type\"). Recognized keys are: \"n\": field name (required); \"c\": field caption (optional); \"f\": function name for computed fields (optional); \"e\": editable flag (\"true\" or \"false\", optional); \"t\": extra type information (optional); \"roles\": comma separated roles that may see the field (optional); \"eroles\": comma separated roles that may edit the field (optional); \"fmt\": function called with the value of a table field, whose result is shown while the stored and edited value stays the same (optional); \"w\": maximum number of characters shown in the column, F3 shows the full value of the selected cell (optional). If a function is specified, AddFuncField is called; otherwise, AddTableField is used.",
 			IsHeader:    false,
 		},
 		FunctionHelp{
@@ -161,12 +161,84 @@ func RegisterBrowseFunctions() {
 			Description: "SetFieldLookup sets the lookup for the field. LookupTable is the lookup browse, LookupFunc is the lookup function.",
 			IsHeader:    false,
 		},
+		FunctionHelp{
+			Name:        "SetFieldRoles",
+			Parameters:  "<fieldName> string, <viewRoles> string, [<editRoles> string]",
+			Description: "SetFieldRoles limits who sees and edits the field. The roles are comma separated names compared with the role set by SetRole. An empty list allows every role. Fields the role may not see are left out of the browse, fields it may not edit stay read-only.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "SetRowTag",
+			Parameters:  "<function> string",
+			Description: "SetRowTag sets a function called with the table for every row shown in the browse. Its result (a string, number or boolean) is kept with the row without being shown.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "GetRowTag",
+			Parameters:  "",
+			Description: "GetRowTag returns the tag of the selected row computed by the SetRowTag function, nil if the row has none. Use it in button functions to act on hidden row data.",
+			IsHeader:    false,
+		},
 		FunctionHelp{
 			Name:        "AddButton",
 			Parameters:  "<caption> string, <function> string",
 			Description: "AddButton adds a button to the browse. Caption is the button caption, function is the function to be called when the button is clicked.",
 			IsHeader:    false,
 		},
+		FunctionHelp{
+			Name:        "PressButton",
+			Parameters:  "<caption> string",
+			Description: "PressButton calls the function of the browse button with the caption with the table, as clicking the button does. Returns false if the browse has no button with the caption.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "SetShowPrimaryKey",
+			Parameters:  "<show> boolean",
+			Description: "SetShowPrimaryKey sets whether the id column is shown when the browse has no fields added. The default is true.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "SetShowFilterRow",
+			Parameters:  "<show> boolean",
+			Description: "SetShowFilterRow shows a row under the header with a filter cell for every table field. Type in a cell or press Enter to edit its filter, Delete clears it; the rows must match the filters of all columns. Call it before the browse is shown.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "SetFixedColumns",
+			Parameters:  "<n> int",
+			Description: "SetFixedColumns keeps the first n columns visible when the browse scrolls right, like the header row. 0 lets all columns scroll.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "SetColumnGroups",
+			Parameters:  "<groups> table",
+			Description: "SetColumnGroups shows a row of group captions above the column captions. Every group is {caption, firstField, lastField} and spans the columns of the fields from the first to the last; leave out the last field for one column. nil removes the groups.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "SetGroupTotals",
+			Parameters:  "<field> string",
+			Description: "SetGroupTotals shows a subtotal row after every group of rows with the same value of the field and a grand total row at the end, summing the integer and real columns. Order the table by the field to keep its groups together. The browse is read-only while it shows totals. nil removes the totals.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "SetColumnVisible",
+			Parameters:  "<field> string, <visible> boolean",
+			Description: "SetColumnVisible hides or shows a field added to the browse. It can be called while the browse is shown, the columns are rendered again and the other fields stay editable.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "SetOnOpen",
+			Parameters:  "<function> string",
+			Description: "SetOnOpen sets the function to be called with the table when the browse is shown.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "SetOnClose",
+			Parameters:  "<function> string",
+			Description: "SetOnClose sets the function to be called with the table when the browse is closed with Escape.",
+			IsHeader:    false,
+		},
 		FunctionHelp{
 			Name:        "Show",
 			Parameters:  "",
@@ -232,6 +304,246 @@ func RegisterTableFunctions() {
 			Description: "OrderBy orders the table by the specified field.",
 			IsHeader:    false,
 		},
+		FunctionHelp{
+			Name:        "SetFilterMode",
+			Parameters:  "<mode> string",
+			Description: "SetFilterMode sets how the filters of different fields combine: AND (the default) finds the rows matching all of them, OR the rows matching any of them.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "SaveFilter",
+			Parameters:  "<name> string",
+			Description: "SaveFilter stores the field and range filters of the table and its filter mode in the database under the name, replacing a filter saved before with the same name. Returns false on error.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "LoadFilter",
+			Parameters:  "<name> string",
+			Description: "LoadFilter replaces the filters of the table with the ones saved under the name. Call Find to load the rows they select. Returns false if the table has no filter with the name.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "ListFilters",
+			Parameters:  "",
+			Description: "ListFilters returns an array of the names of the filters saved for the table in alphabetical order, or nil on error.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "SetSoftDelete",
+			Parameters:  "<field> string",
+			Description: "SetSoftDelete makes deleting a row set the field to 1, and deleted_at to the current time if the table has it, instead of removing the row. Find leaves out the flagged rows. nil turns it off. Returns false if the field does not exist.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "IncludeDeleted",
+			Parameters:  "[include] boolean",
+			Description: "IncludeDeleted makes Find return the soft deleted rows too, or leave them out again with false.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "SetLimit",
+			Parameters:  "<n> integer",
+			Description: "SetLimit makes Find return at most n rows. 0 or less removes the limit.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "SetOffset",
+			Parameters:  "<n> integer",
+			Description: "SetOffset makes Find skip the first n rows, e.g. to show the next page. 0 or less removes the offset.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "SetOrderField",
+			Parameters:  "<field> string",
+			Description: "SetOrderField sets the integer field keeping the order of the rows chosen by the user. Find returns the rows in that order, new rows with the field unset or 0 go to the end, and browses move the selected row with Shift+Up and Shift+Down. Returns false if the field is not an integer field.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "MoveUp",
+			Parameters:  "",
+			Description: "MoveUp moves the current row one place up in the order set with SetOrderField, swapping it with the row before it. The change is saved and the current row stays on the moved row. Returns false on error; getLastError() tells why.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "MoveDown",
+			Parameters:  "",
+			Description: "MoveDown moves the current row one place down in the order set with SetOrderField, swapping it with the row after it.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "MoveTo",
+			Parameters:  "<position> integer",
+			Description: "MoveTo moves the current row to the position, counted from 1, among the rows found by Find; the rows in between shift by one place. All changes are saved in one transaction.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "SetFieldDefault",
+			Parameters:  "<field> string, <value> any",
+			Description: "SetFieldDefault sets the value the field gets in new rows. The value must match the field type; dates are given in the user format.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "SetCollation",
+			Parameters:  "<field> string, <collation> string",
+			Description: "SetCollation sets the collation used for the text field in filters and ordering: BINARY, NOCASE or RTRIM. NOCASE ignores the case of latin letters. An empty collation restores the default.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "DistinctValues",
+			Parameters:  "<field> string",
+			Description: "DistinctValues returns a sorted array of the distinct values of the field in the rows matching the current filters. Dates and booleans are returned in user format. Returns nil on error; getLastError() tells why.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "ExportCSV",
+			Parameters:  "<path> string",
+			Description: "ExportCSV writes the rows matching the current filters, in the current order, to a CSV file with a header line of the column names. Dates and booleans are written in user format. The rows loaded by Find stay loaded. Returns the number of rows written, or nil on error; getLastError() tells why.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "Sum",
+			Parameters:  "<field> string",
+			Description: "Sum returns the sum of the Integer or Real field in the rows matching the current filters, 0 if no row matches. A field of another type is a script error.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "Avg",
+			Parameters:  "<field> string",
+			Description: "Avg returns the average of the Integer or Real field in the rows matching the current filters, 0 if no row matches. A field of another type is a script error.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "Min",
+			Parameters:  "<field> string",
+			Description: "Min returns the smallest value of the field in the rows matching the current filters, nil if no row matches. Dates and booleans are returned in user format.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "Max",
+			Parameters:  "<field> string",
+			Description: "Max returns the largest value of the field in the rows matching the current filters, nil if no row matches. Dates and booleans are returned in user format.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "Count",
+			Parameters:  "",
+			Description: "Count returns the number of rows matching the current filters without loading them.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "RowCountLoaded",
+			Parameters:  "",
+			Description: "RowCountLoaded returns the number of rows loaded by the last Find, at most the limit set with SetLimit.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "HasMore",
+			Parameters:  "",
+			Description: "HasMore returns true if the last Find stopped at the limit set with SetLimit and more rows follow. Load the next page with SetOffset and Find.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "ToArray",
+			Parameters:  "",
+			Description: "ToArray returns the rows loaded by the last Find as an array of records with their fields in user format, so they can be used with the Lua table functions. Call Find first, with SetLimit to bound the rows; returns nil if Find was not called. The current row does not change.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "Upsert",
+			Parameters:  "<record> table, [keyFields] string",
+			Description: "Upsert updates the row whose keyFields (comma separated, the id if not given) have the values of the record, or inserts the record if there is no such row. Returns the id of the row and true if it was inserted. Returns nil on error, e.g. when the key matches more than one row; getLastError() tells why.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "DeleteWhere",
+			Parameters:  "[<force> bool]",
+			Description: "DeleteWhere deletes all rows matching the current filters in one statement and returns how many were deleted; with soft delete on they are flagged instead. The after delete function is not called. Without a filter it is a script error unless force is true. Returns nil on error; getLastError() tells why.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "Walk",
+			Parameters:  "<function> string",
+			Description: "Walk calls the function for every filtered row of the table. If the function returns true, the changed row is updated. All updates are done in one transaction. Returns true or false depending on the success and the number of updated rows.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "Transaction",
+			Parameters:  "<function> string",
+			Description: "Transaction calls the function with the table inside a transaction. The changes are committed when the function finishes and rolled back if the function fails or returns false. Returns true if the changes were committed.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "GetString",
+			Parameters:  "<field> string",
+			Description: "Returns the field value of the current row as a string.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "GetRawField",
+			Parameters:  "<field> string",
+			Description: "Returns the field value of the current row as it is stored, without the user format, e.g. dates as yyyymmdd and booleans as 1 or 0. Read transforms are not applied.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "GetRecord",
+			Parameters:  "",
+			Description: "Returns the current row as a Lua table of field names and values in user format, or nil if there is no current row. pairs() visits all fields, also the empty ones. Changing the record does not change the table; pass it to Upsert to save it.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "GetColumns",
+			Parameters:  "",
+			Description: "Returns the column names of the table as an array in their order in the table.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "GetFieldType",
+			Parameters:  "<field> string",
+			Description: "Returns the type of the field as it was created, like TEXT, INTEGER, REAL, DATE, TIME, DATETIME or BOOLEAN, or nil if the table has no such field.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "RecordKeys",
+			Parameters:  "<record> table",
+			Description: "Returns the field names of a record returned by GetRecord, or the string keys of any table, as an array in alphabetical order.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "GetInt",
+			Parameters:  "<field> string",
+			Description: "Returns the field value of the current row as an integer. Raises an error if the value is not an integer.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "GetFloat",
+			Parameters:  "<field> string",
+			Description: "Returns the field value of the current row as a number. Raises an error if the value is not numeric.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "GetBool",
+			Parameters:  "<field> string",
+			Description: "Returns the field value of the current row as a boolean. Raises an error if the value is not a boolean.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "GetDate",
+			Parameters:  "<field> string",
+			Description: "Returns the value of a date, time or datetime field of the current row as a string in the user format. Raises an error for other field types.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "SetReadTransform",
+			Parameters:  "<field> string, <function> string",
+			Description: "SetReadTransform sets the function applied to the field value when it is read. The function gets the value and returns the value to use. An empty function name removes the transform.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "SetWriteTransform",
+			Parameters:  "<field> string, <function> string",
+			Description: "SetWriteTransform sets the function applied to the field value before it is stored. The function gets the value and returns the value to store. An empty function name removes the transform.",
+			IsHeader:    false,
+		},
 		FunctionHelp{
 			Name:        "SetOnAfterDelete",
 			Parameters:  "<function> function",
@@ -250,6 +562,12 @@ func RegisterTableFunctions() {
 			Description: "SetOnAfterInsert sets the function to be called after a row is inserted.",
 			IsHeader:    false,
 		},
+		FunctionHelp{
+			Name:        "SetOnValidate",
+			Parameters:  "<function> function",
+			Description: "SetOnValidate sets the function to be called before a row is inserted or updated. The function gets the row and cancels the operation by returning false and an optional message.",
+			IsHeader:    false,
+		},
 	)
 }
 
@@ -258,13 +576,13 @@ func RegisterCommonFunctions() {
 		FunctionHelp{
 			Name:        "DBOpen",
 			Parameters:  "<path> string",
-			Description: "Opens a database connection. Returns a database object.",
+			Description: "Opens a database connection. Returns a database object. The path \":memory:\" opens a new empty database kept in memory, discarded by DBClose.",
 			IsHeader:    false,
 		},
 		FunctionHelp{
 			Name:        "DBClose",
 			Parameters:  "<db> Database object",
-			Description: "Closes a database connection.",
+			Description: "Closes a database connection. A transaction left open is rolled back. Databases still open when the application exits are closed the same way.",
 			IsHeader:    false,
 		},
 		FunctionHelp{
@@ -273,16 +591,22 @@ func RegisterCommonFunctions() {
 			Description: "Opens a table connection. Returns a table object.",
 			IsHeader:    false,
 		},
+		FunctionHelp{
+			Name:        "DBOpenFiltered",
+			Parameters:  "<db> Database object, <tableName> string, <field> string, <filter> string | <filters> table",
+			Description: "Opens a table, sets a filter on the field (or one filter per field of the table {field = filter}) in the format of SetFilter and finds the rows. Returns the table on the first matching row, or with no rows if nothing matches.",
+			IsHeader:    false,
+		},
 		FunctionHelp{
 			Name:        "DBCreate",
 			Parameters:  "<path> string",
-			Description: "Creates a database. Returns a database object.",
+			Description: "Creates a database. Returns a database object. The path \":memory:\" creates a database kept in memory, for scratch work and tests; it is discarded by DBClose.",
 			IsHeader:    false,
 		},
 		FunctionHelp{
 			Name:        "DBCreateTable",
 			Parameters:  "<db> Database object, <tableName> string, <description> string, <openIfExists> bool",
-			Description: "Creates a table. Returns a table object. Description is a string that contains field definitions separated by '|', where each field is defined by semicolon-separated key-value pairs (e.g., \"n::Name;t::Type;l::Length\").",
+			Description: "Creates a table. Returns a table object. Description is a string that contains field definitions separated by '|', where each field is defined by semicolon-separated key-value pairs (e.g., \"n::Name;t::Type;l::Length\"). \"fk::table.field\" makes the field reference a row of another table, and \"fkdel::Cascade\" or \"fkdel::SetNull\" sets what deleting that row does.",
 			IsHeader:    false,
 		},
 		FunctionHelp{
@@ -293,8 +617,152 @@ func RegisterCommonFunctions() {
 		},
 		FunctionHelp{
 			Name:        "DBDropTable",
+			Parameters:  "<db> Database object, <tableName> string, [confirm] bool",
+			Description: "Drops a table. If confirm is true, a confirmation dialog is shown first and the table is dropped only when it is accepted. In batch mode the table is dropped without asking. Returns true if the table was dropped, false if the dialog was declined.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "DBRenameTable",
+			Parameters:  "<db> Database object, <oldName> string, <newName> string",
+			Description: "Renames a table and moves its field metadata to the new name. Fails if a table with the new name exists. Returns the renamed table object.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "DBCreateView",
+			Parameters:  "<db> Database object, <viewName> string, <select> string",
+			Description: "Creates a view from a SELECT statement. Open it with DBOpenTable to read its rows; inserts, updates and deletes on a view fail. Returns true.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "DBDropView",
+			Parameters:  "<db> Database object, <viewName> string",
+			Description: "Drops a view. Nothing happens if the view does not exist. Returns true.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "SetConfirmDrop",
+			Parameters:  "<confirm> bool",
+			Description: "Sets whether DBDropTable called without the confirm argument asks before dropping a table. Off by default.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "SetTiming",
+			Parameters:  "<on> bool, [<logPath> string]",
+			Description: "Turns on or off the timing of the table operations Find, FindByID, Insert, Update, Delete and the aggregates Sum, Avg, Min, Max and Count. With a log path every operation is appended to the file with its table and duration. Returns false if the log can not be opened.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "GetLastTiming",
+			Parameters:  "",
+			Description: "Returns the duration of the last timed table operation in milliseconds, 0 when timing is off.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "DBTransaction",
+			Parameters:  "<db> Database object, <function> string",
+			Description: "Calls the function with the database inside a transaction. All tables of the database take part in it. The changes are committed when the function finishes and rolled back if the function fails or returns false. Returns true if the changes were committed.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "DBBegin",
+			Parameters:  "<db> Database object",
+			Description: "Opens a transaction on the database. The changes of all its tables are kept until DBCommit or undone by DBRollback, and their after insert, update and delete callbacks run on commit. Returns false if a transaction is already open.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "DBCommit",
+			Parameters:  "<db> Database object",
+			Description: "Commits the transaction opened by DBBegin. Returns false if there is none or the commit fails; getLastError() tells why.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "DBRollback",
+			Parameters:  "<db> Database object",
+			Description: "Undoes the changes of the transaction opened by DBBegin. Their after callbacks are not called. Returns false if there is no transaction.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "DBExecScript",
+			Parameters:  "<db> Database object, <path> string",
+			Description: "Runs the statements of a SQL file in one transaction. If a statement fails all changes are rolled back and false is returned; getLastError() tells which statement failed.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "DBQuery",
+			Parameters:  "<db> Database object, <sql> string, [<args>...]",
+			Description: "Runs a SQL query, e.g. with joins, subqueries or window functions, and returns an array of rows, each a table by column name. The arguments after the query are bound to its ? placeholders; never build the SQL from user input. Values come as stored: dates as yyyymmdd and booleans as 1 and 0. Returns nil on error; getLastError() tells why.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "DBExec",
+			Parameters:  "<db> Database object, <sql> string, [<args>...]",
+			Description: "Runs a SQL statement such as UPDATE or DELETE with the arguments after it bound to its ? placeholders and returns the number of changed rows. Table callbacks are not called. Returns nil on error; getLastError() tells why.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "DBImportCSV",
+			Parameters:  "<table> Table, <path> string",
+			Description: "Inserts the rows of a CSV file into the table, like the files written by ExportCSV. The header line names the columns; cells are in user format and empty cells get the field default. All rows are inserted in one transaction: if a row fails, none is kept. Returns the number of rows inserted, or nil on error; getLastError() tells the failing line.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "DBExportSchema",
+			Parameters:  "<db> Database object",
+			Description: "Returns the structure of all tables of the database, one line per table in the form name=structure, where structure is the format of DBCreateTable.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "DBImportSchema",
+			Parameters:  "<db> Database object, <schema> string",
+			Description: "Creates the tables of a schema returned by DBExportSchema that do not exist in the database. Returns the number of created tables.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "DBExportDump",
+			Parameters:  "<db> Database object, <path> string",
+			Description: "Writes the tables of the database with their rows to a SQL file as CREATE TABLE and INSERT statements. Run the file with DBExecScript to copy the data into another database. Returns false if the file can not be written; getLastError() tells why.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "LastInsertID",
+			Parameters:  "<table> Table object",
+			Description: "Returns the id of the last row inserted through the table, or 0 if nothing was inserted.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "NextSequence",
+			Parameters:  "<db> Database object, <name> string",
+			Description: "Increments the named counter of the database and returns its new value. Counters start at 1 and are kept in the table_sequences table; scripts sharing the database never get the same number. Returns nil on errors, see getLastError().",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "DBResetSequence",
+			Parameters:  "<db> Database object, <tableName> string, <value> int",
+			Description: "Sets the id the next row inserted into the table gets, e.g. 1 after the table was cleared for tests or imports. The value must be above the largest id in the table. Returns false if the table does not exist or has no AUTOINCREMENT key; getLastError() tells why.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "DBTableExists",
 			Parameters:  "<db> Database object, <tableName> string",
-			Description: "Drops a table.",
+			Description: "Returns true if the database has the table, false otherwise.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "DBColumnExists",
+			Parameters:  "<db> Database object, <tableName> string, <columnName> string",
+			Description: "Returns true if the table has the column, false if it has not or there is no such table.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "DBGetVersion",
+			Parameters:  "<db> Database object",
+			Description: "Returns the schema version of the database kept in PRAGMA user_version, 0 for a new database. Returns nil on error.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "DBSetVersion",
+			Parameters:  "<db> Database object, <version> int",
+			Description: "Sets the schema version of the database, e.g. after a migration: if DBGetVersion(db) < 2 then ... DBSetVersion(db, 2) end. Inside DBTransaction the version is rolled back with the migration. Returns false on error.",
 			IsHeader:    false,
 		},
 		FunctionHelp{
@@ -342,7 +810,7 @@ func RegisterCommonFunctions() {
 		FunctionHelp{
 			Name:        "DateDiff",
 			Parameters:  "<date1> string, <date2> string, <mode> string",
-			Description: "Calculates the difference between two dates. mode can be 'd', 'D', 'm', 'M', 'y', 'Y'.",
+			Description: "Calculates the difference between two dates. mode can be 'd', 'D' (days), 'w', 'W' (weeks), 'm', 'M' (months), 'q', 'Q' (quarters), 'y', 'Y' (years).",
 			IsHeader:    false,
 		},
 		FunctionHelp{
@@ -354,13 +822,139 @@ func RegisterCommonFunctions() {
 		FunctionHelp{
 			Name:        "DateAdd",
 			Parameters:  "<date> string, <year> int, <month> int, <day> int",
-			Description: "Adds a specified number of years, months, and days to a date. year, month, day can be positive or negative.",
+			Description: "Adds a specified number of years, months, and days to a date and returns the new date. year, month, day can be positive or negative, use 0 for the parts not to change. Example: DateAdd(d, 0, 1, 0) adds one month.",
 			IsHeader:    false,
 		},
 		FunctionHelp{
 			Name:        "TimeAdd",
 			Parameters:  "<time> string, <hour> int, <minute> int, <second> int",
-			Description: "Adds a specified number of hours, minutes, and seconds to a time. hour, minute, second can be positive or negative.",
+			Description: "Adds a specified number of hours, minutes, and seconds to a time and returns the new time. hour, minute, second can be positive or negative, use 0 for the parts not to change. Example: TimeAdd(t, 0, 30, 0) adds half an hour.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "DayOfWeek",
+			Parameters:  "<date> string",
+			Description: "Returns the day of the week of a date, 1 for Monday to 7 for Sunday. Returns -1 if the date is not in the format set by SetDateFormat.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "WeekNumber",
+			Parameters:  "<date> string",
+			Description: "Returns the ISO 8601 week number of a date. Early January days can be in week 52 or 53 of the year before. Returns -1 if the date is not valid.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "Today",
+			Parameters:  "",
+			Description: "Returns the current date in the format specified by SetDateFormat, like Date.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "Yesterday",
+			Parameters:  "",
+			Description: "Returns the date before today in the format specified by SetDateFormat.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "Tomorrow",
+			Parameters:  "",
+			Description: "Returns the date after today in the format specified by SetDateFormat.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "StartOfMonth",
+			Parameters:  "[date] string",
+			Description: "Returns the first day of the month of the date, of the current month if no date is given. Returns an empty string if the date is not valid.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "EndOfMonth",
+			Parameters:  "[date] string",
+			Description: "Returns the last day of the month of the date, of the current month if no date is given. Handles February of leap years. Returns an empty string if the date is not valid.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "StartOfWeek",
+			Parameters:  "[date] string",
+			Description: "Returns the Monday of the week of the date, of the current week if no date is given. Returns an empty string if the date is not valid.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "IsLeapYear",
+			Parameters:  "<year> int",
+			Description: "Returns true if February of the year has 29 days. 1900 is not a leap year, 2000 is.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "DaysInMonth",
+			Parameters:  "<year> int, <month> int",
+			Description: "Returns the number of days of the month (1-12) in the year, e.g. DaysInMonth(2024, 2) returns 29.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "FormatNumber",
+			Parameters:  "<value> number, [decimals] int, [thousandsSep] string, [decimalSep] string",
+			Description: "Formats a number with the given number of decimals, grouping thousands with thousandsSep (e.g. FormatNumber(1234567.891, 2, \" \", \",\") returns \"1 234 567,89\").",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "ParseNumber",
+			Parameters:  "<text> string, [thousandsSep] string, [decimalSep] string",
+			Description: "Converts a formatted number back to a number. Returns nil if the text is not a number.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "SetNumberFormat",
+			Parameters:  "<decimals> int, [thousandsSep] string, [decimalSep] string",
+			Description: "Sets the format used to show Integer and Real fields in browses. Integer fields are shown without decimals.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "Random",
+			Parameters:  "<min> int, <max> int",
+			Description: "Returns a random integer from min to max, both included.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "RandomFloat",
+			Parameters:  "",
+			Description: "Returns a random number from 0 up to, but not including, 1.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "Split",
+			Parameters:  "<s> string, <sep> string",
+			Description: "Splits s at every sep and returns the parts as an array. An empty sep splits s into characters (e.g. Split(\"a,b,c\", \",\") returns {\"a\", \"b\", \"c\"}).",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "Join",
+			Parameters:  "<items> table, [sep] string",
+			Description: "Joins the items of an array into a string with sep between them. Numbers and booleans are converted like tostring does.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "Format",
+			Parameters:  "<fmt> string, [values] any...",
+			Description: "Formats the values like Go's fmt.Sprintf (e.g. Format(\"%s: %05.1f\", \"Total\", 3.14159) returns \"Total: 003.1\"). Whole numbers can be used with %d and %x.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "PadLeft",
+			Parameters:  "<s> string, <n> int, [ch] string",
+			Description: "Puts ch (a space by default) before s until it is n characters long (e.g. PadLeft(\"7\", 3, \"0\") returns \"007\"). Longer strings are returned as they are.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "PadRight",
+			Parameters:  "<s> string, <n> int, [ch] string",
+			Description: "Puts ch (a space by default) after s until it is n characters long.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "UUID",
+			Parameters:  "",
+			Description: "Returns a random (version 4) UUID string, e.g. for unique keys.",
 			IsHeader:    false,
 		},
 		FunctionHelp{
@@ -375,6 +969,18 @@ func RegisterCommonFunctions() {
 			Description: "Adds a lookup browse to the table.",
 			IsHeader:    false,
 		},
+		FunctionHelp{
+			Name:        "SetInputTheme",
+			Parameters:  "<placeholder> string, [text] string, [background] string",
+			Description: "Sets the placeholder, text and background colors of the edit inputs of browses and forms. Colors are names like \"yellow\" or values like \"#FFD700\"; an empty color keeps the current one.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "SetFunctionTimeout",
+			Parameters:  "<seconds> number",
+			Description: "Sets how long lookup, field and RunWhenIdle functions may run before they are aborted with an error. 0 disables the limit. Default is 5 seconds.",
+			IsHeader:    false,
+		},
 		// FunctionHelp{
 		// 	Name:        "AddForm",
 		// 	Parameters:  "<caption> string",
@@ -383,14 +989,86 @@ func RegisterCommonFunctions() {
 		// },
 		FunctionHelp{
 			Name:        "Confirm",
-			Parameters:  "<message> string",
-			Description: "Shows a confirmation dialog.",
+			Parameters:  "<message> string, [data] table",
+			Description: "Shows a confirmation dialog. The message can be a key of the translation files; placeholders like {{.Name}} in it are filled from the data table.",
 			IsHeader:    false,
 		},
 		FunctionHelp{
 			Name:        "Message",
-			Parameters:  "<message> string",
-			Description: "Shows a message dialog.",
+			Parameters:  "<message> string, [data] table",
+			Description: "Shows a message dialog. The message can be a key of the translation files, like in Message(\"app.saved\", {Count = 5}); placeholders like {{.Count}} in the translation or in a plain message are filled from the data table.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "PickList",
+			Parameters:  "<title> string, <items> table",
+			Description: "Shows a list of strings to choose from and waits for the user. Returns the index and the value of the chosen item, or nil if the list was closed with Escape or the script runs with -batch.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "SetAppTitle",
+			Parameters:  "<text> string",
+			Description: "Shows the text as a title above the browses and forms of the script. An empty text hides the title.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "GetAppTitle",
+			Parameters:  "",
+			Description: "Returns the title set with SetAppTitle.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "ShowInfoBar",
+			Parameters:  "<show> boolean",
+			Description: "Shows or hides the info bar with the titles of the browses and forms under them. F8 hides and shows the info bar and the button bar together.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "ShowButtonBar",
+			Parameters:  "<show> boolean",
+			Description: "Shows or hides the bar with the buttons of the browses that have them, to leave more rows for the data on small terminals.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "SetRole",
+			Parameters:  "<name> string",
+			Description: "Sets the role of the user. Browses shown afterwards hide and lock the fields limited to other roles with SetFieldRoles or the roles:: and eroles:: keys of AddField.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "GetRole",
+			Parameters:  "",
+			Description: "Returns the role set with SetRole, an empty string when none is set.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "BindKey",
+			Parameters:  "<action> string, <keys> string",
+			Description: "Binds the keys to an action like editor.save or browse.filter, replacing its keys. Keys are comma separated, like \"Ctrl+S,F2\".",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "SetErrorHandler",
+			Parameters:  "<function> string",
+			Description: "Sets a function called with every error before it is shown. It gets a table with message, type (\"script\" or \"data\"), fatal, and script and line when known. If it returns true the error is not shown; errors that stop the script still stop it. nil removes the handler.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "SetStatus",
+			Parameters:  "<text> string",
+			Description: "Shows the text in a status line below the browses and forms. It stays when you switch between them. An empty text hides the line.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "GetStatus",
+			Parameters:  "",
+			Description: "Returns the text of the status line.",
+			IsHeader:    false,
+		},
+		FunctionHelp{
+			Name:        "RunWhenIdle",
+			Parameters:  "<function> string",
+			Description: "Calls the function once, after the current key press or callback is handled. Use it to defer work from event handlers. Called from a script, it runs after the script ends. Like lookup and field functions, it is aborted with an error after the SetFunctionTimeout limit, 5 seconds by default.",
 			IsHeader:    false,
 		},
 		// FunctionHelp{
@@ -415,8 +1093,6 @@ func RegisterCommonFunctions() {
 
 }
 
-var currentDialog tview.Primitive
-
 func ShowHelp(fromEditor bool, callback func(functionName string)) {
 	list := tview.NewList().
 		ShowSecondaryText(true).
@@ -450,7 +1126,7 @@ func ShowHelp(fromEditor bool, callback func(functionName string)) {
 			}
 			functionCall := fmt.Sprintf("%s(%s)", mainText, strings.Join(placeholders, ", "))
 			callback(functionCall)
-			closeDialog(fromEditor)
+			closeDialog()
 		}
 	})
 
@@ -458,7 +1134,7 @@ func ShowHelp(fromEditor bool, callback func(functionName string)) {
 
 	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		if event.Key() == tcell.KeyEscape {
-			closeDialog(fromEditor)
+			closeDialog()
 			return nil
 		}
 		return event
@@ -467,23 +1143,16 @@ func ShowHelp(fromEditor bool, callback func(functionName string)) {
 	showDialog(list, 120, 40)
 }
 
-// showDialog displays a dialog with the given content and dimensions
+// showDialog displays a dialog with the given content and dimensions.
+// It goes on the dialog stack over the main view.
 func showDialog(content tview.Primitive, width, height int) {
 	modal := tview.NewFlex().
 		AddItem(content, 0, 1, true)
 
-	currentDialog = modal
-	statefunc.App.SetRoot(modal, true)
+	statefunc.PushDialog(modal, content, statefunc.MainFlex)
 }
 
-// closeDialog closes the current dialog and restores the previous view
-func closeDialog(fromEditor bool) {
-	if currentDialog != nil {
-		if fromEditor {
-			statefunc.App.SetRoot(statefunc.EditorFlex, true)
-		} else {
-			statefunc.App.SetRoot(statefunc.MainFlex, true)
-		}
-		currentDialog = nil
-	}
+// closeDialog closes the help and restores the previous view
+func closeDialog() {
+	statefunc.PopDialog()
 }
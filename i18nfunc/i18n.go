@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"text/template"
 
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"golang.org/x/text/language"
@@ -93,3 +95,30 @@ func T(messageID string, templateData map[string]interface{}) string {
 	}
 	return msg
 }
+
+// Text returns the translation of the message id like T. A text that is not a message id
+// is returned as it is, with placeholders like {{.Name}} filled from the data, so scripts
+// can pass their own texts as well as keys of the translation files.
+func Text(idOrText string, templateData map[string]interface{}) string {
+	if localizer != nil {
+		msg, err := localizer.Localize(&i18n.LocalizeConfig{
+			MessageID:    idOrText,
+			TemplateData: templateData,
+		})
+		if err == nil {
+			return msg
+		}
+	}
+	if templateData == nil || !strings.Contains(idOrText, "{{") {
+		return idOrText
+	}
+	tmpl, err := template.New("text").Parse(idOrText)
+	if err != nil {
+		return idOrText
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, templateData); err != nil {
+		return idOrText
+	}
+	return b.String()
+}
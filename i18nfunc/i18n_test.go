@@ -0,0 +1,25 @@
+package i18nfunc
+
+import "testing"
+
+func TestTextTranslatesKeysAndFillsPlainTexts(t *testing.T) {
+	if err := InitI18n("en"); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		text string
+		data map[string]interface{}
+		want string
+	}{
+		{"error.db_foreign_key_failed", map[string]interface{}{"Table": "Lines"}, "The change to table Lines breaks a reference between tables"},
+		{"Saved {{.Count}} rows", map[string]interface{}{"Count": 5}, "Saved 5 rows"},
+		{"Saved {{.Count}} rows", nil, "Saved {{.Count}} rows"},
+		{"Total: {{.Sum}", map[string]interface{}{"Sum": 1}, "Total: {{.Sum}"}, // Not a valid template
+		{"Plain text", nil, "Plain text"},
+	}
+	for _, tt := range tests {
+		if got := Text(tt.text, tt.data); got != tt.want {
+			t.Errorf("Text(%q, %v) = %q, want %q", tt.text, tt.data, got, tt.want)
+		}
+	}
+}
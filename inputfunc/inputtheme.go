@@ -0,0 +1,40 @@
+package inputfunc
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Colors of the edit inputs of browses and forms
+var (
+	placeholderColor = tcell.ColorYellow
+	fieldTextColor   = tview.Styles.PrimaryTextColor
+	fieldBackground  = tview.Styles.ContrastBackgroundColor
+)
+
+// SetInputTheme sets the placeholder, text and background colors of edit inputs.
+// Inputs created afterwards use the new colors.
+func SetInputTheme(placeholder, text, background tcell.Color) {
+	placeholderColor = placeholder
+	fieldTextColor = text
+	fieldBackground = background
+}
+
+// GetInputTheme returns the placeholder, text and background colors of edit inputs
+func GetInputTheme() (placeholder, text, background tcell.Color) {
+	return placeholderColor, fieldTextColor, fieldBackground
+}
+
+// ApplyInputTheme colors the input with the current input theme
+func ApplyInputTheme(input *tview.InputField) {
+	input.SetPlaceholderTextColor(placeholderColor)
+	input.SetFieldTextColor(fieldTextColor)
+	input.SetFieldBackgroundColor(fieldBackground)
+}
+
+// ApplyFormTheme colors the input fields of the form with the current input theme.
+// A form sets the field colors of its items when it is drawn, so they are set on the form.
+func ApplyFormTheme(form *tview.Form) {
+	form.SetFieldTextColor(fieldTextColor)
+	form.SetFieldBackgroundColor(fieldBackground)
+}
@@ -0,0 +1,262 @@
+package keymapfunc
+
+import (
+	"bufio"
+	"errors"
+	"gotulua/i18nfunc"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Actions that can be bound to keys
+const (
+	EditorSave         = "editor.save"
+	EditorSaveAs       = "editor.save_as"
+	EditorUndo         = "editor.undo"
+	EditorRedo         = "editor.redo"
+	EditorCopy         = "editor.copy"
+	EditorPaste        = "editor.paste"
+	EditorRunSelection = "editor.run_selection"
+	EditorFindNext     = "editor.find_next"
+	EditorHelp         = "editor.help"
+	EditorRun          = "editor.run"
+	EditorSaveAndRun   = "editor.save_run"
+	EditorFormat       = "editor.format"
+	EditorGoToLine     = "editor.goto_line"
+	EditorComment      = "editor.comment"
+	BrowseDeleteRow    = "browse.delete_row"
+	BrowseFilter       = "browse.filter"
+	BrowseExport       = "browse.export"
+	BrowseViewValue    = "browse.view_value"
+	BrowseMoveUp       = "browse.move_up"
+	BrowseMoveDown     = "browse.move_down"
+	BrowseToggleMark   = "browse.toggle_mark"
+	BrowseBulkSet      = "browse.bulk_set"
+	AppToggleRun       = "app.toggle_run"
+	AppToggleBars      = "app.toggle_bars"
+)
+
+// defaultKeys are the keys of the actions when nothing is configured.
+// An action can have several keys separated by commas.
+var defaultKeys = map[string]string{
+	EditorSave:         "Ctrl+S",
+	EditorSaveAs:       "Ctrl+Shift+S",
+	EditorUndo:         "Ctrl+Z",
+	EditorRedo:         "Ctrl+Y",
+	EditorCopy:         "Insert",
+	EditorPaste:        "Ctrl+V,Shift+Insert",
+	EditorRunSelection: "Ctrl+Enter,Ctrl+J", // Many terminals send Ctrl+Enter as Ctrl+J
+	EditorFindNext:     "F3,F4",
+	EditorHelp:         "F1,F2",
+	EditorRun:          "F5",
+	EditorSaveAndRun:   "Ctrl+R",             // Ctrl+Shift+R comes as Ctrl+R in most terminals
+	EditorFormat:       "Ctrl+Shift+F,Alt+F", // Alt+F for terminals sending Ctrl+Shift+F as Ctrl+F
+	EditorGoToLine:     "Ctrl+G",
+	EditorComment:      "Ctrl+/,Ctrl+_", // Most terminals send Ctrl+/ as Ctrl+_
+	BrowseDeleteRow:    "Delete",
+	BrowseFilter:       "F7",
+	BrowseExport:       "Ctrl+E",
+	BrowseViewValue:    "F3",
+	BrowseMoveUp:       "Shift+Up",
+	BrowseMoveDown:     "Shift+Down",
+	BrowseToggleMark:   "Space",
+	BrowseBulkSet:      "F9",
+	AppToggleRun:       "F6",
+	AppToggleBars:      "F8",
+}
+
+// combo is one key with its modifiers
+type combo struct {
+	key         tcell.Key
+	ch          rune
+	mod         tcell.ModMask
+	ctrlImplied bool // Ctrl+letter keys have their own key codes, terminals do not always report the Ctrl modifier
+}
+
+var mu sync.RWMutex
+var bindings = map[string][]combo{}
+
+func init() {
+	Reset()
+}
+
+// Reset restores the default keys of all actions
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	bindings = map[string][]combo{}
+	for action, keys := range defaultKeys {
+		combos, err := parseKeys(keys)
+		if err != nil {
+			panic(err) // the defaults are fixed
+		}
+		bindings[action] = combos
+	}
+}
+
+// Actions returns the names of all actions that can be bound, sorted
+func Actions() []string {
+	actions := make([]string, 0, len(defaultKeys))
+	for action := range defaultKeys {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+// Bind sets the keys of the action, replacing its previous keys.
+// keys is a comma separated list like "Ctrl+S" or "F3,F4".
+func Bind(action, keys string) error {
+	if _, ok := defaultKeys[action]; !ok {
+		return errors.New(i18nfunc.T("error.keymap_unknown_action", map[string]interface{}{
+			"Action": action,
+		}))
+	}
+	combos, err := parseKeys(keys)
+	if err != nil {
+		return err
+	}
+	mu.Lock()
+	bindings[action] = combos
+	mu.Unlock()
+	return nil
+}
+
+// Matches reports whether the key event is one of the keys of the action
+func Matches(action string, event *tcell.EventKey) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, c := range bindings[action] {
+		if c.matches(event) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c combo) matches(event *tcell.EventKey) bool {
+	if event.Key() != c.key {
+		return false
+	}
+	if c.key == tcell.KeyRune && unicode.ToLower(event.Rune()) != unicode.ToLower(c.ch) {
+		return false
+	}
+	mask := tcell.ModShift | tcell.ModAlt | tcell.ModCtrl
+	if c.ctrlImplied {
+		mask &^= tcell.ModCtrl
+	}
+	return event.Modifiers()&mask == c.mod&mask
+}
+
+// Load reads key bindings from a file with one "action = keys" line per action.
+// Empty lines and lines starting with # are skipped, actions not in the file keep their keys.
+func Load(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		action, keys, found := strings.Cut(line, "=")
+		if !found {
+			return errors.New(i18nfunc.T("error.keymap_bad_line", map[string]interface{}{
+				"Path": path,
+				"Line": lineNo,
+			}))
+		}
+		if err := Bind(strings.TrimSpace(action), strings.TrimSpace(keys)); err != nil {
+			return errors.New(i18nfunc.T("error.keymap_file", map[string]interface{}{
+				"Path":  path,
+				"Line":  lineNo,
+				"Error": err.Error(),
+			}))
+		}
+	}
+	return scanner.Err()
+}
+
+// parseKeys parses a comma separated list of keys
+func parseKeys(keys string) ([]combo, error) {
+	var combos []combo
+	for _, k := range strings.Split(keys, ",") {
+		if k = strings.TrimSpace(k); k == "" {
+			continue
+		}
+		c, err := parseKey(k)
+		if err != nil {
+			return nil, err
+		}
+		combos = append(combos, c)
+	}
+	if len(combos) == 0 {
+		return nil, invalidKey(keys)
+	}
+	return combos, nil
+}
+
+// parseKey parses a key like "Ctrl+Shift+S", "F5", "Alt+x", "Space" or "Insert"
+func parseKey(s string) (combo, error) {
+	parts := strings.Split(s, "+")
+	name := strings.TrimSpace(parts[len(parts)-1])
+	if name == "" && len(parts) > 1 {
+		name = "+" // "Ctrl++"
+		parts = parts[:len(parts)-1]
+	}
+	var c combo
+	for _, m := range parts[:len(parts)-1] {
+		switch strings.ToLower(strings.TrimSpace(m)) {
+		case "ctrl":
+			c.mod |= tcell.ModCtrl
+		case "alt":
+			c.mod |= tcell.ModAlt
+		case "shift":
+			c.mod |= tcell.ModShift
+		case "meta":
+			c.mod |= tcell.ModMeta
+		default:
+			return c, invalidKey(s)
+		}
+	}
+	if strings.EqualFold(name, "Space") {
+		name = " " // tcell has no key name for the space bar
+	}
+	if utf8.RuneCountInString(name) == 1 {
+		r, _ := utf8.DecodeRuneInString(name)
+		upper := unicode.ToUpper(r)
+		// Ctrl with a letter or one of \ ] ^ _ is a control character with its own key code
+		if c.mod&tcell.ModCtrl != 0 && (upper >= 'A' && upper <= 'Z' || upper >= '\\' && upper <= '_') {
+			c.key = tcell.KeyCtrlA + tcell.Key(upper-'A')
+			c.ctrlImplied = true
+			return c, nil
+		}
+		c.key = tcell.KeyRune
+		c.ch = r
+		return c, nil
+	}
+	for k, n := range tcell.KeyNames {
+		if strings.EqualFold(n, name) && !strings.HasPrefix(n, "Ctrl-") {
+			c.key = k
+			return c, nil
+		}
+	}
+	return c, invalidKey(s)
+}
+
+func invalidKey(s string) error {
+	return errors.New(i18nfunc.T("error.keymap_invalid_key", map[string]interface{}{
+		"Key": s,
+	}))
+}
@@ -0,0 +1,222 @@
+package luafunc
+
+import (
+	"fmt"
+	"gotulua/errorhandlefunc"
+	"gotulua/i18nfunc"
+	"gotulua/statefunc"
+	"strings"
+
+	"github.com/Shopify/go-lua"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// consoleChunkName is the chunk name of the statements run in the console
+const consoleChunkName = "console"
+
+// Console evaluates Lua statements typed in an input line against the running interpreter
+// and shows their results, printed text and errors in a scrollback view.
+type Console struct {
+	*tview.Flex
+	output     *tview.TextView
+	input      *tview.InputField
+	pending    []string // Lines of a statement that is not complete yet
+	history    []string // Statements entered, the last one at the end
+	historyPos int      // Position while walking the history, len(history) when not walking
+}
+
+// console is the console shown by ShowConsole, it keeps its scrollback between openings
+var console *Console
+
+// NewConsole creates a console evaluating the statements with the global interpreter
+func NewConsole() *Console {
+	c := &Console{
+		Flex:   tview.NewFlex().SetDirection(tview.FlexRow),
+		output: tview.NewTextView().SetDynamicColors(true).SetScrollable(true).SetWrap(true),
+	}
+	c.output.SetBorder(true).SetTitle(i18nfunc.T("console.title", nil))
+	// Follow the end of the output until the user scrolls up; the text view keeps doing it
+	// on every draw, so nothing has to run from the goroutine writing the output
+	c.output.ScrollToEnd()
+	c.input = tview.NewInputField().SetLabel("> ").SetFieldWidth(0)
+	c.input.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			c.Submit(c.input.GetText())
+		}
+	})
+	c.input.SetInputCapture(c.historyInput)
+	c.AddItem(c.output, 0, 1, false)
+	c.AddItem(c.input, 1, 0, true)
+	return c
+}
+
+// ShowConsole shows the console on top of the current view, Escape goes back to it
+func ShowConsole() {
+	if console == nil {
+		console = NewConsole()
+	}
+	if root := statefunc.MainFlex; root != nil && root.HasFocus() {
+		statefunc.PushVisual(root)
+	}
+	statefunc.App.SetRoot(console, true)
+	statefunc.App.SetFocus(console.input)
+	if statefunc.IsScriptAsync() {
+		console.write("[red]" + tview.Escape(i18nfunc.T("console.script_running", nil)) + "[-]")
+	}
+}
+
+// Submit takes a line typed in the input. The line is kept while the statement is incomplete,
+// and the whole statement is run once it is complete. Nothing is run while a script started
+// from the editor runs on the same interpreter, the line stays in the input to be run later.
+func (c *Console) Submit(line string) {
+	if statefunc.IsScriptAsync() {
+		c.write("[red]" + tview.Escape(i18nfunc.T("console.script_running", nil)) + "[-]")
+		return
+	}
+	c.input.SetText("")
+	prompt := "> "
+	if len(c.pending) > 0 {
+		prompt = ">> "
+	}
+	c.write("[gray]" + prompt + tview.Escape(line) + "[-]")
+	if len(c.pending) == 0 && strings.TrimSpace(line) == "" {
+		return
+	}
+	c.pending = append(c.pending, line)
+	code := strings.Join(c.pending, "\n")
+	out, incomplete := EvalConsoleCode(statefunc.L, code, func(text string) {
+		c.write(tview.Escape(text))
+	})
+	if incomplete {
+		c.input.SetLabel(">> ")
+		return
+	}
+	c.pending = nil
+	c.input.SetLabel("> ")
+	c.history = append(c.history, code)
+	c.historyPos = len(c.history)
+	if out.err != "" {
+		c.write("[red]" + tview.Escape(out.err) + "[-]")
+	} else if out.results != "" {
+		c.write(tview.Escape(out.results))
+	}
+}
+
+// historyInput walks through the statements entered with the Up and Down keys
+func (c *Console) historyInput(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyUp:
+		if c.historyPos > 0 {
+			c.historyPos--
+			c.input.SetText(c.history[c.historyPos])
+		}
+		return nil
+	case tcell.KeyDown:
+		if c.historyPos < len(c.history)-1 {
+			c.historyPos++
+			c.input.SetText(c.history[c.historyPos])
+		} else {
+			c.historyPos = len(c.history)
+			c.input.SetText("")
+		}
+		return nil
+	}
+	return event
+}
+
+// write adds a line to the scrollback
+func (c *Console) write(line string) {
+	fmt.Fprintln(c.output, line)
+}
+
+// consoleOutput is the outcome of a statement run in the console
+type consoleOutput struct {
+	results string // Values returned by the statement, formatted with FormatConsoleResults
+	err     string // Error raised by the statement, "" if it ran
+}
+
+// EvalConsoleCode runs the code typed in the console. An expression is evaluated and its
+// values are returned, other code runs as statements. Text printed by the code and errors
+// shown by the API functions are passed to write while it runs. incomplete is set when the
+// code is the start of a statement that goes on in the next lines, and nothing is run.
+func EvalConsoleCode(L *lua.State, code string, write func(string)) (out consoleOutput, incomplete bool) {
+	top := L.Top()
+	defer func() {
+		if r := recover(); r != nil {
+			out = consoleOutput{err: fmt.Sprint(r)}
+		}
+		L.SetTop(top)
+	}()
+	if lua.LoadBuffer(L, "return "+code, "="+consoleChunkName, "") != nil {
+		L.Pop(1)
+		if err := lua.LoadBuffer(L, code, "="+consoleChunkName, ""); err != nil {
+			msg, _ := L.ToString(-1)
+			if IsIncompleteChunk(msg) {
+				return consoleOutput{}, true
+			}
+			return consoleOutput{err: msg}, false
+		}
+	}
+
+	// print and the errors of the API functions go to the console while the code runs
+	L.Global("print")
+	L.PushGoFunction(func(L *lua.State) int {
+		write(formatValues(L, 1, L.Top()))
+		return 0
+	})
+	L.SetGlobal("print")
+	defer func() {
+		L.PushValue(top + 2) // the original print, kept under the chunk
+		L.SetGlobal("print")
+	}()
+	errorhandlefunc.SetErrorSink(write)
+	defer errorhandlefunc.SetErrorSink(nil)
+	statefunc.ClearErrorRun()
+
+	L.PushValue(-2) // the loaded chunk, above the original print
+	if err := L.ProtectedCall(0, lua.MultipleReturns, 0); err != nil {
+		msg, ok := L.ToString(-1)
+		if !ok {
+			msg = err.Error()
+		}
+		L.Pop(1)
+		return consoleOutput{err: msg}, false
+	}
+	results := FormatConsoleResults(L, top+2)
+	L.SetTop(top + 2)
+	return consoleOutput{results: results}, false
+}
+
+// IsIncompleteChunk reports whether the syntax error of a chunk comes from its end,
+// so more lines can complete it. An unfinished quoted string can not go on in the
+// next line, unlike a long string or comment.
+func IsIncompleteChunk(msg string) bool {
+	return strings.HasSuffix(msg, "near <eof>") && !strings.Contains(msg, "unfinished string")
+}
+
+// FormatConsoleResults formats the values on the stack above index first as the console shows them,
+// separated by tabs. Strings are quoted so they can be told from numbers, nil and booleans.
+func FormatConsoleResults(L *lua.State, first int) string {
+	var parts []string
+	for i := first + 1; i <= L.Top(); i++ {
+		if L.TypeOf(i) == lua.TypeString {
+			s, _ := L.ToString(i)
+			parts = append(parts, fmt.Sprintf("%q", s))
+			continue
+		}
+		parts = append(parts, formatValues(L, i, i))
+	}
+	return strings.Join(parts, "\t")
+}
+
+// formatValues formats the values from index first to last like print does
+func formatValues(L *lua.State, first, last int) string {
+	var parts []string
+	for i := first; i <= last; i++ {
+		s, _ := lua.ToStringMeta(L, i)
+		L.Pop(1) // ToStringMeta pushes the string
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, "\t")
+}
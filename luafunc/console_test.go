@@ -0,0 +1,98 @@
+package luafunc
+
+import (
+	"gotulua/statefunc"
+	"testing"
+	"time"
+
+	"github.com/Shopify/go-lua"
+)
+
+func TestConsoleWaitsForTheRunningScript(t *testing.T) {
+	L := lua.NewState()
+	lua.OpenLibraries(L)
+	statefunc.L = L
+	c := NewConsole()
+
+	started, release := make(chan struct{}), make(chan struct{})
+	statefunc.StartScript(L, "script", func(string) error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+	c.input.SetText("x = 1")
+	c.Submit(c.input.GetText())
+	L.Global("x")
+	if !L.IsNil(-1) {
+		t.Error("the console ran a statement while a script was running")
+	}
+	L.Pop(1)
+	if c.input.GetText() != "x = 1" {
+		t.Errorf("input is %q, want the refused statement kept", c.input.GetText())
+	}
+
+	close(release)
+	for deadline := time.Now().Add(time.Second); statefunc.IsScriptAsync(); {
+		if time.Now().After(deadline) {
+			t.Fatal("the script did not end")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	c.Submit(c.input.GetText())
+	L.Global("x")
+	if n, _ := L.ToInteger(-1); n != 1 {
+		t.Error("the console did not run the statement once the script ended")
+	}
+}
+
+func TestIsIncompleteChunk(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"if x then", true},
+		{"for i = 1, 3 do", true},
+		{"f(1,", true},
+		{"x = 'abc", false},
+		{"x = = 1", false},
+		{"end", false},
+	}
+	L := lua.NewState()
+	for _, tt := range tests {
+		if err := lua.LoadString(L, tt.code); err == nil {
+			t.Fatalf("%q compiled", tt.code)
+		}
+		msg, _ := L.ToString(-1)
+		L.Pop(1)
+		if got := IsIncompleteChunk(msg); got != tt.want {
+			t.Errorf("IsIncompleteChunk(%q) = %v, want %v", msg, got, tt.want)
+		}
+	}
+}
+
+func TestFormatConsoleResults(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"return", ""},
+		{"return 1", "1"},
+		{"return 'a', 2.5", "\"a\"\t2.5"},
+		{"return nil, true, false", "nil\ttrue\tfalse"},
+		{"return 'say \"hi\"'", `"say \"hi\""`},
+		{"return setmetatable({}, {__tostring = function() return 'obj' end})", "obj"},
+	}
+	L := lua.NewState()
+	lua.OpenLibraries(L)
+	for _, tt := range tests {
+		top := L.Top()
+		if err := lua.DoString(L, tt.code); err != nil {
+			t.Fatalf("%q: %v", tt.code, err)
+		}
+		if got := FormatConsoleResults(L, top); got != tt.want {
+			t.Errorf("FormatConsoleResults(%q) = %q, want %q", tt.code, got, tt.want)
+		}
+		L.SetTop(top)
+	}
+}
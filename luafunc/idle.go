@@ -0,0 +1,121 @@
+package luafunc
+
+import (
+	"errors"
+	"gotulua/errorhandlefunc"
+	"gotulua/i18nfunc"
+	"gotulua/statefunc"
+	"sync"
+
+	"github.com/Shopify/go-lua"
+)
+
+var (
+	idleMu sync.Mutex
+	// idleFunctions keeps the functions scheduled by RunWhenIdle while a script runs, and in
+	// batch mode. They use the interpreter of the script, so they run once it has ended.
+	idleFunctions []string
+	// scriptRunning is set while RunLuaScript or RunLuaString runs a script
+	scriptRunning bool
+)
+
+// runWhenIdle schedules a Lua function to run once, after the current event loop iteration
+func runWhenIdle(L *lua.State) int {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "RunWhenIdle",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	funcName, ok := L.ToString(1)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "function name",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	L.Global(funcName)
+	if !L.IsFunction(-1) {
+		L.Pop(1)
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_a_function", map[string]interface{}{
+			"Name": funcName,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	L.Pop(1)
+	idleMu.Lock()
+	defer idleMu.Unlock()
+	if statefunc.IsBatchMode() || scriptRunning {
+		idleFunctions = append(idleFunctions, funcName)
+		return 0
+	}
+	queueIdleFunctions(L, []string{funcName})
+	return 0
+}
+
+// startScriptRun marks that a script runs, RunWhenIdle keeps the functions it schedules
+func startScriptRun() {
+	idleMu.Lock()
+	defer idleMu.Unlock()
+	scriptRunning = true
+	idleFunctions = nil
+}
+
+// endScriptRun marks that the script has ended and passes the functions it scheduled to the event loop
+func endScriptRun(L *lua.State) {
+	idleMu.Lock()
+	funcs := idleFunctions
+	idleFunctions = nil
+	scriptRunning = false
+	idleMu.Unlock()
+	if len(funcs) > 0 {
+		queueIdleFunctions(L, funcs)
+	}
+}
+
+// queueIdleFunctions runs the functions in their order on the event loop, stopping at the first error
+func queueIdleFunctions(L *lua.State, funcs []string) {
+	// QueueUpdateDraw blocks until the event loop takes the function, so it is queued from a goroutine
+	go statefunc.App.QueueUpdateDraw(func() {
+		for _, funcName := range funcs {
+			if err := callIdleFunction(L, funcName); err != nil {
+				errorhandlefunc.ThrowError(err.Error(), errorhandlefunc.ErrorTypeScript, false)
+				return
+			}
+		}
+	})
+}
+
+// callIdleFunction calls a function scheduled by RunWhenIdle. It runs on the event loop, so
+// like lookup and field functions it is aborted after the function timeout.
+func callIdleFunction(L *lua.State, funcName string) error {
+	L.Global(funcName)
+	if !L.IsFunction(-1) {
+		L.Pop(1)
+		return errors.New(i18nfunc.T("error.not_a_function", map[string]interface{}{
+			"Name": funcName,
+		}))
+	}
+	return statefunc.ProtectedCallWithTimeout(L, funcName, 0, 0)
+}
+
+// runIdleFunctions runs the functions scheduled in batch mode in their order,
+// also those scheduled by them, and stops at the first error.
+func runIdleFunctions(L *lua.State) error {
+	for {
+		idleMu.Lock()
+		if len(idleFunctions) == 0 {
+			idleMu.Unlock()
+			return nil
+		}
+		funcName := idleFunctions[0]
+		idleFunctions = idleFunctions[1:]
+		idleMu.Unlock()
+		if err := callIdleFunction(L, funcName); err != nil {
+			idleMu.Lock()
+			idleFunctions = nil
+			idleMu.Unlock()
+			return err
+		}
+	}
+}
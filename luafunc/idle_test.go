@@ -0,0 +1,63 @@
+package luafunc
+
+import (
+	"gotulua/statefunc"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Shopify/go-lua"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func TestRunWhenIdleWaitsForTheScript(t *testing.T) {
+	L := lua.NewState()
+	lua.OpenLibraries(L)
+	statefunc.L = L
+	screen := tcell.NewSimulationScreen("")
+	app := tview.NewApplication().SetScreen(screen)
+	statefunc.SetState(tview.NewFlex(), tview.NewFlex(), tview.NewPages(), app)
+	go app.Run()
+	t.Cleanup(app.Stop)
+
+	called := make(chan string, 2)
+	L.Register("Called", func(L *lua.State) int {
+		s, _ := L.ToString(1)
+		called <- s
+		return 0
+	})
+	code := `
+		function first() Called("first " .. tostring(done)) end
+		function second() Called("second") end
+		RunWhenIdle("first")
+		RunWhenIdle("second")
+		done = true`
+	L.Register("RunWhenIdle", runWhenIdle)
+	if err := RunLuaString(code); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"first true", "second"} {
+		select {
+		case got := <-called:
+			if got != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%q was not called", want)
+		}
+	}
+}
+
+func TestRunWhenIdleIsLimitedByTheFunctionTimeout(t *testing.T) {
+	newBatchState(t)
+	prev := statefunc.GetFunctionTimeout()
+	t.Cleanup(func() { statefunc.SetFunctionTimeout(prev) })
+	err := runBatch(t, `
+		SetFunctionTimeout(0.1)
+		function endless() while true do end end
+		RunWhenIdle("endless")`)
+	if err == nil || !strings.Contains(err.Error(), "endless") {
+		t.Errorf("the endless idle function ended with %v, want the timeout error", err)
+	}
+}
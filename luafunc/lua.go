@@ -6,10 +6,16 @@ import (
 	"gotulua/gormfunc"
 	"gotulua/helpsysfunc"
 	"gotulua/i18nfunc"
+	"gotulua/inputfunc"
+	"gotulua/keymapfunc"
+	"gotulua/numfunc"
 	"gotulua/statefunc"
+	"gotulua/strfunc"
 	"gotulua/timefunc"
+	"gotulua/typesfunc"
 	"gotulua/uifunc"
-	"reflect"
+	"math"
+	"strconv"
 
 	"os"
 	"path/filepath"
@@ -17,9 +23,13 @@ import (
 	"time"
 
 	"github.com/Shopify/go-lua"
+	"github.com/gdamore/tcell/v2"
 	"gorm.io/gorm"
 )
 
+// confirmTableDrop is the default of the DBDropTable confirm argument
+var confirmTableDrop bool
+
 type FuncDescr struct {
 	Name        string
 	Description string
@@ -342,11 +352,37 @@ func CreateLuaInterpreter() (*lua.State, []uifunc.InputField) {
 	statefunc.L.Register("DBOpen", dbOpen)
 	statefunc.L.Register("DBClose", dbClose)
 	statefunc.L.Register("DBOpenTable", dbOpenTable)
+	statefunc.L.Register("DBOpenFiltered", dbOpenFiltered)
 	statefunc.L.Register("DBCreate", dbCreate)
 	statefunc.L.Register("DBCreateTable", dbCreateTable)
 	statefunc.L.Register("DBCreateTableTemp", dbCreateTableTemp)
 	statefunc.L.Register("DBAlterTable", dbAlterTable)
 	statefunc.L.Register("DBDropTable", dbDropTable)
+	statefunc.L.Register("DBRenameTable", dbRenameTable)
+	statefunc.L.Register("DBCreateView", dbCreateView)
+	statefunc.L.Register("DBDropView", dbDropView)
+	statefunc.L.Register("SetConfirmDrop", setConfirmDrop)
+	statefunc.L.Register("SetTiming", setTiming)
+	statefunc.L.Register("GetLastTiming", getLastTiming)
+	statefunc.L.Register("DBTransaction", dbTransaction)
+	statefunc.L.Register("DBBegin", dbBegin)
+	statefunc.L.Register("DBCommit", dbCommit)
+	statefunc.L.Register("DBRollback", dbRollback)
+	statefunc.L.Register("DBExecScript", dbExecScript)
+	statefunc.L.Register("DBQuery", dbQuery)
+	statefunc.L.Register("DBExec", dbExec)
+	statefunc.L.Register("DBImportCSV", dbImportCSV)
+	statefunc.L.Register("DBExportSchema", dbExportSchema)
+	statefunc.L.Register("DBImportSchema", dbImportSchema)
+	statefunc.L.Register("DBExportDump", dbExportDump)
+	statefunc.L.Register("RecordKeys", recordKeysLua)
+	statefunc.L.Register("LastInsertID", lastInsertID)
+	statefunc.L.Register("NextSequence", nextSequence)
+	statefunc.L.Register("DBResetSequence", dbResetSequence)
+	statefunc.L.Register("DBTableExists", dbTableExists)
+	statefunc.L.Register("DBColumnExists", dbColumnExists)
+	statefunc.L.Register("DBGetVersion", dbGetVersion)
+	statefunc.L.Register("DBSetVersion", dbSetVersion)
 	statefunc.L.Register("SetDateFormat", setDateFormat)
 	statefunc.L.Register("SetTimeFormat", setTimeFormat)
 	statefunc.L.Register("SetDateTimeFormat", setDateTimeFormat)
@@ -357,11 +393,46 @@ func CreateLuaInterpreter() (*lua.State, []uifunc.InputField) {
 	statefunc.L.Register("TimeDiff", timeDiff)
 	statefunc.L.Register("DateAdd", dateAdd)
 	statefunc.L.Register("TimeAdd", timeAdd)
+	statefunc.L.Register("DayOfWeek", dayOfWeek)
+	statefunc.L.Register("WeekNumber", weekNumber)
+	statefunc.L.Register("Today", today)
+	statefunc.L.Register("Yesterday", yesterday)
+	statefunc.L.Register("Tomorrow", tomorrow)
+	statefunc.L.Register("StartOfMonth", startOfMonth)
+	statefunc.L.Register("EndOfMonth", endOfMonth)
+	statefunc.L.Register("StartOfWeek", startOfWeek)
+	statefunc.L.Register("IsLeapYear", isLeapYear)
+	statefunc.L.Register("DaysInMonth", daysInMonth)
+	statefunc.L.Register("FormatNumber", formatNumber)
+	statefunc.L.Register("ParseNumber", parseNumber)
+	statefunc.L.Register("SetNumberFormat", setNumberFormat)
+	statefunc.L.Register("Random", random)
+	statefunc.L.Register("RandomFloat", randomFloat)
+	statefunc.L.Register("Split", split)
+	statefunc.L.Register("Join", join)
+	statefunc.L.Register("Format", format)
+	statefunc.L.Register("PadLeft", padLeft)
+	statefunc.L.Register("PadRight", padRight)
+	statefunc.L.Register("UUID", uuid)
 	statefunc.L.Register("AddBrowse", addBrowse)
 	statefunc.L.Register("AddLookup", addLookup)
+	statefunc.L.Register("SetFunctionTimeout", setFunctionTimeout)
+	statefunc.L.Register("SetInputTheme", setInputTheme)
 	statefunc.L.Register("AddForm", uifunc.AddForm)
 	statefunc.L.Register("Confirm", confirm)
 	statefunc.L.Register("Message", message)
+	statefunc.L.Register("PickList", pickList)
+	statefunc.L.Register("SetAppTitle", setAppTitle)
+	statefunc.L.Register("ShowInfoBar", showInfoBar)
+	statefunc.L.Register("ShowButtonBar", showButtonBar)
+	statefunc.L.Register("GetAppTitle", getAppTitle)
+	statefunc.L.Register("SetStatus", setStatus)
+	statefunc.L.Register("GetStatus", getStatus)
+	statefunc.L.Register("RunWhenIdle", runWhenIdle)
+	statefunc.L.Register("SetRole", setRole)
+	statefunc.L.Register("GetRole", getRole)
+	statefunc.L.Register("BindKey", bindKey)
+	statefunc.L.Register("SetErrorHandler", setErrorHandler)
 	statefunc.L.Register("getLastError", getLastError)
 	statefunc.L.Register("clearErrors", clearErrors)
 
@@ -550,6 +621,96 @@ func registerTableType(L *lua.State) {
 			// L.PushBoolean(true)
 			// return 1
 		},
+		"SetFieldDefault": func(L *lua.State) int {
+			return setFieldDefault(L)
+		},
+		"SetCollation": func(L *lua.State) int {
+			return setCollation(L)
+		},
+		"SetFilterMode": func(L *lua.State) int {
+			return setFilterMode(L)
+		},
+		"SaveFilter": func(L *lua.State) int {
+			return saveFilter(L)
+		},
+		"LoadFilter": func(L *lua.State) int {
+			return loadFilter(L)
+		},
+		"ListFilters": func(L *lua.State) int {
+			return listFilters(L)
+		},
+		"DistinctValues": func(L *lua.State) int {
+			return distinctValues(L)
+		},
+		"ExportCSV": func(L *lua.State) int {
+			return exportCSV(L)
+		},
+		"Sum": func(L *lua.State) int {
+			return numericAggregate(L, "Sum")
+		},
+		"Avg": func(L *lua.State) int {
+			return numericAggregate(L, "Avg")
+		},
+		"Min": func(L *lua.State) int {
+			return valueAggregate(L, "Min")
+		},
+		"Max": func(L *lua.State) int {
+			return valueAggregate(L, "Max")
+		},
+		"Count": func(L *lua.State) int {
+			return count(L)
+		},
+		"RowCountLoaded": func(L *lua.State) int {
+			return rowCountLoaded(L)
+		},
+		"HasMore": func(L *lua.State) int {
+			return hasMore(L)
+		},
+		"ToArray": func(L *lua.State) int {
+			return toArray(L)
+		},
+		"Upsert": func(L *lua.State) int {
+			return upsert(L)
+		},
+		"DeleteWhere": func(L *lua.State) int {
+			return deleteWhere(L)
+		},
+		"GetRawField": func(L *lua.State) int {
+			return getRawField(L)
+		},
+		"GetRecord": func(L *lua.State) int {
+			return getRecord(L)
+		},
+		"GetColumns": func(L *lua.State) int {
+			return getColumns(L)
+		},
+		"GetFieldType": func(L *lua.State) int {
+			return getFieldType(L)
+		},
+		"SetSoftDelete": func(L *lua.State) int {
+			return setSoftDelete(L)
+		},
+		"IncludeDeleted": func(L *lua.State) int {
+			return includeDeleted(L)
+		},
+		"SetOrderField": func(L *lua.State) int {
+			return setOrderField(L)
+		},
+		"MoveUp": func(L *lua.State) int {
+			return moveRecord(L, (*gormfunc.Table).MoveUp)
+		},
+		"MoveDown": func(L *lua.State) int {
+			return moveRecord(L, (*gormfunc.Table).MoveDown)
+		},
+		"MoveTo": func(L *lua.State) int {
+			return moveTo(L)
+		},
+		"SetLimit": func(L *lua.State) int {
+			return setLimitOrOffset(L, "SetLimit", (*gormfunc.Table).SetLimit)
+		},
+		"SetOffset": func(L *lua.State) int {
+			return setLimitOrOffset(L, "SetOffset", (*gormfunc.Table).SetOffset)
+		},
 		"OrderBy": func(L *lua.State) int {
 			return setOrderBy(L)
 			// wrapper := checkTable(L)
@@ -570,6 +731,33 @@ func registerTableType(L *lua.State) {
 			// L.PushBoolean(true)
 			// return 1
 		},
+		"Walk": func(L *lua.State) int {
+			return walk(L)
+		},
+		"Transaction": func(L *lua.State) int {
+			return tableTransaction(L)
+		},
+		"GetString": func(L *lua.State) int {
+			return getTypedField(L, "GetString", typesfunc.TypeText)
+		},
+		"GetInt": func(L *lua.State) int {
+			return getTypedField(L, "GetInt", typesfunc.TypeInteger)
+		},
+		"GetFloat": func(L *lua.State) int {
+			return getTypedField(L, "GetFloat", typesfunc.TypeReal)
+		},
+		"GetBool": func(L *lua.State) int {
+			return getTypedField(L, "GetBool", typesfunc.TypeBoolean)
+		},
+		"GetDate": func(L *lua.State) int {
+			return getTypedField(L, "GetDate", typesfunc.TypeDate)
+		},
+		"SetReadTransform": func(L *lua.State) int {
+			return setTransform(L, "SetReadTransform")
+		},
+		"SetWriteTransform": func(L *lua.State) int {
+			return setTransform(L, "SetWriteTransform")
+		},
 		"SetOnAfterDelete": func(L *lua.State) int {
 			wrapper := checkTable(L)
 			if wrapper == nil {
@@ -608,6 +796,25 @@ func registerTableType(L *lua.State) {
 			L.PushBoolean(true)
 			return 1
 		},
+		"SetOnValidate": func(L *lua.State) int {
+			wrapper := checkTable(L)
+			if wrapper == nil {
+				return 0
+			}
+			if L.Top() < 2 {
+				L.PushString("SetOnValidate requires a function name parameter")
+				L.Error()
+				return 0
+			}
+			funcName, ok := L.ToString(2)
+			if !ok {
+				L.PushBoolean(false)
+				return 1
+			}
+			wrapper.Table.SetOnValidate(funcName)
+			L.PushBoolean(true)
+			return 1
+		},
 		"SetOnAfterInsert": func(L *lua.State) int {
 			wrapper := checkTable(L)
 			if wrapper == nil {
@@ -682,29 +889,7 @@ func registerTableType(L *lua.State) {
 			}), errorhandlefunc.ErrorTypeScript, true)
 			return 0
 		}
-		switch v := val.(type) {
-		case string:
-			L.PushString(v)
-		case int:
-			L.PushInteger(v)
-		case int64:
-			L.PushInteger(int(v))
-		case float64:
-			L.PushNumber(v)
-		case bool:
-			L.PushBoolean(v)
-		case nil:
-			L.PushNil()
-		default:
-			t := fmt.Sprintf("%v", reflect.TypeOf(val))
-			if t == "*interface {}" {
-				var vp *interface{} = val.(*interface{})
-				vv := *vp
-				L.PushString(vv.(string))
-			} else {
-				L.PushString(fmt.Sprintf("%v", v))
-			}
-		}
+		pushFieldValue(L, val)
 		return 1
 	})
 	L.RawSet(-3)
@@ -817,6 +1002,30 @@ func registerBrowseType(L *lua.State) {
 	L.SetField(-2, "SetFieldLookup") // __index.BrowseTableAddField = BrowseTableAddField
 	L.PushGoFunction(uifunc.AddButton)
 	L.SetField(-2, "AddButton") // __index.BrowseTableAddField = BrowseTableAddField
+	L.PushGoFunction(uifunc.PressButton)
+	L.SetField(-2, "PressButton")
+	L.PushGoFunction(uifunc.SetFieldRoles)
+	L.SetField(-2, "SetFieldRoles")
+	L.PushGoFunction(uifunc.SetRowTag)
+	L.SetField(-2, "SetRowTag")
+	L.PushGoFunction(uifunc.GetRowTag)
+	L.SetField(-2, "GetRowTag")
+	L.PushGoFunction(uifunc.SetShowPrimaryKey)
+	L.SetField(-2, "SetShowPrimaryKey")
+	L.PushGoFunction(uifunc.SetShowFilterRow)
+	L.SetField(-2, "SetShowFilterRow")
+	L.PushGoFunction(uifunc.SetFixedColumns)
+	L.SetField(-2, "SetFixedColumns")
+	L.PushGoFunction(uifunc.SetColumnGroups)
+	L.SetField(-2, "SetColumnGroups")
+	L.PushGoFunction(uifunc.SetGroupTotals)
+	L.SetField(-2, "SetGroupTotals")
+	L.PushGoFunction(uifunc.SetColumnVisible)
+	L.SetField(-2, "SetColumnVisible")
+	L.PushGoFunction(uifunc.SetOnOpen)
+	L.SetField(-2, "SetOnOpen")
+	L.PushGoFunction(uifunc.SetOnClose)
+	L.SetField(-2, "SetOnClose")
 	L.PushGoFunction(browseTable)
 	L.SetField(-2, "Show") // __index.BrowseTable = BrowseTable
 	// Set the metatable for the Browse type
@@ -1094,10 +1303,10 @@ func dateDiff(L *lua.State) int {
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	if mode != "d" && mode != "D" && mode != "m" && mode != "M" && mode != "y" && mode != "Y" {
+	if len(mode) != 1 || !strings.Contains("dDwWmMqQyY", mode) {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_valid", map[string]interface{}{
 			"Argument": mode,
-			"Valid":    "d, D, m, M, y, Y",
+			"Valid":    "d, D, w, W, m, M, q, Q, y, Y",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
@@ -1154,335 +1363,2469 @@ func timeDiff(L *lua.State) int {
 
 // Register Date, Time, DateTime formats in Lua <<<<<<<<<<<<<<<<
 
-// Register the UI functions with the Lua interpreter >>>>>>>>>>>>>>>>>>>>>>
-func addBrowse(L *lua.State) int {
-	return uifunc.BrowseTableNew(L, false)
-}
-
-func addLookup(L *lua.State) int {
-	return uifunc.BrowseTableNew(L, true)
+// Register the number functions >>>>>>>>>>>>>>>>>>>>>>
+// optionalString returns the string argument at index idx or def if it is absent
+func optionalString(L *lua.State, idx int, def string) string {
+	if L.Top() < idx || L.IsNil(idx) {
+		return def
+	}
+	s, ok := L.ToString(idx)
+	if !ok {
+		return def
+	}
+	return s
 }
 
-func dateAdd(L *lua.State) int {
-	if L.Top() < 2 {
+// formatNumber formats a number with thousands grouping: FormatNumber(value, decimals, thousandsSep, decimalSep)
+func formatNumber(L *lua.State) int {
+	if L.Top() < 1 {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
-			"Name": "DateAdd",
+			"Name": "FormatNumber",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	date, ok := L.ToString(1)
+	value, ok := L.ToNumber(1)
 	if !ok {
-		x := L.ToValue(1)
-		if x != nil {
-			errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
-				"Name": "date",
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_number", map[string]interface{}{
+			"Name": "value",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	decimals := 0
+	if L.Top() >= 2 && !L.IsNil(2) {
+		decimals, ok = L.ToInteger(2)
+		if !ok {
+			errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_integer", map[string]interface{}{
+				"Name": "decimals",
 			}), errorhandlefunc.ErrorTypeScript, true)
 			return 0
 		}
 	}
-	year, ok := L.ToInteger(2)
-	if !ok {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_int", map[string]interface{}{
-			"Name": "year",
+	thousandsSep := optionalString(L, 3, "")
+	decimalSep := optionalString(L, 4, ".")
+	L.PushString(numfunc.FormatNumber(value, decimals, thousandsSep, decimalSep))
+	return 1
+}
+
+// parseNumber converts a formatted number back: ParseNumber(s, thousandsSep, decimalSep)
+func parseNumber(L *lua.State) int {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "ParseNumber",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	month, ok := L.ToInteger(3)
+	s, ok := L.ToString(1)
 	if !ok {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_int", map[string]interface{}{
-			"Name": "month",
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "number",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	day, ok := L.ToInteger(4)
-	if !ok {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_int", map[string]interface{}{
-			"Name": "day",
+	thousandsSep := optionalString(L, 2, "")
+	decimalSep := optionalString(L, 3, ".")
+	v, err := numfunc.ParseNumber(s, thousandsSep, decimalSep)
+	if err != nil {
+		statefunc.SetLastErrorText(err.Error())
+		L.PushNil()
+		return 1
+	}
+	L.PushNumber(v)
+	return 1
+}
+
+// setNumberFormat sets the format of numbers shown in browses: SetNumberFormat(decimals, thousandsSep, decimalSep)
+func setNumberFormat(L *lua.State) int {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "SetNumberFormat",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	mode, ok := L.ToString(3)
+	decimals, ok := L.ToInteger(1)
 	if !ok {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
-			"Name": "mode",
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_integer", map[string]interface{}{
+			"Name": "decimals",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	if mode != "d" && mode != "D" && mode != "m" && mode != "M" && mode != "y" && mode != "Y" {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_valid", map[string]interface{}{
-			"Argument": mode,
-			"Valid":    "d, D, m, M, y, Y",
-		}), errorhandlefunc.ErrorTypeScript, true)
+	err := numfunc.SetNumberFormat(decimals, optionalString(L, 2, ""), optionalString(L, 3, "."))
+	if err != nil {
+		errorhandlefunc.ThrowError(err.Error(), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	r := timefunc.DateAdd(date, year, month, day)
-	L.PushString(r)
 	return 1
 }
 
-func timeAdd(L *lua.State) int {
+// random returns a random integer from min to max, both included: Random(min, max)
+func random(L *lua.State) int {
 	if L.Top() < 2 {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
-			"Name": "TimeAdd",
+			"Name": "Random",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	time, ok := L.ToString(1)
+	min, ok := L.ToInteger(1)
 	if !ok {
-		x := L.ToValue(1)
-		if x != nil {
-			errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
-				"Name": "time",
-			}), errorhandlefunc.ErrorTypeScript, true)
-			return 0
-		}
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_integer", map[string]interface{}{
+			"Name": "min",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
 	}
-	hour, ok := L.ToInteger(2)
+	max, ok := L.ToInteger(2)
 	if !ok {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_int", map[string]interface{}{
-			"Name": "hour",
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_integer", map[string]interface{}{
+			"Name": "max",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	minute, ok := L.ToInteger(3)
-	if !ok {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_int", map[string]interface{}{
-			"Name": "minute",
+	if min > max {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.random_range", map[string]interface{}{
+			"Min": min,
+			"Max": max,
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	second, ok := L.ToInteger(4)
-	if !ok {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_int", map[string]interface{}{
-			"Name": "second",
+	L.PushInteger(numfunc.Random(min, max))
+	return 1
+}
+
+// randomFloat returns a random number from 0 up to 1: RandomFloat()
+func randomFloat(L *lua.State) int {
+	L.PushNumber(numfunc.RandomFloat())
+	return 1
+}
+
+// Register the number functions <<<<<<<<<<<<<<<<<<<<<<
+
+// Register the string functions >>>>>>>>>>>>>>>>>>>>>>
+// split splits a string into a Lua array: Split(s, sep). An empty sep splits into characters.
+func split(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "Split",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	mode, ok := L.ToString(3)
+	s, ok := L.ToString(1)
 	if !ok {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
-			"Name": "mode",
+			"Name": "s",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	if mode != "d" && mode != "D" && mode != "m" && mode != "M" && mode != "y" && mode != "Y" {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_valid", map[string]interface{}{
-			"Argument": mode,
-			"Valid":    "d, D, m, M, y, Y",
+	sep, ok := L.ToString(2)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "sep",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	r := timefunc.TimeAdd(time, hour, minute, second)
-	L.PushString(r)
-	return 1
-}
-
-func browseTable(L *lua.State) int {
-	if L.Top() < 1 {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.extra_args", map[string]interface{}{
-			"Name": "BrowseTable",
-		}), errorhandlefunc.ErrorTypeScript, true)
-		return 0
+	parts := strings.Split(s, sep)
+	if s == "" {
+		parts = nil
 	}
-	browse, ok := L.ToUserData(1).(*uifunc.TBrowse) // Get the browse from Lua
-	if !ok {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_browse", nil), errorhandlefunc.ErrorTypeScript, true)
-		return 0
+	L.CreateTable(len(parts), 0)
+	for i, part := range parts {
+		L.PushString(part)
+		L.RawSetInt(-2, i+1)
 	}
-	browse.Show(L) // Call the BrowseTable method on the browse
-	return 1       // Return the number of results
+	return 1
 }
 
-func confirm(L *lua.State) int {
+// join joins the items of a Lua array into a string: Join(items, sep)
+func join(L *lua.State) int {
 	if L.Top() < 1 {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
-			"Name": "Confirm",
+			"Name": "Join",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	text, ok := L.ToString(1)
-	if !ok {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
-			"Name": "text",
+	if !L.IsTable(1) {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_lua_table", map[string]interface{}{
+			"Name": "items",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	uifunc.Confirm(text, func(ok bool) {
-		L.PushBoolean(ok)
-	})
+	sep := optionalString(L, 2, "")
+	var items []string
+	for i := 1; ; i++ {
+		L.RawGetInt(1, i)
+		if L.IsNil(-1) {
+			L.Pop(1)
+			break
+		}
+		items = append(items, strfunc.ToString(L.ToValue(-1)))
+		L.Pop(1)
+	}
+	L.PushString(strings.Join(items, sep))
 	return 1
 }
 
-func message(L *lua.State) int {
+// format formats the arguments like Go's fmt.Sprintf: Format(fmt, ...)
+func format(L *lua.State) int {
 	if L.Top() < 1 {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
-			"Name": "Message",
+			"Name": "Format",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	text, ok := L.ToString(1)
+	f, ok := L.ToString(1)
 	if !ok {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
-			"Name": "text",
+			"Name": "fmt",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	uifunc.Message(text)
+	var args []interface{}
+	for i := 2; i <= L.Top(); i++ {
+		args = append(args, L.ToValue(i))
+	}
+	L.PushString(strfunc.Format(f, args))
 	return 1
 }
 
-// Get the last error message
-func getLastError(L *lua.State) int {
-	L.PushString(statefunc.GetLastErrorText())
-	return 1
+// padLeft pads a string on the left: PadLeft(s, n, ch)
+func padLeft(L *lua.State) int {
+	return pad(L, "PadLeft", strfunc.PadLeft)
 }
 
-// Clear the error message
-func clearErrors(L *lua.State) int {
-	statefunc.ClearErrors()
-	return 1
+// padRight pads a string on the right: PadRight(s, n, ch)
+func padRight(L *lua.State) int {
+	return pad(L, "PadRight", strfunc.PadRight)
 }
 
-// Register the UI functions with the Lua interpreter <<<<<<<<<<<<<<<<<<<<<<<<<<<<<<<<<<
-
-// Register the database functions >>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>
-func dbOpen(L *lua.State) int {
-	if L.Top() < 1 {
+func pad(L *lua.State, name string, padFunc func(s string, n int, pad string) string) int {
+	if L.Top() < 2 {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
-			"Name": "DBOpen",
+			"Name": name,
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	path, ok := L.ToString(1) // Get the database path from Lua
+	s, ok := L.ToString(1)
 	if !ok {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
-			"Name": "database path",
+			"Name": "s",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	db := gormfunc.OpenDB(path) // Open the database
-	if db == nil {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.db_open", map[string]interface{}{
-			"Name": path,
+	n, ok := L.ToInteger(2)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_integer", map[string]interface{}{
+			"Name": "n",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	L.PushUserData(db) // Push the database as userdata
-	return 1           // Return the number of results
+	L.PushString(padFunc(s, n, optionalString(L, 3, " ")))
+	return 1
 }
 
-func dbClose(L *lua.State) int {
+// uuid returns a random UUID string: UUID()
+func uuid(L *lua.State) int {
+	id, err := strfunc.UUID()
+	if err != nil {
+		errorhandlefunc.ThrowError(err.Error(), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	L.PushString(id)
+	return 1
+}
+
+// Register the string functions <<<<<<<<<<<<<<<<<<<<<<
+
+// Register the UI functions with the Lua interpreter >>>>>>>>>>>>>>>>>>>>>>
+// setFunctionTimeout sets the time budget of lookup and field functions in seconds
+func setFunctionTimeout(L *lua.State) int {
 	if L.Top() < 1 {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
-			"Name": "DBClose",
+			"Name": "SetFunctionTimeout",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	db, ok := L.ToUserData(1).(*gorm.DB) // Get the database from Lua
+	seconds, ok := L.ToNumber(1)
 	if !ok {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_db", map[string]interface{}{
-			"Name": "database",
-		}), errorhandlefunc.ErrorTypeScript, true)
-		return 0
-	}
-	err := gormfunc.CloseDB(db) // Close the database
-	if err != nil {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.db_close", map[string]interface{}{
-			"Error": err.Error(),
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_number", map[string]interface{}{
+			"Name": "seconds",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	return 0 // Return success
+	statefunc.SetFunctionTimeout(time.Duration(seconds * float64(time.Second)))
+	return 1
 }
 
-func dbOpenTable(L *lua.State) int {
-	if L.Top() < 2 {
+// setInputTheme sets the colors of edit inputs: SetInputTheme(placeholder, [text], [background]).
+// Colors are tcell names or #RRGGBB values, an empty or missing color keeps the current one.
+func setInputTheme(L *lua.State) int {
+	if L.Top() < 1 {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
-			"Name": "DBOpenTable",
+			"Name": "SetInputTheme",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
+	placeholder, text, background := inputfunc.GetInputTheme()
+	colors := []*tcell.Color{&placeholder, &text, &background}
+	for i, c := range colors {
+		name := optionalString(L, i+1, "")
+		if name == "" {
+			continue
+		}
+		color := tcell.GetColor(name)
+		if color == tcell.ColorDefault && !strings.EqualFold(name, "default") {
+			errorhandlefunc.ThrowError(i18nfunc.T("error.invalid_color", map[string]interface{}{
+				"Color": name,
+			}), errorhandlefunc.ErrorTypeScript, true)
+			return 0
+		}
+		*c = color
+	}
+	inputfunc.SetInputTheme(placeholder, text, background)
+	return 1
+}
+
+func addBrowse(L *lua.State) int {
+	return uifunc.BrowseTableNew(L, false)
+}
+
+func addLookup(L *lua.State) int {
+	return uifunc.BrowseTableNew(L, true)
+}
+
+func dateAdd(L *lua.State) int {
+	if L.Top() < 4 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "DateAdd",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	date, ok := L.ToString(1)
+	if !ok {
+		x := L.ToValue(1)
+		if x != nil {
+			errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+				"Name": "date",
+			}), errorhandlefunc.ErrorTypeScript, true)
+			return 0
+		}
+	}
+	year, ok := L.ToInteger(2)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_integer", map[string]interface{}{
+			"Name": "year",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	month, ok := L.ToInteger(3)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_integer", map[string]interface{}{
+			"Name": "month",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	day, ok := L.ToInteger(4)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_integer", map[string]interface{}{
+			"Name": "day",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	r := timefunc.DateAdd(date, year, month, day)
+	L.PushString(r)
+	return 1
+}
+
+func timeAdd(L *lua.State) int {
+	if L.Top() < 4 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "TimeAdd",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	time, ok := L.ToString(1)
+	if !ok {
+		x := L.ToValue(1)
+		if x != nil {
+			errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+				"Name": "time",
+			}), errorhandlefunc.ErrorTypeScript, true)
+			return 0
+		}
+	}
+	hour, ok := L.ToInteger(2)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_integer", map[string]interface{}{
+			"Name": "hour",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	minute, ok := L.ToInteger(3)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_integer", map[string]interface{}{
+			"Name": "minute",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	second, ok := L.ToInteger(4)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_integer", map[string]interface{}{
+			"Name": "second",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	r := timefunc.TimeAdd(time, hour, minute, second)
+	L.PushString(r)
+	return 1
+}
+
+// dayOfWeek returns the day of the week of a date, 1 for Monday to 7 for Sunday
+func dayOfWeek(L *lua.State) int {
+	date, ok := dateArg(L, "DayOfWeek")
+	if !ok {
+		return 0
+	}
+	L.PushInteger(timefunc.DayOfWeek(date))
+	return 1
+}
+
+// weekNumber returns the ISO 8601 week of a date
+func weekNumber(L *lua.State) int {
+	date, ok := dateArg(L, "WeekNumber")
+	if !ok {
+		return 0
+	}
+	L.PushInteger(timefunc.WeekNumber(date))
+	return 1
+}
+
+// today returns the current date in the format specified by SetDateFormat
+func today(L *lua.State) int {
+	L.PushString(timefunc.Today())
+	return 1
+}
+
+// yesterday returns the date before today in the format specified by SetDateFormat
+func yesterday(L *lua.State) int {
+	L.PushString(timefunc.Yesterday())
+	return 1
+}
+
+// tomorrow returns the date after today in the format specified by SetDateFormat
+func tomorrow(L *lua.State) int {
+	L.PushString(timefunc.Tomorrow())
+	return 1
+}
+
+// startOfMonth returns the first day of the month of a date, of the current month without one
+func startOfMonth(L *lua.State) int {
+	return pushDateBoundary(L, timefunc.StartOfMonth)
+}
+
+// endOfMonth returns the last day of the month of a date, of the current month without one
+func endOfMonth(L *lua.State) int {
+	return pushDateBoundary(L, timefunc.EndOfMonth)
+}
+
+// startOfWeek returns the Monday of the week of a date, of the current week without one
+func startOfWeek(L *lua.State) int {
+	return pushDateBoundary(L, timefunc.StartOfWeek)
+}
+
+// isLeapYear returns whether February of the year has 29 days
+func isLeapYear(L *lua.State) int {
+	year, ok := yearArg(L, "IsLeapYear")
+	if !ok {
+		return 0
+	}
+	L.PushBoolean(timefunc.IsLeapYear(year))
+	return 1
+}
+
+// daysInMonth returns the number of days of a month of a year
+func daysInMonth(L *lua.State) int {
+	year, ok := yearArg(L, "DaysInMonth")
+	if !ok {
+		return 0
+	}
+	month, ok := L.ToInteger(2)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_integer", map[string]interface{}{
+			"Name": "month",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	if month < 1 || month > 12 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_valid", map[string]interface{}{
+			"Argument": month,
+			"Valid":    "1-12",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	L.PushInteger(timefunc.DaysInMonth(year, month))
+	return 1
+}
+
+// yearArg reads the year passed as the first argument of the function
+func yearArg(L *lua.State, name string) (int, bool) {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": name,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0, false
+	}
+	year, ok := L.ToInteger(1)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_integer", map[string]interface{}{
+			"Name": "year",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0, false
+	}
+	return year, true
+}
+
+// pushDateBoundary pushes the boundary computed for the optional date argument,
+// today when it is left out
+func pushDateBoundary(L *lua.State, boundary func(string) string) int {
+	date := ""
+	if L.Top() >= 1 && !L.IsNil(1) {
+		var ok bool
+		if date, ok = L.ToString(1); !ok {
+			errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+				"Name": "date",
+			}), errorhandlefunc.ErrorTypeScript, true)
+			return 0
+		}
+	}
+	L.PushString(boundary(date))
+	return 1
+}
+
+// dateArg reads the date string passed as the only argument of the function
+func dateArg(L *lua.State, name string) (string, bool) {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": name,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return "", false
+	}
+	date, ok := L.ToString(1)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "date",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return "", false
+	}
+	return date, true
+}
+
+func browseTable(L *lua.State) int {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.extra_args", map[string]interface{}{
+			"Name": "BrowseTable",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	browse, ok := L.ToUserData(1).(*uifunc.TBrowse) // Get the browse from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_browse", nil), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	browse.Show(L) // Call the BrowseTable method on the browse
+	return 1       // Return the number of results
+}
+
+func confirm(L *lua.State) int {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "Confirm",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	text, ok := L.ToString(1)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "text",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	text = i18nfunc.Text(text, templateData(L, 2))
+	if statefunc.IsBatchMode() {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.batch_dialog_not_available", map[string]interface{}{
+			"Name": "Confirm",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	uifunc.Confirm(text, func(ok bool) {
+		L.PushBoolean(ok)
+	})
+	return 1
+}
+
+// pickList lets the user choose one of the strings of a Lua array.
+// Returns the index and the value of the chosen item, or nil if the list was cancelled.
+func pickList(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "PickList",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	title, ok := L.ToString(1)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "title",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	if !L.IsTable(2) {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_lua_table", map[string]interface{}{
+			"Name": "items",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	var items []string
+	for i := 1; ; i++ {
+		L.RawGetInt(2, i)
+		if L.IsNil(-1) {
+			L.Pop(1)
+			break
+		}
+		item, _ := L.ToString(-1)
+		items = append(items, item)
+		L.Pop(1)
+	}
+	if statefunc.IsBatchMode() {
+		// Nobody can choose, as if the list was cancelled
+		L.PushNil()
+		return 1
+	}
+	index := -1
+	if !statefunc.WaitForAnswer(func(done func()) {
+		uifunc.PickList(title, items, func(i int) {
+			index = i
+			done()
+		})
+	}) {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.dialog_needs_async_run", map[string]interface{}{
+			"Name": "PickList",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	if index < 0 {
+		L.PushNil()
+		return 1
+	}
+	L.PushInteger(index + 1)
+	L.PushString(items[index])
+	return 2
+}
+
+func message(L *lua.State) int {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "Message",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	text, ok := L.ToString(1)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "text",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	text = i18nfunc.Text(text, templateData(L, 2))
+	if statefunc.IsBatchMode() {
+		fmt.Println(text)
+		return 1
+	}
+	uifunc.Message(text)
+	return 1
+}
+
+// templateData returns the string keys of the Lua table at the index with their values,
+// for the placeholders of a message. Returns nil if there is no table at the index.
+func templateData(L *lua.State, index int) map[string]interface{} {
+	if L.Top() < index || !L.IsTable(index) {
+		return nil
+	}
+	data := make(map[string]interface{})
+	L.PushNil()
+	for L.Next(index) {
+		if key, ok := L.ToString(-2); ok && L.TypeOf(-2) == lua.TypeString {
+			data[key] = L.ToValue(-1)
+		}
+		L.Pop(1)
+	}
+	return data
+}
+
+// setAppTitle sets the title shown above the widgets
+func setAppTitle(L *lua.State) int {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "SetAppTitle",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	text, ok := L.ToString(1)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "text",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	uifunc.SetAppTitle(text)
+	return 1
+}
+
+func getAppTitle(L *lua.State) int {
+	L.PushString(uifunc.GetAppTitle())
+	return 1
+}
+
+// showInfoBar shows or hides the info bar with the widget titles
+func showInfoBar(L *lua.State) int {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "ShowInfoBar",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	uifunc.SetInfoBarVisible(L.ToBoolean(1))
+	return 0
+}
+
+// showButtonBar shows or hides the button bar of browses with buttons
+func showButtonBar(L *lua.State) int {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "ShowButtonBar",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	uifunc.SetButtonBarVisible(L.ToBoolean(1))
+	return 0
+}
+
+// setRole sets the role that decides which browse fields are shown and editable
+func setRole(L *lua.State) int {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "SetRole",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	name, ok := L.ToString(1)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "name",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	uifunc.SetRole(name)
+	return 1
+}
+
+func getRole(L *lua.State) int {
+	L.PushString(uifunc.GetRole())
+	return 1
+}
+
+// setErrorHandler sets the Lua function called with the errors before they are shown.
+// nil or an empty name removes it.
+func setErrorHandler(L *lua.State) int {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "SetErrorHandler",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	if L.IsNil(1) {
+		errorhandlefunc.SetErrorHandler("")
+		return 0
+	}
+	funcName, ok := L.ToString(1)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "function name",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	errorhandlefunc.SetErrorHandler(funcName)
+	return 0
+}
+
+// bindKey sets the keys of an action like "editor.save", keys are comma separated like "Ctrl+S,F2"
+func bindKey(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "BindKey",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	action, ok := L.ToString(1)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "action",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	keys, ok := L.ToString(2)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "keys",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	if err := keymapfunc.Bind(action, keys); err != nil {
+		errorhandlefunc.ThrowError(err.Error(), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	return 1
+}
+
+// setStatus sets the status line shown below the widgets
+func setStatus(L *lua.State) int {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "SetStatus",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	text, ok := L.ToString(1)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "text",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	uifunc.SetStatus(text)
+	return 1
+}
+
+func getStatus(L *lua.State) int {
+	L.PushString(uifunc.GetStatus())
+	return 1
+}
+
+// Get the last error message
+func getLastError(L *lua.State) int {
+	L.PushString(statefunc.GetLastErrorText())
+	return 1
+}
+
+// Clear the error message
+func clearErrors(L *lua.State) int {
+	statefunc.ClearErrors()
+	return 1
+}
+
+// Register the UI functions with the Lua interpreter <<<<<<<<<<<<<<<<<<<<<<<<<<<<<<<<<<
+
+// Register the database functions >>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>
+func dbOpen(L *lua.State) int {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "DBOpen",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	path, ok := L.ToString(1) // Get the database path from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "database path",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	db := gormfunc.OpenDB(path) // Open the database
+	if db == nil {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.db_open", map[string]interface{}{
+			"Name": path,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	L.PushUserData(db) // Push the database as userdata
+	return 1           // Return the number of results
+}
+
+func dbClose(L *lua.State) int {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "DBClose",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	db, ok := L.ToUserData(1).(*gorm.DB) // Get the database from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_db", map[string]interface{}{
+			"Name": "database",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	err := gormfunc.CloseDB(db) // Close the database
+	if err != nil {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.db_close", map[string]interface{}{
+			"Error": err.Error(),
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	return 0 // Return success
+}
+
+func dbOpenTable(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "DBOpenTable",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+
+	// Get the database from Lua
+	ud := L.ToUserData(1)
+	if ud == nil {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_db", map[string]interface{}{
+			"Name": "database",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	db, ok := ud.(*gorm.DB)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_db", map[string]interface{}{
+			"Name": "database",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+
+	// Get the table name from Lua
+	tableName, ok := L.ToString(2)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "table name",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+
+	// Open the table
+	table := gormfunc.OpenTable(db, tableName)
+	if table == nil {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.table_open_failed", map[string]interface{}{
+			"Name": tableName,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+
+	return pushTable(L, table)
+}
+
+// pushTable pushes the table as userdata with the table methods
+func pushTable(L *lua.State, table *gormfunc.Table) int {
+	// Create a new wrapper
+	wrapper := &gormfunc.TableWrapper{Table: table}
+
+	// Push the wrapper as userdata
+	L.PushUserData(wrapper)
+
+	// Get and set the metatable from registry
+	L.PushString("TableMT")
+	L.RawGet(lua.RegistryIndex)
+	if L.IsNil(-1) {
+		L.Pop(1)
+		L.PushString("TableMT metatable not found")
+		L.Error()
+		return 0
+	}
+	L.SetMetaTable(-2)
+	return 1
+}
+
+// dbOpenFiltered opens a table, sets its filters and finds the matching rows.
+// Lua: DBOpenFiltered(db, name, field, filter) or DBOpenFiltered(db, name, {field = filter, ...}).
+// The filters have the format of SetFilter. The table is returned on the first matching row,
+// with no rows if nothing matches.
+func dbOpenFiltered(L *lua.State) int {
+	if L.Top() < 3 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "DBOpenFiltered",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	db, ok := L.ToUserData(1).(*gorm.DB) // Get the database from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_db", map[string]interface{}{
+			"Name": "database",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	tableName, ok := L.ToString(2) // Get the table name from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "table name",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	filters := map[string]string{}
+	if L.IsTable(3) {
+		L.PushNil()
+		for L.Next(3) {
+			field, okField := L.ToString(-2)
+			filter, okFilter := L.ToString(-1)
+			L.Pop(1)
+			if !okField || !okFilter {
+				L.Pop(1)
+				errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+					"Name": "filter",
+				}), errorhandlefunc.ErrorTypeScript, true)
+				return 0
+			}
+			filters[field] = filter
+		}
+	} else {
+		field, ok := L.ToString(3) // Get the field name from Lua
+		if !ok {
+			errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+				"Name": "field name",
+			}), errorhandlefunc.ErrorTypeScript, true)
+			return 0
+		}
+		filter, ok := L.ToString(4) // Get the filter from Lua
+		if !ok {
+			errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+				"Name": "filter",
+			}), errorhandlefunc.ErrorTypeScript, true)
+			return 0
+		}
+		filters[field] = filter
+	}
+	table := gormfunc.OpenTable(db, tableName)
+	if table == nil {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.table_open_failed", map[string]interface{}{
+			"Name": tableName,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	for field, filter := range filters {
+		if table.GetFieldType(field) == "" {
+			errorhandlefunc.ThrowError(i18nfunc.T("error.db_field_not_found", map[string]interface{}{
+				"Field": field,
+				"Table": tableName,
+			}), errorhandlefunc.ErrorTypeScript, true)
+			return 0
+		}
+		table.SetFilter(field, filter)
+	}
+	table.Find()
+	return pushTable(L, table)
+}
+
+func dbCreate(L *lua.State) int {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "DBCreate",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	dbName, ok := L.ToString(1) // Get the table name from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "table name",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	db, err := gormfunc.CreateDB(dbName) // Create the database
+	if err != nil {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.db_create_failed", nil), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	L.PushUserData(db) // Push the database as userdata
+	return 1           // Return the number of results
+}
+
+func dbCreateTable(L *lua.State) int {
+	if L.Top() < 4 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "DBCreateTable",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	db, ok := L.ToUserData(1).(*gorm.DB) // Get the database from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_db", map[string]interface{}{
+			"Name": "database",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	tableName, ok := L.ToString(2) // Get the table name from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "table name",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	description, ok := L.ToString(3) // Get the table description from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "table description",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	openIfExists := L.ToBoolean(4) // Get the skip check from Lua
+
+	table := gormfunc.CreateTable(db, tableName, description, openIfExists, false) // Create the table
+	L.PushUserData(table)                                                          // Push the table as userdata
+	L.Global("TableMT")                                                            // Push the metatable
+	L.SetMetaTable(-2)                                                             // Set metatable for userdata
+	return 1                                                                       // Return the number of results
+}
+
+func dbCreateTableTemp(L *lua.State) int {
+	if L.Top() < 4 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "DBCreateTableTemp",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	db, ok := L.ToUserData(1).(*gorm.DB) // Get the database from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_db", map[string]interface{}{
+			"Name": "database",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	tableName, ok := L.ToString(2) // Get the table name from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "table name",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	description, ok := L.ToString(3) // Get the table description from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "table description",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	openIfExists := L.ToBoolean(4) // Get the skip check from Lua
+
+	table := gormfunc.CreateTable(db, tableName, description, openIfExists, true) // Create the table
+	L.PushUserData(table)                                                         // Push the table as userdata
+	L.Global("TableMT")                                                           // Push the metatable
+	L.SetMetaTable(-2)                                                            // Set metatable for userdata
+	return 1                                                                      // Return the number of results
+}
+
+func dbAlterTable(L *lua.State) int {
+	if L.Top() < 3 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "DBAlterTable",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	db, ok := L.ToUserData(1).(*gorm.DB) // Get the database from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_db", map[string]interface{}{
+			"Name": "database",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	tableName, ok := L.ToString(2) // Get the table name from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "table name",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	structure, ok := L.ToString(3) // Get the table description from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "table description",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+
+	table := gormfunc.AlterTable(db, tableName, structure) // Alter the table
+	L.PushUserData(table)                                  // Push the table as userdata
+	L.Global("TableMT")                                    // Push the metatable
+	L.SetMetaTable(-2)                                     // Set metatable for userdata
+	return 1                                               // Return the number of results
+}
+
+func dbDropTable(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "DBDropTable",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	db, ok := L.ToUserData(1).(*gorm.DB) // Get the database from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_db", map[string]interface{}{
+			"Name": "database",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	tableName, ok := L.ToString(2) // Get the table name from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "table name",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	askConfirm := confirmTableDrop
+	if L.Top() >= 3 {
+		askConfirm = L.ToBoolean(3) // Get the confirm flag from Lua
+	}
+	if askConfirm && !statefunc.IsBatchMode() {
+		// The table is dropped only after the user accepts the dialog
+		accepted := false
+		if !statefunc.WaitForAnswer(func(done func()) {
+			uifunc.Confirm(i18nfunc.T("dialog.drop_table", map[string]interface{}{
+				"Name": tableName,
+			}), func(ok bool) {
+				accepted = ok
+				done()
+			})
+		}) {
+			errorhandlefunc.ThrowError(i18nfunc.T("error.dialog_needs_async_run", map[string]interface{}{
+				"Name": "DBDropTable",
+			}), errorhandlefunc.ErrorTypeScript, true)
+			return 0
+		}
+		if !accepted {
+			L.PushBoolean(false)
+			return 1
+		}
+	}
+	err := gormfunc.DropTable(db, tableName) // Drop the table
+	if err != nil {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.db_drop_table_failed", map[string]interface{}{
+			"Name":  tableName,
+			"Error": err.Error(),
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	L.PushBoolean(true)
+	return 1
+}
+
+// dbRenameTable renames a table with its metadata and returns the reopened table
+func dbRenameTable(L *lua.State) int {
+	if L.Top() < 3 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "DBRenameTable",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	db, ok := L.ToUserData(1).(*gorm.DB) // Get the database from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_db", map[string]interface{}{
+			"Name": "database",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	oldName, ok := L.ToString(2)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "old name",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	newName, ok := L.ToString(3)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "new name",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	table, err := gormfunc.RenameTable(db, oldName, newName)
+	if err != nil {
+		errorhandlefunc.ThrowError(err.Error(), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	if table == nil {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.table_open_failed", map[string]interface{}{
+			"Name": newName,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	L.PushUserData(&gormfunc.TableWrapper{Table: table})
+	L.PushString("TableMT")
+	L.RawGet(lua.RegistryIndex)
+	L.SetMetaTable(-2)
+	return 1
+}
+
+// dbCreateView creates a view from a SELECT statement and returns true
+func dbCreateView(L *lua.State) int {
+	if L.Top() < 3 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "DBCreateView",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	db, ok := L.ToUserData(1).(*gorm.DB) // Get the database from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_db", map[string]interface{}{
+			"Name": "database",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	viewName, ok := L.ToString(2)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "view name",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	selectSQL, ok := L.ToString(3)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "select statement",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	if err := gormfunc.CreateView(db, viewName, selectSQL); err != nil {
+		errorhandlefunc.ThrowError(err.Error(), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	L.PushBoolean(true)
+	return 1
+}
+
+// dbDropView drops the view and returns true
+func dbDropView(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "DBDropView",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	db, ok := L.ToUserData(1).(*gorm.DB) // Get the database from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_db", map[string]interface{}{
+			"Name": "database",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	viewName, ok := L.ToString(2)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "view name",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	if err := gormfunc.DropView(db, viewName); err != nil {
+		errorhandlefunc.ThrowError(err.Error(), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	L.PushBoolean(true)
+	return 1
+}
+
+// setConfirmDrop sets whether DBDropTable called without the confirm argument asks before dropping
+func setConfirmDrop(L *lua.State) int {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "SetConfirmDrop",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	confirmTableDrop = L.ToBoolean(1)
+	return 0
+}
+
+// setTiming turns the timing of the table operations on or off, with an optional log file.
+// Returns false and keeps the error in the last error if the log can not be opened.
+func setTiming(L *lua.State) int {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "SetTiming",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	logPath := ""
+	if L.Top() >= 2 && !L.IsNil(2) {
+		var ok bool
+		logPath, ok = L.ToString(2)
+		if !ok {
+			errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+				"Name": "log path",
+			}), errorhandlefunc.ErrorTypeScript, true)
+			return 0
+		}
+	}
+	statefunc.ClearErrors()
+	if err := gormfunc.SetTiming(L.ToBoolean(1), logPath); err != nil {
+		statefunc.SetLastErrorText(i18nfunc.T("error.timing_log_failed", map[string]interface{}{
+			"Path":  logPath,
+			"Error": err.Error(),
+		}))
+		L.PushBoolean(false)
+		return 1
+	}
+	L.PushBoolean(true)
+	return 1
+}
+
+// getLastTiming returns the duration of the last timed table operation in milliseconds
+func getLastTiming(L *lua.State) int {
+	L.PushNumber(float64(gormfunc.LastTiming()) / float64(time.Millisecond))
+	return 1
+}
+
+func setFilter(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "SetFilter",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	field, ok := L.ToString(2) // Get the field name from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "field name",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	filter, ok := L.ToString(3) // Get the filter from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "filter",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper.Table.SetFilter(field, filter) // Set the filter for the table
+	return 1                               // Return success
+}
+
+func setRangeFilter(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "SetRangeFilter",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	field, ok := L.ToString(2) // Get the field name from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "field name",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	min, ok := L.ToInteger(3) // Get the minimum value from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_integer", map[string]interface{}{
+			"Name": "minimum value",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	max, ok := L.ToInteger(4) // Get the maximum value from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_integer", map[string]interface{}{
+			"Name": "maximum value",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper.Table.SetRangeFilter(field, min, max) // Set the range filter for the table
+	return 1                                      // Return success
+}
+
+func setOrderBy(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "SetOrderBy",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	orderBy, ok := L.ToString(2) // Get the order by from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "order by",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper.Table.OrderBy(orderBy) // Set the order by for the table
+	return 1                       // Return success
+}
+
+// setLimitOrOffset sets the limit or the offset of Find with the set function, 0 or less unsets it
+func setLimitOrOffset(L *lua.State, name string, set func(*gormfunc.Table, int) *gormfunc.Table) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": name,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	n, ok := L.ToInteger(2)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_integer", map[string]interface{}{
+			"Name": "n",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	set(wrapper.Table, n)
+	return 0
+}
+
+// setOrderField sets the integer field keeping the order of the rows set by the user
+func setOrderField(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "SetOrderField",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	field, ok := L.ToString(2) // Get the field name from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "field name",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	L.PushBoolean(wrapper.Table.SetOrderField(field))
+	return 1
+}
+
+// moveRecord moves the current row one place up or down in the order of the table
+func moveRecord(L *lua.State, move func(*gormfunc.Table) bool) int {
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	L.PushBoolean(move(wrapper.Table))
+	return 1
+}
+
+// moveTo moves the current row to a position in the order of the table
+func moveTo(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "MoveTo",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	position, ok := L.ToInteger(2)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_integer", map[string]interface{}{
+			"Name": "position",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	L.PushBoolean(wrapper.Table.MoveTo(position))
+	return 1
+}
+
+// setFieldDefault overrides the default value of a field for new rows
+func setFieldDefault(L *lua.State) int {
+	if L.Top() < 3 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "SetFieldDefault",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	field, ok := L.ToString(2) // Get the field name from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "field name",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	var value interface{}
+	switch L.TypeOf(3) {
+	case lua.TypeNumber:
+		value, _ = L.ToNumber(3)
+	case lua.TypeBoolean:
+		value = L.ToBoolean(3)
+	case lua.TypeString:
+		value, _ = L.ToString(3)
+	}
+	L.PushBoolean(wrapper.Table.SetFieldDefault(field, value))
+	return 1
+}
+
+// pushFieldValue pushes a value read from a table onto the Lua stack.
+// Values scanned into *interface{} are unwrapped, times are pushed as
+// "2006-01-02 15:04:05" like ExportDump writes them, and values of other
+// types as formatted strings.
+func pushFieldValue(L *lua.State, val interface{}) {
+	switch v := val.(type) {
+	case *interface{}:
+		if v == nil {
+			L.PushNil()
+			return
+		}
+		pushFieldValue(L, *v)
+	case string:
+		L.PushString(v)
+	case []byte:
+		L.PushString(string(v))
+	case int:
+		L.PushInteger(v)
+	case int64:
+		L.PushInteger(int(v))
+	case int32:
+		L.PushInteger(int(v))
+	case uint64:
+		L.PushInteger(int(v))
+	case float64:
+		L.PushNumber(v)
+	case float32:
+		L.PushNumber(float64(v))
+	case bool:
+		L.PushBoolean(v)
+	case time.Time:
+		L.PushString(v.Format("2006-01-02 15:04:05"))
+	case nil:
+		L.PushNil()
+	default:
+		L.PushString(fmt.Sprintf("%v", v))
+	}
+}
+
+// aggregateArgs checks the table and field arguments of an aggregate method
+func aggregateArgs(L *lua.State, name string) (*gormfunc.TableWrapper, string, bool) {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": name,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return nil, "", false
+	}
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return nil, "", false
+	}
+	field, ok := L.ToString(2)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "field name",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return nil, "", false
+	}
+	return wrapper, field, true
+}
+
+// numericAggregate returns the Sum or Avg of a numeric field, nil on error
+func numericAggregate(L *lua.State, name string) int {
+	wrapper, field, ok := aggregateArgs(L, name)
+	if !ok {
+		return 0
+	}
+	var result float64
+	if name == "Sum" {
+		result, ok = wrapper.Table.Sum(field)
+	} else {
+		result, ok = wrapper.Table.Avg(field)
+	}
+	if !ok {
+		L.PushNil()
+		return 1
+	}
+	L.PushNumber(result)
+	return 1
+}
+
+// valueAggregate returns the Min or Max of a field, nil if no row matches or on error
+func valueAggregate(L *lua.State, name string) int {
+	wrapper, field, ok := aggregateArgs(L, name)
+	if !ok {
+		return 0
+	}
+	var result interface{}
+	if name == "Min" {
+		result, ok = wrapper.Table.Min(field)
+	} else {
+		result, ok = wrapper.Table.Max(field)
+	}
+	if !ok || result == nil {
+		L.PushNil()
+		return 1
+	}
+	pushFieldValue(L, result)
+	return 1
+}
+
+// count returns the number of rows matching the current filters, nil on error
+func count(L *lua.State) int {
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	n, ok := wrapper.Table.Count()
+	if !ok {
+		L.PushNil()
+		return 1
+	}
+	L.PushInteger(int(n))
+	return 1
+}
+
+// rowCountLoaded returns the number of rows loaded by the last Find
+func rowCountLoaded(L *lua.State) int {
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	L.PushInteger(wrapper.Table.RowCountLoaded())
+	return 1
+}
+
+// hasMore returns whether more rows follow the page loaded by the last Find
+func hasMore(L *lua.State) int {
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	L.PushBoolean(wrapper.Table.HasMore())
+	return 1
+}
+
+// distinctValues returns a Lua array with the distinct values of a field, or nil on error
+func distinctValues(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "DistinctValues",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	field, ok := L.ToString(2) // Get the field name from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "field name",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	values, ok := wrapper.Table.DistinctValues(field)
+	if !ok {
+		L.PushNil()
+		return 1
+	}
+	L.CreateTable(len(values), 0)
+	for i, v := range values {
+		pushFieldValue(L, v)
+		L.RawSetInt(-2, i+1)
+	}
+	return 1
+}
+
+// dbImportCSV inserts the rows of a CSV file into the table in one transaction.
+// Returns the number of rows inserted, or nil on error.
+func dbImportCSV(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "DBImportCSV",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	path, ok := L.ToString(2) // Get the file path from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "path",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	count, ok := wrapper.Table.ImportCSV(path)
+	if !ok {
+		L.PushNil()
+		return 1
+	}
+	L.PushInteger(count)
+	return 1
+}
+
+// exportCSV writes the rows matching the filters of the table to a CSV file.
+// Returns the number of rows written, or nil on error.
+func exportCSV(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "ExportCSV",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	path, ok := L.ToString(2) // Get the file path from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "path",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	count, ok := wrapper.Table.ExportCSV(path)
+	if !ok {
+		L.PushNil()
+		return 1
+	}
+	L.PushInteger(count)
+	return 1
+}
+
+func setCollation(L *lua.State) int {
+	if L.Top() < 3 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "SetCollation",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	field, ok := L.ToString(2) // Get the field name from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "field name",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	collation, ok := L.ToString(3) // Get the collation from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "collation",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	L.PushBoolean(wrapper.Table.SetCollation(field, collation))
+	return 1
+}
+
+// setFilterMode sets whether the field filters of the table combine with AND or OR
+func setFilterMode(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "SetFilterMode",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	mode, ok := L.ToString(2) // Get the mode from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "mode",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	L.PushBoolean(wrapper.Table.SetFilterMode(mode))
+	return 1
+}
+
+// saveFilter stores the filters of the table under a name, returns false on error
+func saveFilter(L *lua.State) int {
+	return namedFilter(L, "SaveFilter", (*gormfunc.Table).SaveFilter)
+}
+
+// loadFilter sets the filters saved under a name, returns false if there are none or on error
+func loadFilter(L *lua.State) int {
+	return namedFilter(L, "LoadFilter", (*gormfunc.Table).LoadFilter)
+}
+
+func namedFilter(L *lua.State, name string, f func(*gormfunc.Table, string) bool) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": name,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	filterName, ok := L.ToString(2)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "name",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	L.PushBoolean(f(wrapper.Table, filterName))
+	return 1
+}
+
+// listFilters returns the names of the filters saved for the table, or nil on error
+func listFilters(L *lua.State) int {
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	names, ok := wrapper.Table.ListFilters()
+	if !ok {
+		L.PushNil()
+		return 1
+	}
+	L.CreateTable(len(names), 0)
+	for i, name := range names {
+		L.PushString(name)
+		L.RawSetInt(-2, i+1)
+	}
+	return 1
+}
+
+// setSoftDelete makes deletes flag the rows with the field instead of removing them
+func setSoftDelete(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "SetSoftDelete",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	field := ""
+	if !L.IsNil(2) {
+		var ok bool
+		field, ok = L.ToString(2)
+		if !ok {
+			errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+				"Name": "field name",
+			}), errorhandlefunc.ErrorTypeScript, true)
+			return 0
+		}
+	}
+	L.PushBoolean(wrapper.Table.SetSoftDelete(field))
+	return 1
+}
+
+// includeDeleted sets whether Find returns the soft deleted rows, true if not given
+func includeDeleted(L *lua.State) int {
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	include := true
+	if L.Top() >= 2 {
+		include = L.ToBoolean(2)
+	}
+	wrapper.Table.IncludeDeleted(include)
+	return 0
+}
+
+func insert(L *lua.State) int {
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+
+	fields := wrapper.Table.GetCurrentRecord()
+	var id int64
+	result := wrapper.Table.Insert(fields, &id) // Insert the fields into the table
+	L.PushBoolean(result)
+	if result {
+		L.PushInteger(int(id))
+		return 2
+	}
+	return 1 // Return success
+}
+
+// deleteWhere deletes the rows matching the filters of the table and returns how many were deleted,
+// or nil on error. Without a filter it refuses to run unless the force argument is true.
+func deleteWhere(L *lua.State) int {
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	force := false
+	if L.Top() >= 2 {
+		force = L.ToBoolean(2)
+	}
+	n, ok := wrapper.Table.DeleteWhere(force)
+	if !ok {
+		L.PushNil()
+		return 1
+	}
+	L.PushInteger(int(n))
+	return 1
+}
+
+// upsert updates the row with the key values of the record or inserts it: Upsert(record, [keyFields]).
+// Returns the id of the row and true if it was inserted, nil on error.
+func upsert(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "Upsert",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	if !L.IsTable(2) {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_lua_table", map[string]interface{}{
+			"Name": "record",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	fields := make(gormfunc.Record)
+	L.PushNil()
+	for L.Next(2) {
+		if key, ok := L.ToString(-2); ok && L.TypeOf(-2) == lua.TypeString {
+			fields[key] = L.ToValue(-1)
+		}
+		L.Pop(1)
+	}
+	var keyFields []string
+	for _, k := range strings.Split(optionalString(L, 3, ""), ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			keyFields = append(keyFields, k)
+		}
+	}
+	id, inserted, ok := wrapper.Table.Upsert(fields, keyFields)
+	if !ok {
+		L.PushNil()
+		return 1
+	}
+	L.PushInteger(int(id))
+	L.PushBoolean(inserted)
+	return 2
+}
+
+// find retrieves all rows from the table and returns them as a Rowset
+func find(L *lua.State) int {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.extra_args", map[string]interface{}{
+			"Name": "Find",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	L.PushBoolean(wrapper.Table.Find()) // Call the Find method on the table
+	return 1                            // Return the number of results
+}
+
+// FindLast retrieves the last row from the table based on current filters and ordering.
+func findLast(L *lua.State) int {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.extra_args", map[string]interface{}{
+			"Name": "FindLast",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	L.PushBoolean(wrapper.Table.FindLast()) // Call the FindLast method on the table
+	return 1                                // Return the number of results
+}
+
+func findByID(L *lua.State) int {
+
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.extra_args", map[string]interface{}{
+			"Name": "FindByID",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	id, ok := L.ToInteger(2) // Get the ID from Lua
+	if !ok {
+		if L.IsNil(2) {
+			L.PushNil()
+			return 1
+		}
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_integer", map[string]interface{}{
+			"Name": "ID",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	value := wrapper.Table.FindByID(id) // Call the FindByID method on the table
+	if !value {
+		L.PushNil() // Push nil if no rows found
+	} else {
+		L.PushBoolean(value) // Push the value as boolean
+	}
+	return 1 // Return the number of results
+}
+
+// next retrieves the next row from the table and returns it as a Rowset
+func next(L *lua.State) int {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.extra_args", map[string]interface{}{
+			"Name": "Next",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	L.PushBoolean(wrapper.Table.Next()) // Call the Next method on the table
+	return 1                            // Return the number of results
+}
+
+// prev retrieves the previous row from the table and returns it as a Rowset
+func prev(L *lua.State) int {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.extra_args", map[string]interface{}{
+			"Name": "Prev",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	L.PushBoolean(wrapper.Table.Prev()) // Call the Prev method on the table
+	return 1                            // Return the number of results
+}
+
+func update(L *lua.State) int {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "Update",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	ud := L.ToUserData(1)
+	if ud == nil {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_table", nil), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper, ok := ud.(*gormfunc.TableWrapper)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_table", nil), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	table := wrapper.Table // Get the table from the wrapper
+	x := table.GetCurrentRecord()[gormfunc.PrimaryKeyField]
+	var id int64
+	switch v := x.(type) {
+	case int:
+		id = int64(v)
+	case int64:
+		id = v
+	default:
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_integer", map[string]interface{}{
+			"Name": "ID",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	result := table.Update(id, table.GetCurrentRecord()) // Update the table with the current record
+	L.SetTop(0)
+	L.PushBoolean(result)
+	return 1
+}
 
-	// Get the database from Lua
-	ud := L.ToUserData(1)
-	if ud == nil {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_db", map[string]interface{}{
-			"Name": "database",
+// setTransform sets the read or write transform function of a table field
+func setTransform(L *lua.State, name string) int {
+	if L.Top() < 3 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": name,
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	db, ok := ud.(*gorm.DB)
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	field, ok := L.ToString(2) // Get the field name from Lua
 	if !ok {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_db", map[string]interface{}{
-			"Name": "database",
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "field name",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	funcName, ok := L.ToString(3) // Get the function name from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "function name",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
+	if name == "SetReadTransform" {
+		wrapper.Table.SetReadTransform(field, funcName)
+	} else {
+		wrapper.Table.SetWriteTransform(field, funcName)
+	}
+	L.PushBoolean(true)
+	return 1
+}
 
-	// Get the table name from Lua
-	tableName, ok := L.ToString(2)
+// tableTransaction calls a Lua function with the table inside a transaction
+func tableTransaction(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "Transaction",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	funcName, ok := L.ToString(2) // Get the function name from Lua
 	if !ok {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
-			"Name": "table name",
+			"Name": "function name",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
+	L.PushBoolean(wrapper.Table.Transaction(funcName))
+	return 1
+}
 
-	// Open the table
-	table := gormfunc.OpenTable(db, tableName)
-	if table == nil {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.table_open_failed", map[string]interface{}{
-			"Name": tableName,
+// dbTransaction calls a Lua function with the database inside a transaction.
+// The transaction is rolled back if the function raises an error or returns false.
+func dbTransaction(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "DBTransaction",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	db, ok := L.ToUserData(1).(*gorm.DB) // Get the database from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_db", map[string]interface{}{
+			"Name": "database",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	funcName, ok := L.ToString(2) // Get the function name from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "function name",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
+	L.Global(funcName)
+	if !L.IsFunction(-1) {
+		L.Pop(1)
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_a_function", map[string]interface{}{
+			"Name": funcName,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	L.Pop(1)
+	statefunc.ClearErrors()
+	result := gormfunc.RunInTransaction(db, func() bool {
+		L.Global(funcName)
+		L.PushUserData(db)
+		L.Call(1, 1)
+		returned := !L.IsNil(-1)
+		result := L.ToBoolean(-1)
+		L.Pop(1)
+		return result || !returned
+	})
+	L.PushBoolean(result)
+	return 1
+}
 
-	// Create a new wrapper
-	wrapper := &gormfunc.TableWrapper{Table: table}
+// dbBegin opens a transaction on the database. The changes of all its tables are kept
+// until DBCommit and undone by DBRollback. Returns false if a transaction is already open.
+func dbBegin(L *lua.State) int {
+	return transactionStep(L, "DBBegin", gormfunc.BeginTransaction)
+}
 
-	// Push the wrapper as userdata
-	L.PushUserData(wrapper)
+// dbCommit commits the transaction opened by DBBegin and runs the after callbacks of its changes
+func dbCommit(L *lua.State) int {
+	return transactionStep(L, "DBCommit", gormfunc.CommitTransaction)
+}
 
-	// Get and set the metatable from registry
-	L.PushString("TableMT")
-	L.RawGet(lua.RegistryIndex)
-	if L.IsNil(-1) {
-		L.Pop(1)
-		L.PushString("TableMT metatable not found")
-		L.Error()
+// dbRollback undoes the changes of the transaction opened by DBBegin, their after callbacks never run
+func dbRollback(L *lua.State) int {
+	return transactionStep(L, "DBRollback", gormfunc.RollbackTransaction)
+}
+
+// transactionStep checks the database argument of DBBegin, DBCommit and DBRollback and calls step on it.
+// Returns false and keeps the error as the last error if step fails.
+func transactionStep(L *lua.State, name string, step func(*gorm.DB) error) int {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": name,
+		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	L.SetMetaTable(-2)
+	db, ok := L.ToUserData(1).(*gorm.DB)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_db", map[string]interface{}{
+			"Name": "database",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	statefunc.ClearErrors()
+	if err := step(db); err != nil {
+		statefunc.SetLastErrorText(err.Error())
+		L.PushBoolean(false)
+		return 1
+	}
+	L.PushBoolean(true)
 	return 1
 }
 
-func dbCreate(L *lua.State) int {
-	if L.Top() < 1 {
+// dbExecScript runs the statements of a SQL file in one transaction.
+// Returns false and keeps the failed statement in the last error if any statement fails.
+func dbExecScript(L *lua.State) int {
+	if L.Top() < 2 {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
-			"Name": "DBCreate",
+			"Name": "DBExecScript",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	dbName, ok := L.ToString(1) // Get the table name from Lua
+	db, ok := L.ToUserData(1).(*gorm.DB) // Get the database from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_db", map[string]interface{}{
+			"Name": "database",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	path, ok := L.ToString(2) // Get the script path from Lua
 	if !ok {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
-			"Name": "table name",
+			"Name": "script path",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	db, err := gormfunc.CreateDB(dbName) // Create the database
+	if err := gormfunc.ExecScript(db, path); err != nil {
+		statefunc.SetLastErrorText(err.Error())
+		L.PushBoolean(false)
+		return 1
+	}
+	L.PushBoolean(true)
+	return 1
+}
+
+// dbQuery runs a SQL query with the arguments after the query bound to its ? placeholders.
+// Returns an array of rows, each a table by column name, or nil on error.
+func dbQuery(L *lua.State) int {
+	db, query, args, ok := rawSQLArgs(L, "DBQuery")
+	if !ok {
+		return 0
+	}
+	statefunc.ClearErrors()
+	records, err := gormfunc.Query(db, query, args...)
 	if err != nil {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.db_create_failed", nil), errorhandlefunc.ErrorTypeScript, true)
+		statefunc.SetLastErrorText(err.Error())
+		L.PushNil()
+		return 1
+	}
+	L.CreateTable(len(records), 0)
+	for i, rec := range records {
+		PushRecWithDotNotation(L, rec)
+		L.RawSetInt(-2, i+1)
+	}
+	return 1
+}
+
+// dbExec runs a SQL statement with the arguments after it bound to its ? placeholders.
+// Returns the number of changed rows, or nil on error.
+func dbExec(L *lua.State) int {
+	db, statement, args, ok := rawSQLArgs(L, "DBExec")
+	if !ok {
 		return 0
 	}
-	L.PushUserData(db) // Push the database as userdata
-	return 1           // Return the number of results
+	statefunc.ClearErrors()
+	n, err := gormfunc.Exec(db, statement, args...)
+	if err != nil {
+		statefunc.SetLastErrorText(err.Error())
+		L.PushNil()
+		return 1
+	}
+	L.PushInteger(int(n))
+	return 1
 }
 
-func dbCreateTable(L *lua.State) int {
-	if L.Top() < 4 {
+// rawSQLArgs checks the database and SQL arguments of DBQuery and DBExec and returns them
+// with the values to bind. Whole numbers are bound as integers.
+func rawSQLArgs(L *lua.State, name string) (*gorm.DB, string, []interface{}, bool) {
+	if L.Top() < 2 {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
-			"Name": "DBCreateTable",
+			"Name": name,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return nil, "", nil, false
+	}
+	db, ok := L.ToUserData(1).(*gorm.DB) // Get the database from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_db", map[string]interface{}{
+			"Name": "database",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return nil, "", nil, false
+	}
+	sql, ok := L.ToString(2)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "sql",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return nil, "", nil, false
+	}
+	var args []interface{}
+	for i := 3; i <= L.Top(); i++ {
+		switch L.TypeOf(i) {
+		case lua.TypeNil:
+			args = append(args, nil)
+		case lua.TypeBoolean:
+			args = append(args, L.ToBoolean(i))
+		case lua.TypeNumber:
+			f, _ := L.ToNumber(i)
+			if f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+				args = append(args, int64(f))
+			} else {
+				args = append(args, f)
+			}
+		case lua.TypeString:
+			s, _ := L.ToString(i)
+			args = append(args, s)
+		default:
+			errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+				"Name": fmt.Sprintf("argument %d", i-2),
+			}), errorhandlefunc.ErrorTypeScript, true)
+			return nil, "", nil, false
+		}
+	}
+	return db, sql, args, true
+}
+
+// dbExportSchema returns the structure of all user tables of the database
+func dbExportSchema(L *lua.State) int {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "DBExportSchema",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
@@ -1493,33 +3836,56 @@ func dbCreateTable(L *lua.State) int {
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	tableName, ok := L.ToString(2) // Get the table name from Lua
+	schema, err := gormfunc.ExportSchema(db)
+	if err != nil {
+		errorhandlefunc.ThrowError(err.Error(), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	L.PushString(schema)
+	return 1
+}
+
+// dbExportDump writes the tables and rows of the database to a SQL file, which DBExecScript runs again.
+// Returns false and keeps the error in the last error if the dump can not be written.
+func dbExportDump(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "DBExportDump",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	db, ok := L.ToUserData(1).(*gorm.DB) // Get the database from Lua
 	if !ok {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
-			"Name": "table name",
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_db", map[string]interface{}{
+			"Name": "database",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	description, ok := L.ToString(3) // Get the table description from Lua
+	path, ok := L.ToString(2) // Get the dump path from Lua
 	if !ok {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
-			"Name": "table description",
+			"Name": "dump path",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	openIfExists := L.ToBoolean(4) // Get the skip check from Lua
-
-	table := gormfunc.CreateTable(db, tableName, description, openIfExists, false) // Create the table
-	L.PushUserData(table)                                                          // Push the table as userdata
-	L.Global("TableMT")                                                            // Push the metatable
-	L.SetMetaTable(-2)                                                             // Set metatable for userdata
-	return 1                                                                       // Return the number of results
+	statefunc.ClearErrors()
+	if err := gormfunc.ExportDump(db, path); err != nil {
+		statefunc.SetLastErrorText(i18nfunc.T("error.export_failed", map[string]interface{}{
+			"Path":  path,
+			"Error": err.Error(),
+		}))
+		L.PushBoolean(false)
+		return 1
+	}
+	L.PushBoolean(true)
+	return 1
 }
 
-func dbCreateTableTemp(L *lua.State) int {
-	if L.Top() < 4 {
+// dbImportSchema creates the missing tables of a schema made by DBExportSchema
+func dbImportSchema(L *lua.State) int {
+	if L.Top() < 2 {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
-			"Name": "DBCreateTableTemp",
+			"Name": "DBImportSchema",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
@@ -1530,33 +3896,74 @@ func dbCreateTableTemp(L *lua.State) int {
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	tableName, ok := L.ToString(2) // Get the table name from Lua
+	schema, ok := L.ToString(2) // Get the schema from Lua
 	if !ok {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
-			"Name": "table name",
+			"Name": "schema",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	description, ok := L.ToString(3) // Get the table description from Lua
+	created, err := gormfunc.ImportSchema(db, schema)
+	if err != nil {
+		errorhandlefunc.ThrowError(err.Error(), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	L.PushInteger(created)
+	return 1
+}
+
+// lastInsertID returns the id of the last row inserted through the table
+func lastInsertID(L *lua.State) int {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "LastInsertID",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	L.PushInteger(int(wrapper.Table.LastInsertID()))
+	return 1
+}
+
+// nextSequence returns the next number of a named counter of the database, nil on errors
+func nextSequence(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "NextSequence",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	db, ok := L.ToUserData(1).(*gorm.DB) // Get the database from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_db", map[string]interface{}{
+			"Name": "database",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	name, ok := L.ToString(2)
 	if !ok {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
-			"Name": "table description",
+			"Name": "sequence name",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	openIfExists := L.ToBoolean(4) // Get the skip check from Lua
-
-	table := gormfunc.CreateTable(db, tableName, description, openIfExists, true) // Create the table
-	L.PushUserData(table)                                                         // Push the table as userdata
-	L.Global("TableMT")                                                           // Push the metatable
-	L.SetMetaTable(-2)                                                            // Set metatable for userdata
-	return 1                                                                      // Return the number of results
+	value, ok := gormfunc.NextSequence(db, name)
+	if !ok {
+		L.PushNil()
+		return 1
+	}
+	L.PushInteger(int(value))
+	return 1
 }
 
-func dbAlterTable(L *lua.State) int {
+// dbResetSequence sets the id the next row inserted into the table gets
+func dbResetSequence(L *lua.State) int {
 	if L.Top() < 3 {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
-			"Name": "DBAlterTable",
+			"Name": "DBResetSequence",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
@@ -1567,32 +3974,29 @@ func dbAlterTable(L *lua.State) int {
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	tableName, ok := L.ToString(2) // Get the table name from Lua
+	table, ok := L.ToString(2)
 	if !ok {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
 			"Name": "table name",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	structure, ok := L.ToString(3) // Get the table description from Lua
+	next, ok := L.ToInteger(3)
 	if !ok {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
-			"Name": "table description",
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_integer", map[string]interface{}{
+			"Name": "value",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-
-	table := gormfunc.AlterTable(db, tableName, structure) // Alter the table
-	L.PushUserData(table)                                  // Push the table as userdata
-	L.Global("TableMT")                                    // Push the metatable
-	L.SetMetaTable(-2)                                     // Set metatable for userdata
-	return 1                                               // Return the number of results
+	L.PushBoolean(gormfunc.ResetSequence(db, table, int64(next)))
+	return 1
 }
 
-func dbDropTable(L *lua.State) int {
+// dbTableExists returns whether the database has the table
+func dbTableExists(L *lua.State) int {
 	if L.Top() < 2 {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
-			"Name": "DBDropTable",
+			"Name": "DBTableExists",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
@@ -1603,130 +4007,105 @@ func dbDropTable(L *lua.State) int {
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	tableName, ok := L.ToString(2) // Get the table name from Lua
+	table, ok := L.ToString(2)
 	if !ok {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
 			"Name": "table name",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	err := gormfunc.DropTable(db, tableName) // Drop the table
-	if err != nil {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.db_drop_table_failed", nil), errorhandlefunc.ErrorTypeScript, true)
-		return 0
-	}
-	return 1 // Return success
+	L.PushBoolean(gormfunc.TableExists(db, table))
+	return 1
 }
 
-func setFilter(L *lua.State) int {
-	if L.Top() < 2 {
+// dbGetVersion returns the schema version of the database, nil on error
+func dbGetVersion(L *lua.State) int {
+	if L.Top() < 1 {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
-			"Name": "SetFilter",
+			"Name": "DBGetVersion",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	wrapper := checkTable(L)
-	if wrapper == nil {
-		return 0
-	}
-	field, ok := L.ToString(2) // Get the field name from Lua
+	db, ok := L.ToUserData(1).(*gorm.DB) // Get the database from Lua
 	if !ok {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
-			"Name": "field name",
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_db", map[string]interface{}{
+			"Name": "database",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	filter, ok := L.ToString(3) // Get the filter from Lua
+	version, ok := gormfunc.GetVersion(db)
 	if !ok {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
-			"Name": "filter",
-		}), errorhandlefunc.ErrorTypeScript, true)
-		return 0
+		L.PushNil()
+		return 1
 	}
-	wrapper.Table.SetFilter(field, filter) // Set the filter for the table
-	return 1                               // Return success
+	L.PushInteger(int(version))
+	return 1
 }
 
-func setRangeFilter(L *lua.State) int {
+// dbSetVersion sets the schema version of the database
+func dbSetVersion(L *lua.State) int {
 	if L.Top() < 2 {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
-			"Name": "SetRangeFilter",
-		}), errorhandlefunc.ErrorTypeScript, true)
-		return 0
-	}
-	wrapper := checkTable(L)
-	if wrapper == nil {
-		return 0
-	}
-	field, ok := L.ToString(2) // Get the field name from Lua
-	if !ok {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
-			"Name": "field name",
+			"Name": "DBSetVersion",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	min, ok := L.ToInteger(3) // Get the minimum value from Lua
+	db, ok := L.ToUserData(1).(*gorm.DB) // Get the database from Lua
 	if !ok {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_integer", map[string]interface{}{
-			"Name": "minimum value",
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_db", map[string]interface{}{
+			"Name": "database",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	max, ok := L.ToInteger(4) // Get the maximum value from Lua
+	version, ok := L.ToInteger(2)
 	if !ok {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_integer", map[string]interface{}{
-			"Name": "maximum value",
+			"Name": "version",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	wrapper.Table.SetRangeFilter(field, min, max) // Set the range filter for the table
-	return 1                                      // Return success
+	L.PushBoolean(gormfunc.SetVersion(db, int64(version)))
+	return 1
 }
 
-func setOrderBy(L *lua.State) int {
-	if L.Top() < 2 {
+// dbColumnExists returns whether the table of the database has the column, false if there is no such table
+func dbColumnExists(L *lua.State) int {
+	if L.Top() < 3 {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
-			"Name": "SetOrderBy",
+			"Name": "DBColumnExists",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	wrapper := checkTable(L)
-	if wrapper == nil {
+	db, ok := L.ToUserData(1).(*gorm.DB) // Get the database from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_db", map[string]interface{}{
+			"Name": "database",
+		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	orderBy, ok := L.ToString(2) // Get the order by from Lua
+	table, ok := L.ToString(2)
 	if !ok {
 		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
-			"Name": "order by",
+			"Name": "table name",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	wrapper.Table.OrderBy(orderBy) // Set the order by for the table
-	return 1                       // Return success
-}
-
-func insert(L *lua.State) int {
-	wrapper := checkTable(L)
-	if wrapper == nil {
+	column, ok := L.ToString(3)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "column name",
+		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-
-	fields := wrapper.Table.GetCurrentRecord()
-	var id int64
-	result := wrapper.Table.Insert(fields, &id) // Insert the fields into the table
-	L.PushBoolean(result)
-	if result {
-		L.PushInteger(int(id))
-		return 2
-	}
-	return 1 // Return success
+	L.PushBoolean(gormfunc.ColumnExists(db, table, column))
+	return 1
 }
 
-// find retrieves all rows from the table and returns them as a Rowset
-func find(L *lua.State) int {
-	if L.Top() < 1 {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.extra_args", map[string]interface{}{
-			"Name": "Find",
+// walk calls a Lua function for every filtered row and updates the rows for which it returns true
+func walk(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "Walk",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
@@ -1734,31 +4113,24 @@ func find(L *lua.State) int {
 	if wrapper == nil {
 		return 0
 	}
-	L.PushBoolean(wrapper.Table.Find()) // Call the Find method on the table
-	return 1                            // Return the number of results
-}
-
-// FindLast retrieves the last row from the table based on current filters and ordering.
-func findLast(L *lua.State) int {
-	if L.Top() < 1 {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.extra_args", map[string]interface{}{
-			"Name": "Find",
+	funcName, ok := L.ToString(2) // Get the function name from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "function name",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	wrapper := checkTable(L)
-	if wrapper == nil {
-		return 0
-	}
-	L.PushBoolean(wrapper.Table.FindLast()) // Call the FindLast method on the table
-	return 1                                // Return the number of results
+	count, result := wrapper.Table.Walk(funcName)
+	L.PushBoolean(result)
+	L.PushInteger(count)
+	return 2
 }
 
-func findByID(L *lua.State) int {
-
+// getRawField pushes the stored value of a field of the current row, e.g. a date as yyyymmdd
+func getRawField(L *lua.State) int {
 	if L.Top() < 2 {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.extra_args", map[string]interface{}{
-			"Name": "FindByID",
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "GetRawField",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
@@ -1766,47 +4138,31 @@ func findByID(L *lua.State) int {
 	if wrapper == nil {
 		return 0
 	}
-	id, ok := L.ToInteger(2) // Get the ID from Lua
+	field, ok := L.ToString(2) // Get the field name from Lua
 	if !ok {
-		if L.IsNil(2) {
-			L.PushNil()
-			return 1
-		}
-		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_integer", map[string]interface{}{
-			"Name": "ID",
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "field name",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	value := wrapper.Table.FindByID(id) // Call the FindByID method on the table
-	if !value {
-		L.PushNil() // Push nil if no rows found
-	} else {
-		L.PushBoolean(value) // Push the value as boolean
-	}
-	return 1 // Return the number of results
-}
-
-// next retrieves the next row from the table and returns it as a Rowset
-func next(L *lua.State) int {
-	if L.Top() < 1 {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.extra_args", map[string]interface{}{
-			"Name": "Next",
+	val, exists := wrapper.Table.GetRawField(field)
+	if !exists {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.db_field_not_found", map[string]interface{}{
+			"Field": field,
+			"Table": wrapper.Table.Name,
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	wrapper := checkTable(L)
-	if wrapper == nil {
-		return 0
-	}
-	L.PushBoolean(wrapper.Table.Next()) // Call the Next method on the table
-	return 1                            // Return the number of results
+	pushFieldValue(L, val)
+	return 1
 }
 
-// prev retrieves the previous row from the table and returns it as a Rowset
-func prev(L *lua.State) int {
-	if L.Top() < 1 {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.extra_args", map[string]interface{}{
-			"Name": "Prev",
+// getTypedField pushes the value of a field of the current row converted to
+// the requested type. A value that cannot be converted raises an error.
+func getTypedField(L *lua.State, name, dtType string) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": name,
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
@@ -1814,44 +4170,102 @@ func prev(L *lua.State) int {
 	if wrapper == nil {
 		return 0
 	}
-	L.PushBoolean(wrapper.Table.Prev()) // Call the Prev method on the table
-	return 1                            // Return the number of results
-}
-
-func update(L *lua.State) int {
-	if L.Top() < 1 {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
-			"Name": "Update",
+	field, ok := L.ToString(2) // Get the field name from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "field name",
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	ud := L.ToUserData(1)
-	if ud == nil {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_table", nil), errorhandlefunc.ErrorTypeScript, true)
+	val := wrapper.Table.GetField(field, "")
+	if val == nil {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.db_field_not_found", map[string]interface{}{
+			"Field": field,
+			"Table": wrapper.Table.Name,
+		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	wrapper, ok := ud.(*gormfunc.TableWrapper)
-	if !ok {
-		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_table", nil), errorhandlefunc.ErrorTypeScript, true)
-		return 0
+	if vp, ok := val.(*interface{}); ok {
+		val = *vp
 	}
-	table := wrapper.Table // Get the table from the wrapper
-	x := table.GetCurrentRecord()[gormfunc.PrimaryKeyField]
-	var id int64
-	switch v := x.(type) {
-	case int:
-		id = int64(v)
-	case int64:
-		id = v
-	default:
-		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_integer", map[string]interface{}{
-			"Name": "ID",
+	mismatch := func() int {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.field_type_mismatch", map[string]interface{}{
+			"Field": field,
+			"Table": wrapper.Table.Name,
+			"Type":  dtType,
+			"Value": val,
 		}), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	result := table.Update(id, table.GetCurrentRecord()) // Update the table with the current record
-	L.SetTop(0)
-	L.PushBoolean(result)
+	switch dtType {
+	case typesfunc.TypeText:
+		L.PushString(fmt.Sprintf("%v", val))
+	case typesfunc.TypeInteger:
+		switch v := val.(type) {
+		case int:
+			L.PushInteger(v)
+		case int64:
+			L.PushInteger(int(v))
+		case float64:
+			if v != math.Trunc(v) {
+				return mismatch()
+			}
+			L.PushInteger(int(v))
+		case string:
+			i, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			if err != nil {
+				return mismatch()
+			}
+			L.PushInteger(int(i))
+		default:
+			return mismatch()
+		}
+	case typesfunc.TypeReal:
+		switch v := val.(type) {
+		case int:
+			L.PushNumber(float64(v))
+		case int64:
+			L.PushNumber(float64(v))
+		case float64:
+			L.PushNumber(v)
+		case string:
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return mismatch()
+			}
+			L.PushNumber(f)
+		default:
+			return mismatch()
+		}
+	case typesfunc.TypeBoolean:
+		switch v := val.(type) {
+		case bool:
+			L.PushBoolean(v)
+		case int:
+			L.PushBoolean(v != 0)
+		case int64:
+			L.PushBoolean(v != 0)
+		case string:
+			switch strings.ToLower(v) {
+			case "true", "1":
+				L.PushBoolean(true)
+			case "false", "0", "":
+				L.PushBoolean(false)
+			default:
+				return mismatch()
+			}
+		default:
+			return mismatch()
+		}
+	case typesfunc.TypeDate:
+		// Dates are returned as strings in the user format
+		switch wrapper.Table.GetFieldType(field) {
+		case typesfunc.TypeDate, typesfunc.TypeTime, typesfunc.TypeDateTime:
+			L.PushString(fmt.Sprintf("%v", val))
+		default:
+			return mismatch()
+		}
+	}
 	return 1
 }
 
@@ -1948,6 +4362,20 @@ func PushRecWithDotNotation(L *lua.State, rec gormfunc.Record) {
 	})
 	L.RawSet(-3)
 
+	// Keep the field names, pairs and RecordKeys list the fields with nil values too
+	L.PushString(recordFieldsKey)
+	L.NewTable()
+	for i, k := range sortedRecordKeys(rec) {
+		L.PushString(k)
+		L.RawSetInt(-2, i+1)
+	}
+	L.RawSet(-3)
+
+	// Set __pairs metamethod
+	L.PushString("__pairs")
+	L.PushGoFunction(recordPairs)
+	L.RawSet(-3)
+
 	// Set the metatable
 	L.SetMetaTable(-2)
 
@@ -1955,20 +4383,10 @@ func PushRecWithDotNotation(L *lua.State, rec gormfunc.Record) {
 	for k, v := range rec {
 		L.PushString(k)
 		switch val := v.(type) {
-		case string:
-			L.PushString(val)
-		case int:
-			L.PushInteger(val)
-		case float64:
-			L.PushNumber(val)
-		case bool:
-			L.PushBoolean(val)
-		case nil:
-			L.PushNil()
 		case gormfunc.Record:
 			PushRecWithDotNotation(L, val) // Recursively handle nested maps ????
 		default:
-			L.PushString(fmt.Sprintf("%v", val))
+			pushFieldValue(L, val)
 		}
 		L.RawSet(-3)
 	}
@@ -0,0 +1,608 @@
+package luafunc
+
+import (
+	"gotulua/errorhandlefunc"
+	"gotulua/i18nfunc"
+	"gotulua/statefunc"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Shopify/go-lua"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func TestMain(m *testing.M) {
+	i18nfunc.InitI18n("en") // The errors are checked in their English text
+	os.Exit(m.Run())
+}
+
+// newBatchState creates the interpreter with all its functions, running scripts without the UI
+// as the -batch flag does
+func newBatchState(t *testing.T) *lua.State {
+	t.Helper()
+	L, _ := CreateLuaInterpreter()
+	errorhandlefunc.SetLuaState(L)
+	statefunc.SetBatchMode(true)
+	t.Cleanup(func() { statefunc.SetBatchMode(false) })
+	return L
+}
+
+// runBatch runs the code as a script file with RunLuaScriptBatch and returns its error.
+// The tests check their results with assert in the code.
+func runBatch(t *testing.T, code string) error {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.lua")
+	if err := os.WriteFile(path, []byte(code), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return RunLuaScriptBatch(path)
+}
+
+// newInteractiveState creates the interpreter with all its functions and runs the UI loop
+// on a simulation screen until the test ends, so scripts can show dialogs
+func newInteractiveState(t *testing.T) *lua.State {
+	t.Helper()
+	app := tview.NewApplication().SetScreen(tcell.NewSimulationScreen(""))
+	statefunc.SetState(tview.NewFlex(), tview.NewFlex(), tview.NewPages(), app)
+	L, _ := CreateLuaInterpreter()
+	errorhandlefunc.SetLuaState(L)
+	go statefunc.RunApp()
+	t.Cleanup(app.Stop)
+	return L
+}
+
+// runScript runs the code as a script started from the editor and waits for it to end.
+// answer is called while the script runs, to answer its dialogs.
+func runScript(t *testing.T, L *lua.State, code string, answer func()) {
+	t.Helper()
+	statefunc.StartScript(L, "test", func(string) error {
+		return lua.DoString(L, code)
+	})
+	answer()
+	for deadline := time.Now().Add(5 * time.Second); statefunc.IsScriptAsync(); time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("the script did not end")
+		}
+	}
+}
+
+// pressOnDialog waits for a dialog button or list to get the focus and presses the keys on it
+func pressOnDialog(t *testing.T, keys ...tcell.Key) {
+	t.Helper()
+wait:
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(time.Millisecond) {
+		switch statefunc.App.GetFocus().(type) {
+		case *tview.Button, *tview.List:
+			break wait
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("no dialog was shown")
+		}
+	}
+	for _, key := range keys {
+		statefunc.App.QueueEvent(tcell.NewEventKey(key, 0, tcell.ModNone))
+	}
+}
+
+func TestWalkUpdatesTheRowsItReturnsTrueFor(t *testing.T) {
+	newBatchState(t)
+	err := runBatch(t, `
+		db = DBCreate(":memory:")
+		DBCreateTable(db, "Items", "n::Name;t::Text;l::20|n::Qty;t::Integer", false)
+		items = DBOpenTable(db, "Items")
+		items:Find()
+		for i, name in ipairs({"a", "b", "c", "d"}) do
+			items.Name = name
+			items.Qty = i
+			items:Insert()
+		end
+
+		function bump(t)
+			if t.Name == "b" or t.Name == "d" then
+				t.Qty = t.Qty + 10
+				return true
+			end
+			t.Qty = 0 -- Not stored, the function returns false
+			return false
+		end
+		items:OrderBy("Name")
+		ok, n = items:Walk("bump")
+		assert(ok and n == 2, "Walk updated " .. tostring(n) .. " rows")
+
+		items:Find()
+		local want = {1, 12, 3, 14}
+		for i, qty in ipairs(want) do
+			assert(items.Qty == qty, items.Name .. " has Qty " .. tostring(items.Qty))
+			items:Next()
+		end
+
+		items:SetFilter("Name", "a")
+		ok, n = items:Walk("bump")
+		assert(ok and n == 0, "Walk updated a row outside the filter")
+		DBClose(db)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTransactionCommitsOrRollsBackEverything(t *testing.T) {
+	newBatchState(t)
+	err := runBatch(t, `
+		db = DBCreate(":memory:")
+		DBCreateTable(db, "Items", "n::Name;t::Text;l::20", false)
+		items = DBOpenTable(db, "Items")
+		items:Find()
+
+		function addTwo(t)
+			t.Name = "a"
+			t:Insert()
+			t.Name = "b"
+			t:Insert()
+		end
+		assert(items:Transaction("addTwo"), "the transaction was not committed")
+		assert(items:Count() == 2, "the inserts were not committed")
+
+		function failMidway(t)
+			t.Name = "c"
+			t:Insert()
+			error("stop")
+			t.Name = "d"
+			t:Insert()
+		end
+		assert(not pcall(items.Transaction, items, "failMidway"), "the error was not raised")
+		function refuse(t)
+			t.Name = "e"
+			t:Insert()
+			return false
+		end
+		assert(not items:Transaction("refuse"), "a transaction returning false was committed")
+		assert(items:Count() == 2, "the inserts of the failed transactions were kept")
+
+		function addToDB(db)
+			local other = DBOpenTable(db, "Items")
+			other:Find()
+			other.Name = "f"
+			other:Insert()
+			error("stop")
+		end
+		assert(not pcall(DBTransaction, db, "addToDB"), "the error was not raised")
+		assert(items:Count() == 2, "DBTransaction kept the insert of a failed function")
+		DBClose(db)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadAndWriteTransforms(t *testing.T) {
+	newBatchState(t)
+	err := runBatch(t, `
+		db = DBCreate(":memory:")
+		DBCreateTable(db, "Codes", "n::Code;t::Text;l::20", false)
+		codes = DBOpenTable(db, "Codes")
+		codes:Find()
+		function upper(v) return string.upper(v) end
+		function tagged(v) return "code " .. v end
+		codes:SetWriteTransform("Code", "upper")
+		codes:SetReadTransform("Code", "tagged")
+
+		codes.Code = "ab1"
+		codes:Insert()
+		codes:Find()
+		assert(codes:GetRawField("Code") == "AB1", "stored " .. tostring(codes:GetRawField("Code")))
+		assert(codes.Code == "code AB1", "read " .. tostring(codes.Code))
+
+		codes:SetReadTransform("Code", "")
+		assert(codes.Code == "AB1", "the removed read transform still runs")
+		DBClose(db)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDBDropTableAsksFirst(t *testing.T) {
+	L := newInteractiveState(t)
+	if err := lua.DoString(L, `
+		db = DBCreate(":memory:")
+		DBCreateTable(db, "Items", "n::Name;t::Text;l::20", false)`); err != nil {
+		t.Fatal(err)
+	}
+	drop := `dropped = DBDropTable(db, "Items", true)`
+	check := func(wantDropped bool) {
+		t.Helper()
+		if err := lua.DoString(L, `assert(dropped == not DBTableExists(db, "Items"))`); err != nil {
+			t.Error(err)
+		}
+		L.Global("dropped")
+		if L.ToBoolean(-1) != wantDropped {
+			t.Errorf("DBDropTable returned %v, want %v", L.ToBoolean(-1), wantDropped)
+		}
+		L.Pop(1)
+	}
+
+	runScript(t, L, drop, func() { pressOnDialog(t, tcell.KeyTab, tcell.KeyEnter) }) // Cancel
+	check(false)
+	runScript(t, L, drop, func() { pressOnDialog(t, tcell.KeyEnter) }) // OK
+	check(true)
+}
+
+func TestDBDropTableDropsInBatchMode(t *testing.T) {
+	newBatchState(t)
+	err := runBatch(t, `
+		db = DBCreate(":memory:")
+		DBCreateTable(db, "Items", "n::Name;t::Text;l::20", false)
+		assert(DBDropTable(db, "Items", true), "the table was not dropped")
+		assert(not DBTableExists(db, "Items"), "the table is still there")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTypedGetters(t *testing.T) {
+	newBatchState(t)
+	err := runBatch(t, `
+		db = DBCreate(":memory:")
+		DBCreateTable(db, "Items", "n::Name;t::Text;l::20|n::Qty;t::Integer|n::Price;t::Float|n::Done;t::Boolean|n::Day;t::Date", false)
+		items = DBOpenTable(db, "Items")
+		items:Find()
+		items.Name = "12"
+		items.Qty = 3
+		items.Price = 2.5
+		items.Done = "true"
+		items.Day = Date(2024, 2, 29)
+		items:Insert()
+		items:Find()
+
+		assert(type(items:GetInt("Qty")) == "number" and items:GetInt("Qty") == 3, "GetInt")
+		assert(items:GetFloat("Price") == 2.5, "GetFloat")
+		assert(items:GetBool("Done") == true, "GetBool returned " .. tostring(items:GetBool("Done")))
+		assert(items:GetString("Qty") == "3", "GetString of a number")
+		assert(items:GetDate("Day") == Date(2024, 2, 29), "GetDate returned " .. tostring(items:GetDate("Day")))
+
+		for _, call in ipairs({
+			function() return items:GetInt("Price") end,
+			function() return items:GetBool("Name") end,
+			function() return items:GetDate("Qty") end,
+		}) do
+			local ok, v = pcall(call)
+			assert(not ok, "a getter accepted a field of another type: " .. tostring(v))
+		end
+		DBClose(db)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOnValidateBlocksAnInvalidRecord(t *testing.T) {
+	newBatchState(t)
+	err := runBatch(t, `
+		db = DBCreate(":memory:")
+		DBCreateTable(db, "Periods", "n::Start;t::Integer|n::Finish;t::Integer", false)
+		periods = DBOpenTable(db, "Periods")
+		periods:Find()
+		function check(t)
+			if t.Finish < t.Start then
+				return false, "finish before start"
+			end
+			return true
+		end
+		periods:SetOnValidate("check")
+
+		periods.Start = 5
+		periods.Finish = 1
+		assert(not periods:Insert(), "an invalid record was inserted")
+		assert(periods:Count() == 0, "the invalid record was stored")
+
+		periods.Start = 1
+		periods.Finish = 5
+		assert(periods:Insert(), "a valid record was not inserted")
+		assert(periods:Count() == 1, "the valid record was not stored")
+
+		periods:Find()
+		periods.Finish = 0
+		assert(not periods:Update(), "an update making the record invalid was stored")
+		periods:Find()
+		assert(periods.Finish == 5, "the rejected update changed Finish to " .. tostring(periods.Finish))
+		DBClose(db)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPickListReturnsTheChosenItem(t *testing.T) {
+	L := newInteractiveState(t)
+	pick := `i, v = PickList("Fruit", {"apple", "pear", "plum"})`
+	picked := func() (int, string) {
+		L.Global("i")
+		L.Global("v")
+		defer L.Pop(2)
+		i, _ := L.ToInteger(-2)
+		v, _ := L.ToString(-1)
+		if L.IsNil(-2) {
+			i = 0
+		}
+		return i, v
+	}
+
+	runScript(t, L, pick, func() { pressOnDialog(t, tcell.KeyDown, tcell.KeyEnter) })
+	if i, v := picked(); i != 2 || v != "pear" {
+		t.Errorf("PickList returned %d, %q, want 2, pear", i, v)
+	}
+	runScript(t, L, pick, func() { pressOnDialog(t, tcell.KeyEscape) })
+	if i, v := picked(); i != 0 || v != "" {
+		t.Errorf("the cancelled PickList returned %d, %q, want nil", i, v)
+	}
+}
+
+func TestSplitAndJoin(t *testing.T) {
+	newBatchState(t)
+	err := runBatch(t, `
+		local parts = Split("a,b,,c", ",")
+		assert(#parts == 4 and parts[3] == "" and parts[4] == "c", "Split returned " .. #parts .. " parts")
+		assert(Join(parts, ",") == "a,b,,c", "the round trip changed the text")
+		assert(#Split("", ",") == 0, "an empty text has parts")
+		assert(#Split("äb", "") == 2, "Split with an empty separator does not split into characters")
+		assert(Join({1, 2.5, true}, "-") == "1-2.5-true", "Join returned " .. Join({1, 2.5, true}, "-"))
+		assert(Join({"x", "y"}) == "xy", "Join without a separator")
+		assert(PadLeft(Format("%d", 7), 3, "0") == "007", "PadLeft of Format")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRandomStaysInRangeAndUUIDsDiffer(t *testing.T) {
+	newBatchState(t)
+	err := runBatch(t, `
+		for i = 1, 200 do
+			local n = Random(3, 5)
+			assert(n >= 3 and n <= 5 and n == math.floor(n), "Random returned " .. n)
+			local f = RandomFloat()
+			assert(f >= 0 and f < 1, "RandomFloat returned " .. f)
+		end
+		assert(Random(4, 4) == 4, "Random of a single value")
+		local seen = {}
+		for i = 1, 50 do
+			local id = UUID()
+			-- go-lua has no string patterns, check the layout xxxxxxxx-xxxx-4xxx-[89ab]xxx-xxxxxxxxxxxx by hand
+			assert(#id == 36, "malformed UUID " .. id)
+			for p = 1, 36 do
+				local c = id:sub(p, p)
+				if p == 9 or p == 14 or p == 19 or p == 24 then
+					assert(c == "-", "malformed UUID " .. id)
+				else
+					assert(("0123456789abcdef"):find(c, 1, true), "malformed UUID " .. id)
+				end
+			end
+			assert(id:sub(15, 15) == "4", "UUID is not version 4: " .. id)
+			assert(("89ab"):find(id:sub(20, 20), 1, true), "UUID has the wrong variant: " .. id)
+			assert(not seen[id], "UUID repeated " .. id)
+			seen[id] = true
+		end`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPushFieldValue(t *testing.T) {
+	var wrapped interface{} = int64(42)
+	var wrappedNil interface{}
+	var nilPointer *interface{}
+	tests := []struct {
+		name  string
+		value interface{}
+		typ   lua.Type
+		want  string
+	}{
+		{"nil", nil, lua.TypeNil, "nil"},
+		{"nil pointer", nilPointer, lua.TypeNil, "nil"},
+		{"pointer to nil", &wrappedNil, lua.TypeNil, "nil"},
+		{"pointer to an integer", &wrapped, lua.TypeNumber, "42"},
+		{"int", 7, lua.TypeNumber, "7"},
+		{"int32", int32(-3), lua.TypeNumber, "-3"},
+		{"uint64", uint64(9), lua.TypeNumber, "9"},
+		{"float32", float32(0.5), lua.TypeNumber, "0.5"},
+		{"float64", 2.25, lua.TypeNumber, "2.25"},
+		{"bool", true, lua.TypeBoolean, "true"},
+		{"bytes", []byte("abc"), lua.TypeString, "abc"},
+		{"time", time.Date(2024, 3, 5, 14, 7, 9, 0, time.UTC), lua.TypeString, "2024-03-05 14:07:09"},
+		{"other", struct{ A int }{1}, lua.TypeString, "{1}"},
+	}
+	L := lua.NewState()
+	for _, tt := range tests {
+		pushFieldValue(L, tt.value)
+		if typ := L.TypeOf(-1); typ != tt.typ {
+			t.Errorf("%s: pushed a %s, want a %s", tt.name, lua.TypeNameOf(L, -1), tt.typ)
+		}
+		if got, _ := lua.ToStringMeta(L, -1); got != tt.want {
+			t.Errorf("%s: pushed %q, want %q", tt.name, got, tt.want)
+		}
+		L.SetTop(0)
+	}
+}
+
+func TestFieldAccessReadsNumericColumns(t *testing.T) {
+	newBatchState(t)
+	err := runBatch(t, `
+		db = DBCreate(":memory:")
+		DBCreateTable(db, "Items", "n::Name;t::Text;l::20|n::Qty;t::Integer|n::Price;t::Float", false)
+		items = DBOpenTable(db, "Items")
+		items:Find()
+		items.Name = "a"
+		items.Qty = 3
+		items.Price = 2.5
+		items:Insert()
+		items:Find()
+		assert(type(items.Qty) == "number" and items.Qty == 3, "Qty read as " .. tostring(items.Qty))
+		assert(items.Price == 2.5, "Price read as " .. tostring(items.Price))
+		assert(items.Name == "a", "Name read as " .. tostring(items.Name))
+		DBClose(db)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDBOpenFilteredFindsTheMatchingRows(t *testing.T) {
+	newBatchState(t)
+	err := runBatch(t, `
+		db = DBCreate(":memory:")
+		DBCreateTable(db, "Items", "n::Name;t::Text;l::20|n::Qty;t::Integer", false)
+		items = DBOpenTable(db, "Items")
+		items:Find()
+		for _, r in ipairs({{"a", 1}, {"b", 2}, {"b", 3}}) do
+			items.Name = r[1]
+			items.Qty = r[2]
+			items:Insert()
+		end
+
+		local b = DBOpenFiltered(db, "Items", "Name", "b")
+		assert(#b:ToArray() == 2, "the filter on b found " .. #b:ToArray() .. " rows")
+		assert(b.Name == "b" and b.Qty == 2, "the table is not on the first matching row")
+
+		local both = DBOpenFiltered(db, "Items", {Name = "b", Qty = ">2"})
+		assert(#both:ToArray() == 1 and both.Qty == 3, "the filters of a table are not all applied")
+
+		local none = DBOpenFiltered(db, "Items", "Name", "z")
+		assert(#none:ToArray() == 0, "a filter without matches found rows")
+		DBClose(db)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestToArrayReturnsTheFilteredRows(t *testing.T) {
+	newBatchState(t)
+	err := runBatch(t, `
+		db = DBCreate(":memory:")
+		DBCreateTable(db, "Items", "n::Name;t::Text;l::20|n::Qty;t::Integer", false)
+		items = DBOpenTable(db, "Items")
+		assert(items:ToArray() == nil, "ToArray before Find returned rows")
+		items:Find()
+		for _, r in ipairs({{"a", 1}, {"b", 2}, {"c", 3}}) do
+			items.Name = r[1]
+			items.Qty = r[2]
+			items:Insert()
+		end
+
+		items:SetFilter("Qty", ">1")
+		items:Find()
+		items:Next()
+		local rows = items:ToArray()
+		assert(#rows == 2, "ToArray returned " .. #rows .. " rows")
+		assert(rows[1].Name == "b" and rows[1].Qty == 2 and rows[2].Name == "c" and rows[2].Qty == 3,
+			"ToArray returned the wrong rows")
+		assert(items.Name == "c", "ToArray moved the current row")
+		rows[1].Name = "x"
+		items:Find()
+		assert(items.Name == "b", "changing a returned record changed the table")
+		DBClose(db)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDBBeginCommitAndRollback(t *testing.T) {
+	newBatchState(t)
+	err := runBatch(t, `
+		db = DBCreate(":memory:")
+		DBCreateTable(db, "Items", "n::Name;t::Text;l::20", false)
+		items = DBOpenTable(db, "Items")
+		items:Find()
+
+		assert(DBBegin(db), "DBBegin failed")
+		assert(not DBBegin(db), "a second DBBegin opened a nested transaction")
+		items.Name = "a"
+		items:Insert()
+		assert(DBRollback(db), "DBRollback failed")
+		assert(items:Count() == 0, "the rolled back insert was kept")
+
+		assert(DBBegin(db), "DBBegin failed")
+		items.Name = "b"
+		items:Insert()
+		assert(DBCommit(db), "DBCommit failed")
+		assert(items:Count() == 1, "the committed insert was lost")
+
+		assert(not DBCommit(db), "DBCommit without a transaction succeeded")
+		assert(not DBRollback(db), "DBRollback without a transaction succeeded")
+		DBClose(db)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDBQueryAndDBExecBindTheirArguments(t *testing.T) {
+	newBatchState(t)
+	err := runBatch(t, `
+		db = DBCreate(":memory:")
+		assert(DBExec(db, "CREATE TABLE notes (id INTEGER PRIMARY KEY, body TEXT, n INTEGER)") == 0, "CREATE TABLE")
+		assert(DBExec(db, "INSERT INTO notes (body, n) VALUES (?, ?), (?, ?)", "it's", 1, "b", nil) == 2, "two rows were not inserted")
+		assert(DBExec(db, "UPDATE notes SET n = n + ? WHERE n IS NOT NULL", 2) == 1, "UPDATE changed the wrong rows")
+
+		local rows = DBQuery(db, "SELECT body, n FROM notes WHERE body = ?", "it's")
+		assert(#rows == 1 and rows[1].body == "it's" and rows[1].n == 3, "DBQuery returned the wrong row")
+		rows = DBQuery(db, "SELECT body, n FROM notes ORDER BY id")
+		assert(#rows == 2 and rows[2].n == nil, "NULL was not returned as nil")
+		assert(#DBQuery(db, "SELECT * FROM notes WHERE id > ?", 10) == 0, "a query without matches returned rows")
+
+		assert(DBQuery(db, "SELECT * FROM missing") == nil, "a failed query returned rows")
+		assert(getLastError() ~= "", "the error of the failed query was not kept")
+		assert(DBExec(db, "DELETE FROM missing") == nil, "a failed statement returned a count")
+		DBClose(db)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetColumnsAndGetFieldType(t *testing.T) {
+	newBatchState(t)
+	err := runBatch(t, `
+		db = DBCreate(":memory:")
+		DBCreateTable(db, "Items", "n::Name;t::Text;l::20|n::Qty;t::Integer|n::Day;t::Date", false)
+		items = DBOpenTable(db, "Items")
+		local cols = items:GetColumns()
+		assert(#cols == 4 and cols[1] == "id" and cols[2] == "Name" and cols[3] == "Qty" and cols[4] == "Day",
+			"GetColumns returned " .. table.concat(cols, ","))
+		assert(items:GetFieldType("Qty") == "INTEGER", "Qty is " .. tostring(items:GetFieldType("Qty")))
+		assert(items:GetFieldType("Day") == "DATE", "Day is " .. tostring(items:GetFieldType("Day")))
+		assert(items:GetFieldType("Missing") == nil, "a missing field has a type")
+		DBClose(db)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDateAddAndTimeAddTakeTheirParts(t *testing.T) {
+	newBatchState(t)
+	err := runBatch(t, `
+		assert(DateAdd("15.01.2024", 0, 1, 10) == "25.02.2024", "DateAdd returned " .. tostring(DateAdd("15.01.2024", 0, 1, 10)))
+		assert(DateAdd("01.03.2024", 0, 0, -1) == "29.02.2024", "DateAdd back over a leap day")
+		assert(DateAdd("29.02.2024", 1, 0, 0) == "01.03.2025", "DateAdd of a year to a leap day")
+		assert(TimeAdd("10:00:00", 0, 30, 0) == "10:30:00", "TimeAdd returned " .. tostring(TimeAdd("10:00:00", 0, 30, 0)))
+		assert(TimeAdd("23:30:00", 1, 0, 15) == "00:30:15", "TimeAdd past midnight")
+		assert(not pcall(DateAdd, "15.01.2024", 1), "DateAdd without all parts was accepted")
+		local ok, msg = pcall(TimeAdd, "10:00:00", 1)
+		assert(not ok, "TimeAdd without all parts was accepted")
+		assert(string.find(msg, "TimeAdd", 1, true), "TimeAdd without all parts failed with " .. msg)
+		ok, msg = pcall(TimeAdd, "10:00:00", 1, "x", 0)
+		assert(not ok and string.find(msg, "minute is not an integer", 1, true), "TimeAdd with a text minute failed with " .. tostring(msg))`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDBCreateViewAndDBDropViewReturnTrue(t *testing.T) {
+	newBatchState(t)
+	err := runBatch(t, `
+		db = DBCreate(":memory:")
+		DBCreateTable(db, "Items", "n::Name;t::Text;l::20", false)
+		local created = DBCreateView(db, "ItemNames", "SELECT Name FROM Items")
+		assert(created == true, "DBCreateView returned " .. tostring(created))
+		assert(DBColumnExists(db, "ItemNames", "Name"), "the view was not created")
+		local dropped = DBDropView(db, "ItemNames")
+		assert(dropped == true, "DBDropView returned " .. tostring(dropped))
+		assert(not DBColumnExists(db, "ItemNames", "Name"), "the view was not dropped")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,184 @@
+package luafunc
+
+import (
+	"gotulua/errorhandlefunc"
+	"gotulua/gormfunc"
+	"gotulua/i18nfunc"
+	"sort"
+
+	"github.com/Shopify/go-lua"
+)
+
+// recordFieldsKey is the metatable field of a pushed record with the names of its fields
+const recordFieldsKey = "__fields"
+
+// sortedRecordKeys returns the field names of the record in alphabetical order
+func sortedRecordKeys(rec gormfunc.Record) []string {
+	keys := make([]string, 0, len(rec))
+	for k := range rec {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// recordKeys returns the string keys of the Lua table at the index in alphabetical order.
+// For a record pushed by PushRecWithDotNotation the fields with nil values are included.
+func recordKeys(L *lua.State, index int) []string {
+	index = L.AbsIndex(index)
+	seen := map[string]bool{}
+	var keys []string
+	add := func(k string) {
+		if !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	if L.MetaTable(index) {
+		L.Field(-1, recordFieldsKey)
+		if L.IsTable(-1) {
+			for i := 1; ; i++ {
+				L.RawGetInt(-1, i)
+				k, ok := L.ToString(-1)
+				L.Pop(1)
+				if !ok {
+					break
+				}
+				add(k)
+			}
+		}
+		L.Pop(2) // fields and metatable
+	}
+	L.PushNil()
+	for L.Next(index) {
+		if L.TypeOf(-2) == lua.TypeString {
+			k, _ := L.ToString(-2)
+			add(k)
+		}
+		L.Pop(1) // value, keep the key for Next
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// recordPairs is the __pairs metamethod of a pushed record.
+// It visits every field in alphabetical order, also the fields set to nil.
+func recordPairs(L *lua.State) int {
+	keys := recordKeys(L, 1)
+	i := 0
+	L.PushGoFunction(func(L *lua.State) int {
+		if i >= len(keys) {
+			L.PushNil()
+			return 1
+		}
+		L.PushString(keys[i])
+		L.PushString(keys[i])
+		L.RawGet(1)
+		i++
+		return 2
+	})
+	L.PushValue(1)
+	L.PushNil()
+	return 3
+}
+
+// recordKeysLua returns a Lua array with the field names of a record in alphabetical order
+func recordKeysLua(L *lua.State) int {
+	if L.Top() < 1 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "RecordKeys",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	if !L.IsTable(1) {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_table", nil), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	L.NewTable()
+	for i, k := range recordKeys(L, 1) {
+		L.PushString(k)
+		L.RawSetInt(-2, i+1)
+	}
+	return 1
+}
+
+// getRecord returns the current row of the table as a record with its fields in user format,
+// or nil if there is no current row. Changing the record does not change the table.
+func getRecord(L *lua.State) int {
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	t := wrapper.Table
+	if t.GetCurrentRecord() == nil {
+		L.PushNil()
+		return 1
+	}
+	rec := gormfunc.Record{}
+	for _, col := range t.Columns {
+		rec[col] = t.GetField(col, "")
+	}
+	PushRecWithDotNotation(L, rec)
+	return 1
+}
+
+// getColumns returns a Lua array with the column names of the table in their order
+func getColumns(L *lua.State) int {
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	L.NewTable()
+	for i, col := range wrapper.Table.Columns {
+		L.PushString(col)
+		L.RawSetInt(-2, i+1)
+	}
+	return 1
+}
+
+// getFieldType returns the logical type of the field, like DATE or INTEGER, or nil if the table has no such field
+func getFieldType(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "GetFieldType",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	field, ok := L.ToString(2) // Get the field name from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "field name",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	if tp := wrapper.Table.GetFieldType(field); tp != "" {
+		L.PushString(tp)
+	} else {
+		L.PushNil()
+	}
+	return 1
+}
+
+// toArray returns the rows loaded by the last Find as a Lua array of records,
+// or nil if Find was not called
+func toArray(L *lua.State) int {
+	wrapper := checkTable(L)
+	if wrapper == nil {
+		return 0
+	}
+	records, ok := wrapper.Table.Records()
+	if !ok {
+		L.PushNil()
+		return 1
+	}
+	L.CreateTable(len(records), 0)
+	for i, rec := range records {
+		PushRecWithDotNotation(L, rec)
+		L.RawSetInt(-2, i+1)
+	}
+	return 1
+}
@@ -0,0 +1,32 @@
+package luafunc
+
+import (
+	"gotulua/gormfunc"
+	"testing"
+
+	"github.com/Shopify/go-lua"
+)
+
+func TestPairsAndRecordKeysVisitEveryField(t *testing.T) {
+	L := newBatchState(t)
+	PushRecWithDotNotation(L, gormfunc.Record{"Name": "a", "Qty": 3, "Note": nil})
+	L.SetGlobal("rec")
+	err := lua.DoString(L, `
+		local seen, count = {}, 0
+		for k, v in pairs(rec) do
+			count = count + 1
+			seen[k] = v == nil and "nil" or tostring(v)
+		end
+		assert(count == 3, "pairs visited " .. count .. " fields")
+		assert(seen.Name == "a" and seen.Qty == "3" and seen.Note == "nil", "pairs returned the wrong values")
+
+		local keys = RecordKeys(rec)
+		assert(#keys == 3 and keys[1] == "Name" and keys[2] == "Note" and keys[3] == "Qty",
+			"RecordKeys returned " .. table.concat(keys, ","))
+
+		rec.Extra = 1
+		assert(#RecordKeys(rec) == 4, "a field set by the script is not listed")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,9 +1,12 @@
 package luafunc
 
 import (
+	"errors"
 	"gotulua/errorhandlefunc"
+	"gotulua/i18nfunc"
 	"gotulua/statefunc"
 	"gotulua/uifunc"
+	"strings"
 
 	"github.com/Shopify/go-lua"
 )
@@ -24,6 +27,8 @@ func RunLuaScript(script string) error {
 		return nil
 	}
 	uifunc.ClearWidgets() // Clear the widgets before running the script
+	startScriptRun()
+	defer endScriptRun(statefunc.L)
 	statefunc.ClearErrorRun()
 	err := lua.DoFile(statefunc.L, script)
 	if err != nil {
@@ -36,3 +41,65 @@ func RunLuaScript(script string) error {
 	}
 	return nil
 }
+
+// RunLuaString runs a piece of Lua code, such as the text selected in the editor.
+// Errors refer to the lines of the code starting from 1.
+func RunLuaString(code string) error {
+	defer func() {
+		if r := recover(); r != nil {
+			f := statefunc.PopVisual()
+			if f != nil {
+				statefunc.RunFlexLevel0.Clear()
+				statefunc.App.SetRoot(f, true)
+				statefunc.App.ForceDraw()
+			}
+		}
+	}()
+	if strings.TrimSpace(code) == "" {
+		errorhandlefunc.ThrowError("Lua script is empty", errorhandlefunc.ErrorTypeScript, false)
+		return nil
+	}
+	uifunc.ClearWidgets() // Clear the widgets before running the code
+	startScriptRun()
+	defer endScriptRun(statefunc.L)
+	statefunc.ClearErrorRun()
+	err := lua.LoadBuffer(statefunc.L, code, "="+statefunc.SelectionChunkName, "")
+	if err == nil {
+		err = statefunc.L.ProtectedCall(0, lua.MultipleReturns, 0)
+	} else if msg, ok := statefunc.L.ToString(-1); ok {
+		// The details of a syntax error are left on the stack
+		statefunc.L.Pop(1)
+		err = errors.New(msg)
+	}
+	if err != nil {
+		if statefunc.IsErrorRun() {
+			statefunc.ClearErrorRun()
+			return err
+		}
+		errorhandlefunc.ThrowError(err.Error(), errorhandlefunc.ErrorTypeScript, false)
+		return err
+	}
+	return nil
+}
+
+// RunLuaScriptBatch runs the script without the UI and returns the first error raised by it.
+func RunLuaScriptBatch(script string) error {
+	if script == "" {
+		return errors.New(i18nfunc.T("error.batch_no_script", nil))
+	}
+	statefunc.ClearErrorRun()
+	idleMu.Lock()
+	idleFunctions = nil
+	idleMu.Unlock()
+	err := lua.DoFile(statefunc.L, script)
+	if err == lua.FileError || err == lua.SyntaxError {
+		// The details of a file or syntax error are left on the stack
+		if msg, ok := statefunc.L.ToString(-1); ok {
+			err = errors.New(msg)
+		}
+	}
+	if err == nil {
+		err = runIdleFunctions(statefunc.L)
+	}
+	return err
+}
@@ -0,0 +1,74 @@
+package luafunc
+
+import (
+	"gotulua/errorhandlefunc"
+	"gotulua/statefunc"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Shopify/go-lua"
+)
+
+func TestRunLuaScriptBatch(t *testing.T) {
+	L := newBatchState(t)
+	if err := runBatch(t, `done = 1 + 1`); err != nil {
+		t.Fatalf("the script failed: %v", err)
+	}
+	L.Global("done")
+	if n, _ := L.ToInteger(-1); n != 2 {
+		t.Error("the script did not run to its end")
+	}
+	L.Pop(1)
+
+	tests := []struct {
+		name string
+		code string
+		want string // Part of the error
+	}{
+		{"error", `error("stop here")`, "stop here"},
+		{"syntax", `x = = 1`, "test.lua"},
+		{"dialog", `Confirm("Go on?")`, "Confirm is not available in batch mode"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runBatch(t, tt.code)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %v, want one with %q", err, tt.want)
+			}
+		})
+	}
+
+	if err := RunLuaScriptBatch(filepath.Join(t.TempDir(), "missing.lua")); err == nil {
+		t.Error("a missing script ran without an error")
+	}
+	if err := RunLuaScriptBatch(""); err == nil {
+		t.Error("no script ran without an error")
+	}
+}
+
+func TestRunLuaStringRunsTheSelection(t *testing.T) {
+	L := newInteractiveState(t)
+	var shown []string
+	errorhandlefunc.SetErrorSink(func(msg string) { shown = append(shown, msg) })
+	t.Cleanup(func() { errorhandlefunc.SetErrorSink(nil) })
+	var printed []string
+	L.Register("print", func(L *lua.State) int {
+		s, _ := L.ToString(1)
+		printed = append(printed, s)
+		return 0
+	})
+
+	if err := RunLuaString(`print("selected")`); err != nil || len(printed) != 1 || printed[0] != "selected" {
+		t.Errorf("the selection printed %q, error %v", printed, err)
+	}
+
+	// Lines are counted from the start of the selection
+	err := RunLuaString("x = 1\ny = = 2")
+	if err == nil || !strings.Contains(err.Error(), statefunc.SelectionChunkName+":2:") {
+		t.Errorf("error %v, want one on line 2 of the selection", err)
+	}
+	if len(shown) != 1 || shown[0] != err.Error() {
+		t.Errorf("the errors shown are %q, want the syntax error", shown)
+	}
+}
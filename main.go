@@ -3,14 +3,22 @@ package main
 import (
 	"flag"
 	"fmt"
+	"gotulua/editorfunc"
 	"gotulua/errorhandlefunc"
+	"gotulua/gormfunc"
 	"gotulua/helpsysfunc"
 	"gotulua/i18nfunc"
+	"gotulua/keymapfunc"
 	"gotulua/luafunc"
 	"gotulua/pagesfunc"
 	"gotulua/statefunc"
 	"gotulua/uifunc"
 	"gotulua/view"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -27,7 +35,8 @@ func main() {
 		switch widget.(type) {
 		case *tview.InputField:
 			// Let the InputField handle Esc
-			if widget.(*tview.InputField).GetTitle() == "BROWSEINPUT" || widget.(*tview.InputField).GetTitle() == "BROWSEFILTER" {
+			if widget.(*tview.InputField).GetTitle() == "BROWSEINPUT" || widget.(*tview.InputField).GetTitle() == "BROWSEFILTER" ||
+				widget.(*tview.InputField).GetTitle() == "BROWSEEXPORT" {
 				return event
 			}
 		case *tview.Modal:
@@ -55,6 +64,14 @@ func main() {
 				}
 			}
 		}
+		if keymapfunc.Matches(keymapfunc.AppToggleRun, event) {
+			statefunc.ToggleRunVisual()
+			return nil
+		}
+		if event.Key() == tcell.KeyEscape && statefunc.DialogCount() > 0 {
+			// Open dialogs close themselves and show the dialog or view under them
+			return event
+		}
 		if event.Key() == tcell.KeyEscape {
 			f := statefunc.PopVisual()
 			if f != nil {
@@ -68,11 +85,27 @@ func main() {
 	})
 	var err error
 	doEdit := flag.Bool("e", false, "Edit mode")
+	doBatch := flag.Bool("batch", false, "Run the script without UI and exit")
+	doConsole := flag.Bool("console", false, "Open the Lua console")
+	autoSave := flag.Int("autosave", 0, "Save the edited file after this many idle seconds, 0 disables")
+	lineEnding := flag.String("eol", editorfunc.LineEndingAuto, "Line ending of saved files: lf, crlf or auto for the native one")
+	flag.BoolVar(&editorfunc.ShowOverview, "overview", true, "Show the document overview column on the right of the editor")
+	flag.BoolVar(&editorfunc.ShowLineNumbers, "linenumbers", true, "Show line numbers on the left of the editor")
+	flag.BoolVar(&pagesfunc.ConfirmOverwrite, "confirm-overwrite", true, "Ask before Save As replaces an existing file")
+	keymapFile := flag.String("keymap", "", "Key bindings file, <config dir>/gotulua/keys.conf by default")
 	flag.Parse()
+	editorfunc.AutoSaveInterval = time.Duration(*autoSave) * time.Second
+	if editorfunc.DefaultLineEnding, err = editorfunc.ParseLineEnding(*lineEnding); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	loadKeymap(*keymapFile)
 	args := flag.Args()
 	var srcFile string
+	var srcLine, srcCol int
 	if len(args) > 0 {
-		srcFile = "./" + args[0]
+		srcFile, srcLine, srcCol = parseFileArg(args[0])
+		srcFile = "./" + srcFile
 	}
 	mainFlex := tview.NewFlex()
 	runFlexLevel0 := tview.NewFlex()
@@ -84,21 +117,93 @@ func main() {
 	statefunc.SetLuaState(L)
 	luafunc.SetupRequireHandler(L, []string{"."})
 	statefunc.RunLuaScriptFunc = luafunc.RunLuaScript
+	statefunc.RunLuaStringFunc = luafunc.RunLuaString
 	statefunc.ShowHelpFunc = helpsysfunc.ShowHelp
+	statefunc.ShowConsoleFunc = luafunc.ShowConsole
 	errorhandlefunc.SetLuaState(L)
+	if *doBatch {
+		statefunc.SetBatchMode(true)
+		err = luafunc.RunLuaScriptBatch(srcFile)
+		shutdown()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
 	App.EnableMouse(true)
 	App.SetRoot(pages, true)
-	if *doEdit || srcFile == "" {
-		pagesfunc.ShowEditor(srcFile, 0, "")
+	if *doConsole {
+		luafunc.ShowConsole()
+	} else if *doEdit || srcFile == "" {
+		pagesfunc.ShowEditorAt(srcFile, max(srcLine-1, 0), max(srcCol-1, 0), "")
 		statefunc.App.SetFocus(statefunc.MainFlex)
 	} else {
 		luafunc.RunLuaScript(srcFile)
 	}
 
-	err = App.Run()
+	err = statefunc.RunApp()
+	shutdown()
 	if err != nil {
 		fmt.Println("Error running Application:", err)
 	} else {
 		fmt.Println("Application stopped successfully")
 	}
 }
+
+// shutdown closes the databases the script left open, so their changes are flushed
+// and their files unlocked. Open transactions are rolled back.
+func shutdown() {
+	if err := gormfunc.CloseAllDBs(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// loadKeymap loads the key bindings file. The default file is optional,
+// a file given with -keymap must exist.
+func loadKeymap(path string) {
+	if path == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return
+		}
+		path = filepath.Join(dir, "gotulua", "keys.conf")
+		if _, err := os.Stat(path); err != nil {
+			return
+		}
+	}
+	if err := keymapfunc.Load(path); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// parseFileArg splits a file argument like "script.lua:42" or "script.lua:42:7"
+// into the file, the line and the column, both counted from 1. They are 0 when
+// not given. Suffixes that are not positive numbers are left in the file name,
+// and a file whose name really ends with the suffix is taken as it is.
+func parseFileArg(arg string) (file string, line, col int) {
+	if _, err := os.Stat(arg); err == nil {
+		return arg, 0, 0
+	}
+	file = arg
+	var nums []int
+	for len(nums) < 2 {
+		i := strings.LastIndex(file, ":")
+		if i <= 0 {
+			break
+		}
+		n, err := strconv.Atoi(file[i+1:])
+		if err != nil || n <= 0 {
+			break
+		}
+		nums = append([]int{n}, nums...)
+		file = file[:i]
+	}
+	switch len(nums) {
+	case 1:
+		line = nums[0]
+	case 2:
+		line, col = nums[0], nums[1]
+	}
+	return file, line, col
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseFileArg(t *testing.T) {
+	tests := []struct {
+		arg       string
+		file      string
+		line, col int
+	}{
+		{"script.lua", "script.lua", 0, 0},
+		{"script.lua:42", "script.lua", 42, 0},
+		{"script.lua:42:7", "script.lua", 42, 7},
+		{"dir/script.lua:1:2:3", "dir/script.lua:1", 2, 3}, // Only line and column are taken
+		{"script.lua:x", "script.lua:x", 0, 0},
+		{"script.lua:0", "script.lua:0", 0, 0},
+		{"script.lua:-3", "script.lua:-3", 0, 0},
+		{":5", ":5", 0, 0},
+	}
+	for _, tt := range tests {
+		file, line, col := parseFileArg(tt.arg)
+		if file != tt.file || line != tt.line || col != tt.col {
+			t.Errorf("parseFileArg(%q) = %q, %d, %d; want %q, %d, %d", tt.arg, file, line, col, tt.file, tt.line, tt.col)
+		}
+	}
+}
+
+func TestParseFileArgKeepsAnExistingName(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes:12")
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if file, line, _ := parseFileArg(path); file != path || line != 0 {
+		t.Errorf("parseFileArg of the existing file %q = %q, line %d", path, file, line)
+	}
+}
@@ -0,0 +1,113 @@
+package numfunc
+
+import (
+	"errors"
+	"gotulua/i18nfunc"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Number format used to show Integer and Real fields in browses.
+// Decimals < 0 means that numbers are shown as they are stored.
+var Decimals int = -1
+var ThousandsSeparator string = ""
+var DecimalSeparator string = "."
+
+// SetNumberFormat sets the format used to show numbers in browses
+func SetNumberFormat(decimals int, thousandsSep, decimalSep string) error {
+	if decimalSep == "" {
+		decimalSep = "."
+	}
+	if thousandsSep == decimalSep {
+		return errors.New(i18nfunc.T("error.number_format_separators", nil))
+	}
+	Decimals = decimals
+	ThousandsSeparator = thousandsSep
+	DecimalSeparator = decimalSep
+	return nil
+}
+
+// IsNumberFormatSet reports whether a number format was set with SetNumberFormat
+func IsNumberFormatSet() bool {
+	return Decimals >= 0
+}
+
+// FormatNumber formats the value with the given number of decimals, grouping the
+// integer part by thousands. For example FormatNumber(1234567.891, 2, " ", ",")
+// returns "1 234 567,89".
+func FormatNumber(value float64, decimals int, thousandsSep, decimalSep string) string {
+	if decimals < 0 {
+		decimals = 0
+	}
+	if decimalSep == "" {
+		decimalSep = "."
+	}
+	s := strconv.FormatFloat(math.Abs(value), 'f', decimals, 64)
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if thousandsSep != "" && len(intPart) > 3 {
+		var sb strings.Builder
+		lead := len(intPart) % 3
+		if lead > 0 {
+			sb.WriteString(intPart[:lead])
+		}
+		for i := lead; i < len(intPart); i += 3 {
+			if sb.Len() > 0 {
+				sb.WriteString(thousandsSep)
+			}
+			sb.WriteString(intPart[i : i+3])
+		}
+		intPart = sb.String()
+	}
+	if fracPart != "" {
+		intPart += decimalSep + fracPart
+	}
+	if value < 0 && strings.Trim(s, "0.") != "" {
+		intPart = "-" + intPart
+	}
+	return intPart
+}
+
+// ParseNumber converts a string produced by FormatNumber back to a number
+func ParseNumber(s, thousandsSep, decimalSep string) (float64, error) {
+	if decimalSep == "" {
+		decimalSep = "."
+	}
+	str := strings.TrimSpace(s)
+	if thousandsSep != "" {
+		str = strings.ReplaceAll(str, thousandsSep, "")
+	}
+	if decimalSep != "." {
+		str = strings.ReplaceAll(str, decimalSep, ".")
+	}
+	v, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return 0, errors.New(i18nfunc.T("error.number_parse_failed", map[string]interface{}{
+			"Value": s,
+		}))
+	}
+	return v, nil
+}
+
+// FormatNumberToUser formats the value with the format set by SetNumberFormat.
+// Integers are shown without decimals.
+func FormatNumberToUser(value float64, isInteger bool) string {
+	decimals := Decimals
+	if isInteger {
+		decimals = 0
+	}
+	return FormatNumber(value, decimals, ThousandsSeparator, DecimalSeparator)
+}
+
+// ParseNumberFromUser parses a number shown with the format set by SetNumberFormat
+// and returns it in the form it is stored in the database.
+func ParseNumberFromUser(s string) (string, error) {
+	if strings.TrimSpace(s) == "" {
+		return s, nil
+	}
+	v, err := ParseNumber(s, ThousandsSeparator, DecimalSeparator)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64), nil
+}
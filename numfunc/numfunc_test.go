@@ -0,0 +1,77 @@
+package numfunc
+
+import "testing"
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		value        float64
+		decimals     int
+		thousandsSep string
+		decimalSep   string
+		want         string
+	}{
+		{1234567.891, 2, " ", ",", "1 234 567,89"},
+		{1234567.891, 2, ",", ".", "1,234,567.89"},
+		{1234567.891, 0, ".", ",", "1.234.568"},
+		{-1234.5, 1, " ", ",", "-1 234,5"},
+		{-0.001, 2, " ", ",", "0,00"}, // Rounded to zero, without a sign
+		{999, 2, " ", "", "999.00"},
+		{1000, -1, "'", ".", "1'000"},
+		{123456, 0, "", ".", "123456"},
+	}
+	for _, tt := range tests {
+		if got := FormatNumber(tt.value, tt.decimals, tt.thousandsSep, tt.decimalSep); got != tt.want {
+			t.Errorf("FormatNumber(%v, %d, %q, %q) = %q, want %q", tt.value, tt.decimals, tt.thousandsSep, tt.decimalSep, got, tt.want)
+		}
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		s            string
+		thousandsSep string
+		decimalSep   string
+		want         float64
+	}{
+		{"1 234 567,89", " ", ",", 1234567.89},
+		{"1,234,567.89", ",", ".", 1234567.89},
+		{"-1.234,5", ".", ",", -1234.5},
+		{" 42 ", "", "", 42},
+	}
+	for _, tt := range tests {
+		got, err := ParseNumber(tt.s, tt.thousandsSep, tt.decimalSep)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseNumber(%q, %q, %q) = %v, %v, want %v", tt.s, tt.thousandsSep, tt.decimalSep, got, err, tt.want)
+		}
+	}
+	if _, err := ParseNumber("12a", " ", ","); err == nil {
+		t.Error("ParseNumber accepted 12a")
+	}
+
+	// What FormatNumber writes ParseNumber reads back
+	for _, v := range []float64{0, 1234567.25, -98765.5} {
+		s := FormatNumber(v, 2, " ", ",")
+		if got, err := ParseNumber(s, " ", ","); err != nil || got != v {
+			t.Errorf("%v formatted as %q parses back as %v, %v", v, s, got, err)
+		}
+	}
+}
+
+func TestSetNumberFormat(t *testing.T) {
+	t.Cleanup(func() { Decimals, ThousandsSeparator, DecimalSeparator = -1, "", "." })
+	if err := SetNumberFormat(2, ",", ","); err == nil {
+		t.Error("the same thousands and decimal separator was accepted")
+	}
+	if err := SetNumberFormat(2, " ", ","); err != nil {
+		t.Fatal(err)
+	}
+	if got := FormatNumberToUser(1234.5, false); got != "1 234,50" {
+		t.Errorf("a real is shown as %q", got)
+	}
+	if got := FormatNumberToUser(1234, true); got != "1 234" {
+		t.Errorf("an integer is shown as %q", got)
+	}
+	if got, err := ParseNumberFromUser("1 234,50"); err != nil || got != "1234.5" {
+		t.Errorf("the user number is stored as %q, %v", got, err)
+	}
+}
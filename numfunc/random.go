@@ -0,0 +1,14 @@
+package numfunc
+
+import "math/rand/v2"
+
+// Random returns a random integer from min to max, both included. The generator is
+// seeded from the OS when the program starts, so every run gets other numbers.
+func Random(min, max int) int {
+	return min + rand.IntN(max-min+1)
+}
+
+// RandomFloat returns a random number from 0 up to, but not including, 1
+func RandomFloat() float64 {
+	return rand.Float64()
+}
@@ -13,13 +13,7 @@ func ErrorMessage(text string) {
 	dialog.SetTextColor(tview.Styles.PrimaryTextColor)
 	dialog.SetBackgroundColor(tview.Styles.ContrastBackgroundColor)
 	dialog.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
-		statefunc.RunFlexLevelDialog.Clear()
-		statefunc.ShowRunVisual()
+		statefunc.PopDialog()
 	})
-	statefunc.PushVisual(statefunc.RunFlexLevel0)
-	statefunc.RunFlexLevelDialog.Clear()
-	statefunc.RunFlexLevelDialog.AddItem(dialog, 1, 0, false)
-	statefunc.App.SetRoot(statefunc.RunFlexLevelDialog, true)
-	statefunc.App.SetFocus(dialog)
-	statefunc.App.ForceDraw() // Ensure the dialog is drawn immediately
+	statefunc.PushDialog(tview.NewFlex().AddItem(dialog, 1, 0, false), dialog, statefunc.RunFlexLevel0)
 }
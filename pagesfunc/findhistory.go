@@ -0,0 +1,73 @@
+package pagesfunc
+
+import "strings"
+
+// maxFindHistory is the number of search terms kept in the find history
+const maxFindHistory = 20
+
+// findHistory keeps the recent search terms of the session, most recent first.
+type findHistory struct {
+	terms []string
+	pos   int // Position of the term shown in the find box, -1 when none
+}
+
+// findTerms is shared by all editors of the session
+var findTerms = &findHistory{pos: -1}
+
+// Add puts the term at the top of the history, removing an older copy of it.
+func (h *findHistory) Add(term string) {
+	h.pos = -1
+	if strings.TrimSpace(term) == "" {
+		return
+	}
+	for i, t := range h.terms {
+		if t == term {
+			h.terms = append(h.terms[:i], h.terms[i+1:]...)
+			break
+		}
+	}
+	h.terms = append([]string{term}, h.terms...)
+	if len(h.terms) > maxFindHistory {
+		h.terms = h.terms[:maxFindHistory]
+	}
+}
+
+// Get returns the term by its index, 0 being the most recent one.
+func (h *findHistory) Get(index int) (string, bool) {
+	if index < 0 || index >= len(h.terms) {
+		return "", false
+	}
+	return h.terms[index], true
+}
+
+// Len returns the number of terms in the history
+func (h *findHistory) Len() int {
+	return len(h.terms)
+}
+
+// Older moves to the previous (older) term and returns it.
+func (h *findHistory) Older() (string, bool) {
+	if h.pos+1 >= len(h.terms) {
+		return "", false
+	}
+	h.pos++
+	return h.terms[h.pos], true
+}
+
+// Newer moves to the next (more recent) term and returns it.
+// Moving past the most recent term returns an empty string.
+func (h *findHistory) Newer() (string, bool) {
+	if h.pos < 0 {
+		return "", false
+	}
+	h.pos--
+	if h.pos < 0 {
+		return "", true
+	}
+	return h.terms[h.pos], true
+}
+
+// Reset makes the next Older call return the most recent term
+func (h *findHistory) Reset() {
+	h.pos = -1
+}
@@ -0,0 +1,81 @@
+package pagesfunc
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// historyTerms returns the terms of the history by index, most recent first
+func historyTerms(h *findHistory) []string {
+	var terms []string
+	for i := 0; i < h.Len(); i++ {
+		term, _ := h.Get(i)
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+func TestFindHistoryAdd(t *testing.T) {
+	h := &findHistory{pos: -1}
+	for _, term := range []string{"alpha", "beta", " ", "gamma", "alpha"} {
+		h.Add(term)
+	}
+	if got, want := historyTerms(h), []string{"alpha", "gamma", "beta"}; !slices.Equal(got, want) {
+		t.Errorf("terms %q, want %q", got, want)
+	}
+	if _, ok := h.Get(3); ok {
+		t.Error("Get returned a term past the last one")
+	}
+	if _, ok := h.Get(-1); ok {
+		t.Error("Get returned a term before the first one")
+	}
+
+	for i := range maxFindHistory + 5 {
+		h.Add(fmt.Sprint("term ", i))
+	}
+	if h.Len() != maxFindHistory {
+		t.Errorf("the history keeps %d terms, want %d", h.Len(), maxFindHistory)
+	}
+	if term, _ := h.Get(0); term != fmt.Sprint("term ", maxFindHistory+4) {
+		t.Errorf("the most recent term is %q", term)
+	}
+}
+
+func TestFindHistoryOlderAndNewer(t *testing.T) {
+	h := &findHistory{pos: -1}
+	if _, ok := h.Older(); ok {
+		t.Error("Older returned a term of an empty history")
+	}
+	h.Add("one")
+	h.Add("two")
+
+	steps := []struct {
+		step   func() (string, bool)
+		want   string
+		wantOK bool
+	}{
+		{h.Older, "two", true},
+		{h.Older, "one", true},
+		{h.Older, "", false}, // Stays on the oldest term
+		{h.Newer, "two", true},
+		{h.Newer, "", true}, // Past the most recent term the find box is empty
+		{h.Newer, "", false},
+		{h.Older, "two", true},
+	}
+	for i, s := range steps {
+		if got, ok := s.step(); got != s.want || ok != s.wantOK {
+			t.Errorf("step %d: got %q, %v, want %q, %v", i, got, ok, s.want, s.wantOK)
+		}
+	}
+
+	// Adding a term starts again from the most recent one
+	h.Add("three")
+	if got, _ := h.Older(); got != "three" {
+		t.Errorf("Older after Add returned %q, want three", got)
+	}
+	h.Reset()
+	if got, _ := h.Older(); got != "three" {
+		t.Errorf("Older after Reset returned %q, want three", got)
+	}
+}
@@ -3,6 +3,7 @@ package pagesfunc
 import (
 	"fmt"
 	"gotulua/i18nfunc"
+	"gotulua/keymapfunc"
 	"gotulua/statefunc"
 	"os"
 	"path/filepath"
@@ -63,20 +64,20 @@ func (m *MainMenu) updateMenuBar() {
 
 // inputHandler handles keyboard navigation for the menu bar.
 func (m *MainMenu) inputHandler(event *tcell.EventKey) *tcell.EventKey {
-	switch event.Key() {
-	case tcell.KeyLeft:
+	switch key := event.Key(); {
+	case key == tcell.KeyLeft:
 		if m.selected > 0 {
 			m.selected--
 			m.updateMenuBar()
 		}
 		return nil
-	case tcell.KeyRight:
+	case key == tcell.KeyRight:
 		if m.selected < len(m.menus)-1 {
 			m.selected++
 			m.updateMenuBar()
 		}
 		return nil
-	case tcell.KeyEnter:
+	case key == tcell.KeyEnter:
 		if m.findTextArea != nil {
 			ft := m.findTextArea.GetText()
 			m.findFlex.RemoveItem(m.findTextArea)
@@ -86,6 +87,7 @@ func (m *MainMenu) inputHandler(event *tcell.EventKey) *tcell.EventKey {
 			m.findTextView.SetText(ft)
 			m.findFlex.AddItem(m.findTextView, 0, 1, true)
 			statefunc.App.SetFocus(statefunc.EditorFlex)
+			findTerms.Add(ft)
 			m.findFunc(ft, false)
 			return nil
 		}
@@ -93,7 +95,7 @@ func (m *MainMenu) inputHandler(event *tcell.EventKey) *tcell.EventKey {
 			m.callbacks[m.selected]()
 		}
 		return nil
-	case tcell.KeyF3, tcell.KeyF4:
+	case keymapfunc.Matches(keymapfunc.EditorFindNext, event):
 		if m.findTextArea != nil || m.findTextView != nil {
 			ft := ""
 			if m.findTextArea != nil {
@@ -106,6 +108,7 @@ func (m *MainMenu) inputHandler(event *tcell.EventKey) *tcell.EventKey {
 					m.findTextView.SetText(ft)
 					m.findFlex.AddItem(m.findTextView, 0, 1, true)
 				}
+				findTerms.Add(ft)
 			} else if m.findTextView != nil {
 				ft = m.findTextView.GetText(true)
 			}
@@ -113,21 +116,24 @@ func (m *MainMenu) inputHandler(event *tcell.EventKey) *tcell.EventKey {
 			m.findFunc(ft, true)
 			return nil
 		}
-	case tcell.KeyEscape:
+	case key == tcell.KeyEscape:
 		if m.findTextArea != nil {
 			m.findFlex.RemoveItem(m.findTextArea)
 			m.findTextArea = nil
+			m.findFunc("", false) // Cancelling the search clears the highlights
 		}
 		statefunc.App.SetRoot(statefunc.MainFlex, true)
 		return nil
-	case tcell.KeyF5:
+	case keymapfunc.Matches(keymapfunc.EditorRun, event):
 		statefunc.PushVisual(statefunc.MainFlex)
 		statefunc.App.SetRoot(statefunc.RunFlexLevel0, true)
 		statefunc.StartScript(statefunc.L, Editor.GetFileName(), statefunc.RunLuaScriptFunc)
 		return nil
-	case tcell.KeyF1, tcell.KeyF2:
+	case keymapfunc.Matches(keymapfunc.EditorSaveAndRun, event):
+		Editor.SaveAndRun()
+		return nil
+	case keymapfunc.Matches(keymapfunc.EditorHelp, event):
 		if statefunc.ShowHelpFunc != nil {
-			statefunc.PushVisual(statefunc.MainFlex)
 			statefunc.ShowHelpFunc(false, nil)
 		}
 		return nil
@@ -156,6 +162,9 @@ func AddMainMenuToEditor(editor tview.Primitive, statusBar tview.Primitive, app
 		flex.AddItem(statusBar, 1, 0, false)
 	}
 	flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if keymapfunc.Matches(keymapfunc.EditorFormat, event) {
+			return event // Ctrl+Shift+F is not Find
+		}
 		switch event.Key() {
 		case tcell.KeyF10:
 			if statefunc.MainMenuFlex.HasFocus() {
@@ -173,8 +182,20 @@ func AddMainMenuToEditor(editor tview.Primitive, statusBar tview.Primitive, app
 				mainMenu.findTextArea.SetLabel("Find: ")
 				mainMenu.findTextArea.SetWrap(false)
 				mainMenu.findTextArea.SetTitle("Find")
+				findTerms.Reset()
 				mainMenu.findTextArea.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 					switch event.Key() {
+					case tcell.KeyUp:
+						// Walk through the previous searches
+						if term, ok := findTerms.Older(); ok {
+							mainMenu.findTextArea.SetText(term, true)
+						}
+						return nil
+					case tcell.KeyDown:
+						if term, ok := findTerms.Newer(); ok {
+							mainMenu.findTextArea.SetText(term, true)
+						}
+						return nil
 					case tcell.KeyEscape:
 						mainMenu.findFlex.RemoveItem(mainMenu.findTextArea)
 						mainMenu.findTextArea = nil
@@ -271,6 +292,18 @@ func showRunMenu(app *tview.Application) {
 				statefunc.StartScript(statefunc.L, Editor.GetFileName(), statefunc.RunLuaScriptFunc)
 				//_ = RunLuaScriptFunc(Editor.GetFileName())
 			}
+		}).
+		AddItem(i18nfunc.T("action.save_run", nil), i18nfunc.T("prompt.save_run", nil), 's', func() {
+			if statefunc.RunLuaScriptFunc != nil {
+				statefunc.App.SetRoot(statefunc.MainFlex, true)
+				Editor.SaveAndRun()
+			}
+		}).
+		AddItem(i18nfunc.T("action.console", nil), i18nfunc.T("prompt.console", nil), 'c', func() {
+			if statefunc.ShowConsoleFunc != nil {
+				statefunc.App.SetRoot(statefunc.MainFlex, true)
+				statefunc.ShowConsoleFunc()
+			}
 		})
 	list.SetBorder(true).SetTitle(i18nfunc.T("menu.run.title", nil))
 	flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
@@ -318,7 +351,6 @@ func showHelpMenu(app *tview.Application) {
 	flex := tview.NewFlex().SetDirection(tview.FlexRow)
 	list := tview.NewList().
 		AddItem(i18nfunc.T("menu.help", nil), i18nfunc.T("prompt.help", nil), 'h', func() {
-			statefunc.PushVisual(statefunc.MainFlex)
 			if statefunc.ShowHelpFunc != nil {
 				statefunc.ShowHelpFunc(false, nil)
 			}
@@ -2,6 +2,7 @@ package pagesfunc
 
 import (
 	"fmt"
+	"gotulua/i18nfunc"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,26 +11,31 @@ import (
 	"github.com/rivo/tview"
 )
 
+// ConfirmOverwrite is whether new Save As dialogs ask before replacing an existing file
+var ConfirmOverwrite = true
+
 // SaveAsDialog represents a dialog for saving a file with directory navigation
 type SaveAsDialog struct {
 	*tview.Flex
-	dirList       *tview.List
-	fileNameInput *tview.InputField
-	currentPath   string
-	onSave        func(filePath string) error
-	onCancel      func()
-	app           *tview.Application
+	dirList          *tview.List
+	fileNameInput    *tview.InputField
+	currentPath      string
+	onSave           func(filePath string) error
+	onCancel         func()
+	app              *tview.Application
+	confirmOverwrite bool // Ask before replacing an existing file
 }
 
 // newSaveAsDialog creates a new save as dialog
 func newSaveAsDialog(app *tview.Application, initialPath string, onSave func(filePath string) error, onCancel func()) *SaveAsDialog {
 	dialog := &SaveAsDialog{
-		Flex:          tview.NewFlex(),
-		dirList:       tview.NewList(),
-		fileNameInput: tview.NewInputField(),
-		onSave:        onSave,
-		onCancel:      onCancel,
-		app:           app,
+		Flex:             tview.NewFlex(),
+		dirList:          tview.NewList(),
+		fileNameInput:    tview.NewInputField(),
+		onSave:           onSave,
+		onCancel:         onCancel,
+		app:              app,
+		confirmOverwrite: ConfirmOverwrite,
 	}
 
 	// Set up directory list
@@ -147,15 +153,21 @@ func (d *SaveAsDialog) handleSave() {
 	filePath := filepath.Join(d.currentPath, fileName)
 
 	// Check if file exists
-	if _, err := os.Stat(filePath); err == nil {
+	if _, err := os.Stat(filePath); err == nil && d.confirmOverwrite {
 		// File exists, show confirmation dialog
 		confirm := tview.NewModal().
-			SetText(fmt.Sprintf("File '%s' already exists. Overwrite?", fileName)).
-			AddButtons([]string{"Yes", "No"}).
+			SetText(i18nfunc.T("dialog.file_exists_overwrite", map[string]interface{}{
+				"Name": fileName,
+			})).
+			AddButtons([]string{i18nfunc.T("button.yes", nil), i18nfunc.T("button.no", nil)}).
 			SetDoneFunc(func(buttonIndex int, buttonLabel string) {
-				if buttonLabel == "Yes" {
+				if buttonIndex == 0 {
 					d.saveFile(filePath)
+					return
 				}
+				// Keep the original file and let the user choose another name
+				d.app.SetRoot(d, true)
+				d.app.SetFocus(d.fileNameInput)
 			})
 		d.app.SetRoot(confirm, true)
 	} else {
@@ -0,0 +1,69 @@
+package pagesfunc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// pressOnFocus sends the key to the focused primitive of the application
+func pressOnFocus(app *tview.Application, key tcell.Key) {
+	app.GetFocus().InputHandler()(tcell.NewEventKey(key, 0, tcell.ModNone), func(p tview.Primitive) { app.SetFocus(p) })
+}
+
+func TestSaveAsAsksBeforeOverwriting(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.lua")
+	if err := os.WriteFile(path, []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	saves := 0
+	onSave := func(filePath string) error {
+		saves++
+		return os.WriteFile(filePath, []byte("new"), 0o644)
+	}
+	content := func() string {
+		data, _ := os.ReadFile(path)
+		return string(data)
+	}
+	app := tview.NewApplication()
+	d := newSaveAsDialog(app, dir, onSave, func() {})
+	d.fileNameInput.SetText("a.lua")
+
+	d.handleSave()
+	if _, ok := app.GetFocus().(*tview.Button); !ok || saves != 0 {
+		t.Fatalf("saved %d times without asking, focus on %T", saves, app.GetFocus())
+	}
+	pressOnFocus(app, tcell.KeyTab) // To No
+	pressOnFocus(app, tcell.KeyEnter)
+	if saves != 0 || content() != "old" {
+		t.Errorf("declining saved %d times, the file holds %q", saves, content())
+	}
+	if app.GetFocus() != d.fileNameInput {
+		t.Errorf("declining left the focus on %T, want the file name", app.GetFocus())
+	}
+
+	d.handleSave()
+	pressOnFocus(app, tcell.KeyEnter) // Yes
+	if saves != 1 || content() != "new" {
+		t.Errorf("confirming saved %d times, the file holds %q", saves, content())
+	}
+
+	// A new file and a dialog that does not ask are saved at once
+	d.fileNameInput.SetText("b.lua")
+	d.handleSave()
+	if saves != 2 {
+		t.Errorf("a new file was saved %d times, want once more", saves-1)
+	}
+	ConfirmOverwrite = false
+	t.Cleanup(func() { ConfirmOverwrite = true })
+	quiet := newSaveAsDialog(app, dir, onSave, func() {})
+	quiet.fileNameInput.SetText("a.lua")
+	quiet.handleSave()
+	if saves != 3 {
+		t.Error("the dialog asked with the confirmation turned off")
+	}
+}
@@ -3,16 +3,24 @@ package pagesfunc
 import (
 	"gotulua/editorfunc"
 	"gotulua/statefunc"
+
+	"github.com/rivo/tview"
 )
 
 var Editor *editorfunc.LuaEditor
 
 func ShowEditor(path string, line int, statusMsg string) {
+	ShowEditorAt(path, line, 0, statusMsg)
+}
+
+// ShowEditorAt shows the editor with the cursor on the line and column, both 0-based
+func ShowEditorAt(path string, line, col int, statusMsg string) {
 	Editor = editorfunc.NewLuaEditor(statefunc.App, "", path, nil)
-	if line > 0 {
-		Editor.GoToAndHighlightLine(line)
+	if line > 0 || col > 0 {
+		Editor.GoToPosition(line, col)
 	}
 	Editor.SetMouseSupport()
+	Editor.SetSaveAsDialogHandler(showEditorSaveAsDialog)
 	flex := AddMainMenuToEditor(Editor, Editor.GetStatusBar(), statefunc.App)
 	statefunc.MainFlex.AddItem(flex, 0, 1, true)
 }
@@ -29,3 +37,13 @@ func SwitchToEditor(path string, line int, statusMsg string, isError bool) {
 	}
 	statefunc.App.SetRoot(statefunc.MainFlex, true)
 }
+
+// showEditorSaveAsDialog is the Save As dialog used by the editor itself, e.g. to save and run a new file.
+// The editor gets back to the screen when the dialog is closed.
+func showEditorSaveAsDialog(app *tview.Application, fileName string, onSave func(string) error, onCancel func()) {
+	dlg := newSaveAsDialog(app, ".", onSave, func() {
+		app.SetRoot(statefunc.MainFlex, true)
+		onCancel()
+	})
+	app.SetRoot(dlg, true)
+}
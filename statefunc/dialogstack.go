@@ -0,0 +1,60 @@
+package statefunc
+
+import "github.com/rivo/tview"
+
+// openDialog is a dialog shown as the application root and what to show when it is closed
+type openDialog struct {
+	root       tview.Primitive
+	under      tview.Primitive // Root shown again when the dialog is closed
+	underFocus tview.Primitive // Primitive focused when the dialog was opened
+}
+
+// dialogStack keeps the open dialogs, the last one is on the screen
+var dialogStack []openDialog
+
+// PushDialog shows the dialog as the application root and focuses focus.
+// A dialog opened from an open dialog goes on top of it, otherwise it goes on top of base.
+// PopDialog closes it and shows again the dialog or the view that was under it.
+func PushDialog(root, focus, base tview.Primitive) {
+	// Dialogs left behind when the screen was switched by other means are not on the screen anymore
+	for len(dialogStack) > 0 && !dialogStack[len(dialogStack)-1].root.HasFocus() {
+		dialogStack = dialogStack[:len(dialogStack)-1]
+	}
+	under := base
+	if len(dialogStack) > 0 {
+		under = dialogStack[len(dialogStack)-1].root
+	}
+	dialogStack = append(dialogStack, openDialog{root: root, under: under, underFocus: App.GetFocus()})
+	App.SetRoot(root, true)
+	if focus != nil {
+		App.SetFocus(focus)
+	}
+	App.ForceDraw() // Ensure the dialog is drawn immediately
+}
+
+// PopDialog closes the top dialog and shows what was under it with its focus restored.
+// Returns false if no dialog is open.
+func PopDialog() bool {
+	if len(dialogStack) == 0 {
+		return false
+	}
+	d := dialogStack[len(dialogStack)-1]
+	dialogStack = dialogStack[:len(dialogStack)-1]
+	if d.under != nil {
+		App.SetRoot(d.under, true)
+	}
+	if d.underFocus != nil {
+		App.SetFocus(d.underFocus)
+	}
+	return true
+}
+
+// DialogCount returns the number of open dialogs
+func DialogCount() int {
+	return len(dialogStack)
+}
+
+// clearDialogs forgets the open dialogs
+func clearDialogs() {
+	dialogStack = nil
+}
@@ -0,0 +1,33 @@
+package statefunc
+
+import (
+	"testing"
+
+	"github.com/rivo/tview"
+)
+
+func TestClosingANestedDialogReturnsToItsParent(t *testing.T) {
+	SetState(tview.NewFlex(), tview.NewFlex(), tview.NewPages(), tview.NewApplication())
+	t.Cleanup(clearDialogs)
+	base := tview.NewBox()
+	App.SetRoot(base, true)
+	buttonA, buttonB := tview.NewButton("A"), tview.NewButton("B")
+	dialogA := tview.NewFlex().AddItem(buttonA, 0, 1, true)
+	dialogB := tview.NewFlex().AddItem(buttonB, 0, 1, true)
+
+	PushDialog(dialogA, buttonA, base)
+	PushDialog(dialogB, buttonB, base)
+	if DialogCount() != 2 || App.GetFocus() != buttonB {
+		t.Fatalf("%d dialogs are open with the focus on %v, want B on top of A", DialogCount(), App.GetFocus())
+	}
+
+	if !PopDialog() || App.GetFocus() != buttonA {
+		t.Errorf("closing B left the focus on %v, want dialog A", App.GetFocus())
+	}
+	if !PopDialog() || App.GetFocus() != base {
+		t.Errorf("closing A left the focus on %v, want the screen under it", App.GetFocus())
+	}
+	if PopDialog() {
+		t.Error("a dialog was closed with none open")
+	}
+}
@@ -3,6 +3,10 @@ package statefunc
 import (
 	"context"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"gotulua/i18nfunc"
 
 	"github.com/Shopify/go-lua"
 	"github.com/rivo/tview"
@@ -27,9 +31,30 @@ var visualStack *[]*tview.Flex
 var InitialTop int
 var runMode int = RunAsScript // Default run mode is script
 var ShowHelpFunc func(fromEditor bool, callback func(string))
+
+// errorMu guards the error state, the database functions may set it from any goroutine
+var errorMu sync.Mutex
 var lastErrorText string
 var isErrorRun bool
+
 var RunLuaScriptFunc func(string) error
+var RunLuaStringFunc func(string) error
+var ShowConsoleFunc func()
+
+// SelectionChunkName is the chunk name used when running the editor selection
+const SelectionChunkName = "selection"
+
+var selectionStartLine int
+
+// scriptAsync is set while a script started with StartScript is running
+var scriptAsync atomic.Bool
+var batchMode bool
+
+// functionTimeout is the time budget of lookup, field and idle functions called from the UI
+var functionTimeout = 5 * time.Second
+
+// timeoutHookCount is how many Lua instructions run between deadline checks
+const timeoutHookCount = 1000
 
 // ScriptManager handles script execution and interruption
 type ScriptManager struct {
@@ -90,6 +115,7 @@ func ShowPreviousVisual() {
 
 func ShowMainVisual() {
 	clearVisualStack()
+	clearDialogs()
 	if MainFlex != nil {
 		App.SetRoot(MainFlex, true)
 		App.ForceDraw()
@@ -103,6 +129,25 @@ func ShowRunVisual() {
 	}
 }
 
+// ToggleRunVisual switches between the editor and the output of the last run
+// without running the script again. Both layouts are kept as they are.
+func ToggleRunVisual() {
+	if RunFlexLevel0 == nil || MainFlex == nil {
+		return
+	}
+	if RunFlexLevel0.HasFocus() {
+		if len(*visualStack) > 0 && (*visualStack)[len(*visualStack)-1] == MainFlex {
+			ShowPreviousVisual()
+		}
+		return
+	}
+	if MainFlex.HasFocus() && RunFlexLevel0.GetItemCount() > 0 {
+		PushVisual(MainFlex)
+		App.SetRoot(RunFlexLevel0, true)
+		App.SetFocus(RunFlexLevel0)
+	}
+}
+
 // startScript starts script execution in a separate goroutine that can be cancelled
 func (sm *ScriptManager) startScript(L *lua.State, scriptName string, scriptFunc func(string) error) {
 	sm.mu.Lock()
@@ -127,6 +172,8 @@ func (sm *ScriptManager) startScript(L *lua.State, scriptName string, scriptFunc
 	sm.currentState = L
 	sm.currentCancel = cancel
 
+	// Set before the script starts, so the UI goroutine sees it as soon as StartScript returns
+	scriptAsync.Store(true)
 	go func() {
 		defer func() {
 			if r := recover(); r != nil {
@@ -152,6 +199,7 @@ func (sm *ScriptManager) startScript(L *lua.State, scriptName string, scriptFunc
 					}
 				}
 			}()
+			defer scriptAsync.Store(false)
 			done <- scriptFunc(scriptName)
 		}()
 
@@ -193,6 +241,86 @@ func (sm *ScriptManager) getCurrentLuaState() *lua.State {
 	return sm.currentState
 }
 
+// SetSelectionStartLine stores the editor line the running selection starts at
+func SetSelectionStartLine(line int) {
+	selectionStartLine = line
+}
+
+func GetSelectionStartLine() int {
+	return selectionStartLine
+}
+
+// IsScriptAsync reports whether the current script runs apart from the UI loop,
+// so it may wait for the user to answer a dialog.
+func IsScriptAsync() bool {
+	return scriptAsync.Load()
+}
+
+// appRunning is set while RunApp runs the UI loop
+var appRunning atomic.Bool
+
+// RunApp runs the UI loop of the application until it is stopped
+func RunApp() error {
+	appRunning.Store(true)
+	defer appRunning.Store(false)
+	return App.Run()
+}
+
+// WaitForAnswer shows a dialog with show and returns once the dialog calls done.
+// A script running apart from the UI loop waits for the loop to handle the dialog.
+// A script run before the UI loop is started, as "gotulua script.lua" does, runs the
+// loop until the answer comes. Returns false without showing the dialog when the UI
+// loop itself runs the script, as for menu items and buttons, since nothing can wait there.
+func WaitForAnswer(show func(done func())) bool {
+	if IsScriptAsync() {
+		answered := make(chan struct{})
+		show(func() { close(answered) })
+		<-answered
+		return true
+	}
+	if appRunning.Load() {
+		return false
+	}
+	show(App.Stop)
+	if err := RunApp(); err != nil {
+		SetLastErrorText(err.Error())
+	}
+	return true
+}
+
+// SetFunctionTimeout sets the time budget of lookup, field and idle functions.
+// A zero or negative duration disables the limit.
+func SetFunctionTimeout(d time.Duration) {
+	functionTimeout = d
+}
+
+func GetFunctionTimeout() time.Duration {
+	return functionTimeout
+}
+
+// ProtectedCallWithTimeout calls the function on top of the stack like
+// L.ProtectedCall, but aborts it with an error once it runs longer than the
+// function timeout, so an endless loop in a callback cannot freeze the UI.
+func ProtectedCallWithTimeout(L *lua.State, name string, nargs, nresults int) error {
+	budget := functionTimeout
+	if budget <= 0 {
+		return L.ProtectedCall(nargs, nresults, 0)
+	}
+	prevHook, prevMask, prevCount := lua.DebugHook(L), lua.DebugHookMask(L), lua.DebugHookCount(L)
+	defer lua.SetDebugHook(L, prevHook, prevMask, prevCount)
+
+	deadline := time.Now().Add(budget)
+	lua.SetDebugHook(L, func(l *lua.State, _ lua.Debug) {
+		if time.Now().After(deadline) {
+			lua.Errorf(l, "%s", i18nfunc.T("error.function_timeout", map[string]interface{}{
+				"Name":    name,
+				"Seconds": budget.Seconds(),
+			}))
+		}
+	}, lua.MaskCount, timeoutHookCount)
+	return L.ProtectedCall(nargs, nresults, 0)
+}
+
 // Convenience functions for the global script manager
 func StartScript(L *lua.State, scriptName string, scriptFunc func(string) error) {
 	Script.startScript(L, scriptName, scriptFunc)
@@ -228,6 +356,14 @@ func IsRunAsForm() bool {
 	return runMode == RunAsForm
 }
 
+// SetBatchMode switches the interpreter to run scripts without the UI
+func SetBatchMode(batch bool) {
+	batchMode = batch
+}
+func IsBatchMode() bool {
+	return batchMode
+}
+
 func CatchErrorShowEditor(msg string) {
 	//RunFlexLevel0.Clear()
 	//clearVisualStack()
@@ -238,20 +374,32 @@ func CatchErrorShowEditor(msg string) {
 }
 
 func SetLastErrorText(msg string) {
+	errorMu.Lock()
+	defer errorMu.Unlock()
 	lastErrorText = msg
 }
 func GetLastErrorText() string {
+	errorMu.Lock()
+	defer errorMu.Unlock()
 	return lastErrorText
 }
 func ClearErrors() {
+	errorMu.Lock()
+	defer errorMu.Unlock()
 	lastErrorText = ""
 }
 func SetErrorRun() {
+	errorMu.Lock()
+	defer errorMu.Unlock()
 	isErrorRun = true
 }
 func ClearErrorRun() {
+	errorMu.Lock()
+	defer errorMu.Unlock()
 	isErrorRun = false
 }
 func IsErrorRun() bool {
+	errorMu.Lock()
+	defer errorMu.Unlock()
 	return isErrorRun
 }
@@ -0,0 +1,100 @@
+package statefunc
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Shopify/go-lua"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func TestProtectedCallWithTimeout(t *testing.T) {
+	prev := GetFunctionTimeout()
+	SetFunctionTimeout(100 * time.Millisecond)
+	t.Cleanup(func() { SetFunctionTimeout(prev) })
+	L := lua.NewState()
+	lua.OpenLibraries(L)
+	if err := lua.DoString(L, `
+		function endless() while true do end end
+		function double(n) return n * 2 end`); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	L.Global("endless")
+	if err := ProtectedCallWithTimeout(L, "endless", 0, 0); err == nil {
+		t.Fatal("the endless function was not interrupted")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("the endless function was interrupted after %v", elapsed)
+	}
+	L.SetTop(0)
+
+	L.Global("double")
+	L.PushInteger(21)
+	if err := ProtectedCallWithTimeout(L, "double", 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := L.ToInteger(-1); n != 42 {
+		t.Errorf("double returned %d, want 42", n)
+	}
+	if lua.DebugHook(L) != nil {
+		t.Error("the timeout hook was left set")
+	}
+}
+
+// screenText draws the application and returns the text of the first screen row
+func screenText(t *testing.T, screen tcell.SimulationScreen) string {
+	t.Helper()
+	App.ForceDraw()
+	cells, width, _ := screen.GetContents()
+	var sb strings.Builder
+	for _, cell := range cells[:width] {
+		sb.WriteString(string(cell.Runes))
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+func TestToggleRunVisualSwitchesBetweenEditorAndOutput(t *testing.T) {
+	SetState(tview.NewFlex(), tview.NewFlex(), tview.NewPages(), tview.NewApplication())
+	screen := tcell.NewSimulationScreen("")
+	screen.SetSize(40, 5)
+	App.SetScreen(screen)
+	editor := tview.NewTextArea().SetText("x = 1", false)
+	MainFlex.AddItem(editor, 0, 1, true)
+	App.SetRoot(MainFlex, true)
+
+	// Nothing has run yet, the editor stays
+	ToggleRunVisual()
+	if !MainFlex.HasFocus() || len(*visualStack) != 0 {
+		t.Fatal("toggling without output left the editor")
+	}
+
+	output := tview.NewTextView().SetText("output")
+	RunFlexLevel0.AddItem(output, 0, 1, true)
+	ToggleRunVisual()
+	if !RunFlexLevel0.HasFocus() || screenText(t, screen) != "output" {
+		t.Fatal("toggling from the editor did not show the output")
+	}
+	if n := len(*visualStack); n == 0 || (*visualStack)[n-1] != MainFlex {
+		t.Error("the editor is not on top of the visual stack")
+	}
+
+	ToggleRunVisual()
+	if !MainFlex.HasFocus() || screenText(t, screen) != "x = 1" {
+		t.Fatal("toggling from the output did not show the editor")
+	}
+	if got := editor.GetText(); got != "x = 1" {
+		t.Errorf("the editor holds %q after toggling, want x = 1", got)
+	}
+
+	// The focus on neither layout, as with a dialog open
+	other := tview.NewBox()
+	App.SetRoot(other, true)
+	ToggleRunVisual()
+	if App.GetFocus() != other || len(*visualStack) != 0 {
+		t.Error("toggling with the focus on neither layout changed the screen")
+	}
+}
@@ -0,0 +1,101 @@
+package strfunc
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// PadLeft puts the pad characters before s until it is n characters long.
+// An empty pad pads with spaces, s longer than n is returned as it is.
+func PadLeft(s string, n int, pad string) string {
+	return padding(s, n, pad) + s
+}
+
+// PadRight puts the pad characters after s until it is n characters long
+func PadRight(s string, n int, pad string) string {
+	return s + padding(s, n, pad)
+}
+
+// padding returns the characters needed to make s n characters long.
+// Lengths count characters, not bytes, so accented text is padded right.
+func padding(s string, n int, pad string) string {
+	if pad == "" {
+		pad = " "
+	}
+	missing := n - utf8.RuneCountInString(s)
+	if missing <= 0 {
+		return ""
+	}
+	runes := []rune(strings.Repeat(pad, missing/utf8.RuneCountInString(pad)+1))
+	return string(runes[:missing])
+}
+
+// Format works like fmt.Sprintf for the values coming from Lua.
+// Lua numbers are float64, so whole numbers are passed as integers to %d, %x, %o, %b and %c,
+// and %s and %v show numbers the way Lua does (3 instead of 3.0).
+func Format(format string, args []interface{}) string {
+	verbs := formatVerbs(format)
+	for i, v := range args {
+		if i >= len(verbs) {
+			break
+		}
+		switch verbs[i] {
+		case 'd', 'x', 'X', 'o', 'b', 'c':
+			if f, ok := v.(float64); ok && f == math.Trunc(f) {
+				args[i] = int64(f)
+			}
+		case '*':
+			if f, ok := v.(float64); ok {
+				args[i] = int(f)
+			}
+		case 's', 'v', 'q':
+			args[i] = ToString(v)
+		}
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// formatVerbs returns the verbs of the format in the order they take the arguments
+func formatVerbs(format string) []rune {
+	var verbs []rune
+	inVerb := false
+	for _, c := range format {
+		if !inVerb {
+			inVerb = c == '%'
+			continue
+		}
+		switch {
+		case c == '%':
+			inVerb = false // %% takes no argument
+		case c == '*':
+			verbs = append(verbs, c) // width or precision taken from the arguments
+		case strings.ContainsRune("+-# 0123456789.", c):
+			// flags, width and precision
+		default:
+			verbs = append(verbs, c)
+			inVerb = false
+		}
+	}
+	return verbs
+}
+
+// ToString returns the value as Lua's tostring shows it
+func ToString(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return x
+	case bool:
+		return strconv.FormatBool(x)
+	case float64:
+		if x == math.Trunc(x) && math.Abs(x) < 1e15 {
+			return strconv.FormatInt(int64(x), 10)
+		}
+		return strconv.FormatFloat(x, 'g', 14, 64)
+	}
+	return fmt.Sprintf("%v", v)
+}
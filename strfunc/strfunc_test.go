@@ -0,0 +1,45 @@
+package strfunc
+
+import "testing"
+
+func TestPad(t *testing.T) {
+	tests := []struct {
+		s, pad      string
+		n           int
+		left, right string
+	}{
+		{"7", "0", 3, "007", "700"},
+		{"ab", "", 4, "  ab", "ab  "},
+		{"ab", "-=", 5, "-=-ab", "ab-=-"},
+		{"éü", "*", 3, "*éü", "éü*"}, // Characters are counted, not bytes
+		{"long", "*", 2, "long", "long"},
+	}
+	for _, tt := range tests {
+		if got := PadLeft(tt.s, tt.n, tt.pad); got != tt.left {
+			t.Errorf("PadLeft(%q, %d, %q) = %q, want %q", tt.s, tt.n, tt.pad, got, tt.left)
+		}
+		if got := PadRight(tt.s, tt.n, tt.pad); got != tt.right {
+			t.Errorf("PadRight(%q, %d, %q) = %q, want %q", tt.s, tt.n, tt.pad, got, tt.right)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		format string
+		args   []interface{} // As they come from Lua
+		want   string
+	}{
+		{"%d items", []interface{}{3.0}, "3 items"},
+		{"%s has %d, %.2f each", []interface{}{"Anna", 2.0, 1.5}, "Anna has 2, 1.50 each"},
+		{"%v and %s", []interface{}{3.0, true}, "3 and true"},
+		{"%*d|", []interface{}{4.0, 7.0}, "   7|"},
+		{"100%% %x", []interface{}{255.0}, "100% ff"},
+		{"%d", []interface{}{2.5}, "%!d(float64=2.5)"}, // Not a whole number, left as it is
+	}
+	for _, tt := range tests {
+		if got := Format(tt.format, tt.args); got != tt.want {
+			t.Errorf("Format(%q, %v) = %q, want %q", tt.format, tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package strfunc
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// UUID returns a random (version 4) UUID like "0b5fcd1e-7d63-4f5a-9c1e-2a8d3f6b7c90"
+func UUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
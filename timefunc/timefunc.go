@@ -208,6 +208,28 @@ func TemplateToPlaceholder(tpl string) string {
 	return t
 }
 
+// UserFormat returns the user format template of the date/time type, e.g. dd.mm.yyyy
+func UserFormat(mode string) string {
+	switch mode {
+	case typesfunc.TypeTime:
+		return TimeFormat
+	case typesfunc.TypeDateTime:
+		return DateTimeFormat
+	}
+	return DateFormat
+}
+
+// FieldFormatError returns the error for a value of the field that does not match
+// the user format of its date/time type.
+func FieldFormatError(field, value, mode string) error {
+	return errors.New(i18nfunc.T("error.field_datetime_format", map[string]interface{}{
+		"Field":  field,
+		"Value":  value,
+		"Type":   mode,
+		"Format": UserFormat(mode),
+	}))
+}
+
 func FormatDateTime(inp, mode string, direction int) (string, error) {
 	if mode != typesfunc.TypeDate && mode != typesfunc.TypeTime && mode != typesfunc.TypeDateTime {
 		return "", fmt.Errorf(i18nfunc.T("error.datetime_type", map[string]interface{}{
@@ -352,12 +374,144 @@ func DateDiff(start, end, mode string) int64 {
 		return int64(endT.Sub(startT).Hours() / 168)
 	case "m", "M":
 		return int64(endT.Sub(startT).Hours() / 720)
+	case "q", "Q":
+		return int64(endT.Sub(startT).Hours() / 2160)
 	case "y", "Y":
 		return int64(endT.Sub(startT).Hours() / 8760)
 	}
 	return -1
 }
 
+// DayOfWeek returns the day of the week of the date, 1 for Monday to 7 for Sunday,
+// -1 if the date is not in the format set by SetDateFormat
+func DayOfWeek(date string) int {
+	t, ok := parseDate(date)
+	if !ok {
+		return -1
+	}
+	return isoWeekday(t)
+}
+
+// isoWeekday returns the day of the week, 1 for Monday to 7 for Sunday
+func isoWeekday(t time.Time) int {
+	if t.Weekday() == time.Sunday {
+		return 7
+	}
+	return int(t.Weekday())
+}
+
+// WeekNumber returns the ISO 8601 week of the date, -1 if the date is not in
+// the format set by SetDateFormat. The first days of January can belong to the
+// last week of the year before and the last days of December to week 1.
+func WeekNumber(date string) int {
+	t, ok := parseDate(date)
+	if !ok {
+		return -1
+	}
+	_, week := t.ISOWeek()
+	return week
+}
+
+// Today returns the current date in the format set by SetDateFormat
+func Today() string {
+	return formatDate(time.Now())
+}
+
+// Yesterday returns the date before today in the format set by SetDateFormat
+func Yesterday() string {
+	return formatDate(time.Now().AddDate(0, 0, -1))
+}
+
+// Tomorrow returns the date after today in the format set by SetDateFormat
+func Tomorrow() string {
+	return formatDate(time.Now().AddDate(0, 0, 1))
+}
+
+// StartOfMonth returns the first day of the month of the date, of the current month if
+// the date is empty. Returns "" if the date is not in the format set by SetDateFormat.
+func StartOfMonth(date string) string {
+	t, ok := dateOrToday(date)
+	if !ok {
+		return ""
+	}
+	return formatDate(time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC))
+}
+
+// EndOfMonth returns the last day of the month of the date, of the current month if
+// the date is empty. Returns "" if the date is not in the format set by SetDateFormat.
+func EndOfMonth(date string) string {
+	t, ok := dateOrToday(date)
+	if !ok {
+		return ""
+	}
+	return formatDate(time.Date(t.Year(), t.Month(), DaysInMonth(t.Year(), int(t.Month())), 0, 0, 0, 0, time.UTC))
+}
+
+// IsLeapYear reports whether February of the year has 29 days. Years divisible by 100
+// are leap years only when they are also divisible by 400, so 1900 is not and 2000 is.
+func IsLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// daysInMonths are the days of the months of a year that is not a leap year
+var daysInMonths = [12]int{31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+
+// DaysInMonth returns the number of days of the month, 1 to 12, in the year.
+// Returns 0 for a month outside 1 to 12.
+func DaysInMonth(year, month int) int {
+	if month < 1 || month > 12 {
+		return 0
+	}
+	if month == 2 && IsLeapYear(year) {
+		return 29
+	}
+	return daysInMonths[month-1]
+}
+
+// StartOfWeek returns the Monday of the week of the date, of the current week if
+// the date is empty. Returns "" if the date is not in the format set by SetDateFormat.
+func StartOfWeek(date string) string {
+	t, ok := dateOrToday(date)
+	if !ok {
+		return ""
+	}
+	return formatDate(t.AddDate(0, 0, 1-isoWeekday(t)))
+}
+
+// dateOrToday parses a date in the format set by SetDateFormat, an empty date is today
+func dateOrToday(date string) (time.Time, bool) {
+	if date == "" {
+		now := time.Now()
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), true
+	}
+	return parseDate(date)
+}
+
+// formatDate formats a date in the format set by SetDateFormat
+func formatDate(t time.Time) string {
+	gs, err := customTemplateToGoTemplate(DateFormat, typesfunc.TypeDate)
+	if err != nil {
+		return ""
+	}
+	return t.Format(gs)
+}
+
+// parseDate parses a date in the format set by SetDateFormat
+func parseDate(date string) (time.Time, bool) {
+	if date == "" {
+		return time.Time{}, false
+	}
+	gs, err := customTemplateToGoTemplate(DateFormat, typesfunc.TypeDate)
+	if err != nil {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(gs, date)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
 func TimeDiff(start, end, mode string) int64 {
 	if start == "" || end == "" {
 		return -1
@@ -0,0 +1,109 @@
+package timefunc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDateDiffUnits(t *testing.T) {
+	tests := []struct {
+		start, end, mode string
+		want             int64
+	}{
+		{"01.01.2024", "15.01.2024", "d", 14},
+		{"01.01.2024", "15.01.2024", "w", 2},
+		{"01.01.2024", "14.01.2024", "W", 1},
+		{"01.01.2024", "01.07.2024", "q", 2},
+		{"01.01.2024", "01.07.2024", "Q", 2},
+		{"01.01.2024", "01.01.2025", "y", 1},
+		{"15.01.2024", "01.01.2024", "w", -2},
+		{"01.01.2024", "15.01.2024", "x", -1},
+		{"2024-01-01", "15.01.2024", "d", -1},
+	}
+	for _, tt := range tests {
+		if got := DateDiff(tt.start, tt.end, tt.mode); got != tt.want {
+			t.Errorf("DateDiff(%q, %q, %q) = %d, want %d", tt.start, tt.end, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestDayOfWeekAndWeekNumber(t *testing.T) {
+	tests := []struct {
+		date      string
+		day, week int
+	}{
+		{"15.01.2024", 1, 3},  // Monday
+		{"21.01.2024", 7, 3},  // Sunday ends the week
+		{"01.01.2021", 5, 53}, // Belongs to the last week of 2020
+		{"31.12.2024", 2, 1},  // Belongs to the first week of 2025
+		{"not a date", -1, -1},
+	}
+	for _, tt := range tests {
+		if got := DayOfWeek(tt.date); got != tt.day {
+			t.Errorf("DayOfWeek(%q) = %d, want %d", tt.date, got, tt.day)
+		}
+		if got := WeekNumber(tt.date); got != tt.week {
+			t.Errorf("WeekNumber(%q) = %d, want %d", tt.date, got, tt.week)
+		}
+	}
+}
+
+func TestRelativeDates(t *testing.T) {
+	now := time.Now()
+	day := func(offset int) string { return now.AddDate(0, 0, offset).Format("02.01.2006") }
+	if got := Today(); got != day(0) {
+		t.Errorf("Today() = %q, want %q", got, day(0))
+	}
+	if got := Yesterday(); got != day(-1) {
+		t.Errorf("Yesterday() = %q, want %q", got, day(-1))
+	}
+	if got := Tomorrow(); got != day(1) {
+		t.Errorf("Tomorrow() = %q, want %q", got, day(1))
+	}
+
+	tests := []struct {
+		name string
+		fn   func(string) string
+		date string
+		want string
+	}{
+		{"StartOfMonth", StartOfMonth, "15.02.2024", "01.02.2024"},
+		{"EndOfMonth", EndOfMonth, "15.02.2024", "29.02.2024"},
+		{"EndOfMonth", EndOfMonth, "01.12.2023", "31.12.2023"},
+		{"StartOfWeek", StartOfWeek, "21.01.2024", "15.01.2024"}, // Sunday
+		{"StartOfWeek", StartOfWeek, "15.01.2024", "15.01.2024"}, // Monday
+		{"StartOfWeek", StartOfWeek, "01.03.2024", "26.02.2024"}, // Across the month
+		{"StartOfMonth", StartOfMonth, "", "01" + now.Format(".01.2006")},
+		{"EndOfMonth", EndOfMonth, "not a date", ""},
+	}
+	for _, tt := range tests {
+		if got := tt.fn(tt.date); got != tt.want {
+			t.Errorf("%s(%q) = %q, want %q", tt.name, tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestIsLeapYearAndDaysInMonth(t *testing.T) {
+	tests := []struct {
+		year, month int
+		leap        bool
+		days        int
+	}{
+		{2024, 2, true, 29},
+		{2023, 2, false, 28},
+		{1900, 2, false, 28},
+		{2000, 2, true, 29},
+		{2023, 4, false, 30},
+		{2024, 12, true, 31},
+		{2024, 0, true, 0},
+		{2024, 13, true, 0},
+	}
+	for _, tt := range tests {
+		if got := IsLeapYear(tt.year); got != tt.leap {
+			t.Errorf("IsLeapYear(%d) = %v, want %v", tt.year, got, tt.leap)
+		}
+		if got := DaysInMonth(tt.year, tt.month); got != tt.days {
+			t.Errorf("DaysInMonth(%d, %d) = %d, want %d", tt.year, tt.month, got, tt.days)
+		}
+	}
+}
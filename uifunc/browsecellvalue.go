@@ -0,0 +1,63 @@
+package uifunc
+
+import (
+	"gotulua/statefunc"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// newFieldCell returns a cell of the field with the text, cut to the maximum width of the field if it has one
+func newFieldCell(field TBrowseField, text string) *tview.TableCell {
+	return tview.NewTableCell(text).SetMaxWidth(field.MaxWidth)
+}
+
+// selectedCellValue returns the caption of the field of the selected cell and its full text,
+// also the part not shown in a column limited by w::. ok is false if no value cell is selected.
+func (b *TBrowse) selectedCellValue() (caption, text string, ok bool) {
+	row, column := b.TableView.GetSelection()
+	if row < b.headerRows() {
+		return "", "", false
+	}
+	cell := b.TableView.GetCell(row, column)
+	if cell == nil {
+		return "", "", false
+	}
+	if field, isField := cell.GetReference().(TBrowseField); isField {
+		caption = field.Caption
+		if caption == "" {
+			caption = field.Name
+		}
+	} else if header := b.TableView.GetCell(b.captionRow(), column); header != nil {
+		caption = header.Text
+	}
+	return caption, cell.Text, true
+}
+
+// showCellValue shows the full text of the selected cell in a scrollable box
+func (b *TBrowse) showCellValue() {
+	caption, text, ok := b.selectedCellValue()
+	if !ok {
+		return
+	}
+	ShowText(caption, text)
+}
+
+// ShowText shows a text in a scrollable box over the screen. Escape or Enter closes it.
+func ShowText(title, text string) *tview.TextView {
+	view := tview.NewTextView().SetText(text).SetScrollable(true).SetWrap(true)
+	view.SetBorder(true).SetTitle(" " + title + " ")
+	view.SetDoneFunc(func(key tcell.Key) {
+		statefunc.PopDialog()
+	})
+	column := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(view, 0, 3, true).
+		AddItem(nil, 0, 1, false)
+	root := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(column, 0, 3, true).
+		AddItem(nil, 0, 1, false)
+	statefunc.PushDialog(root, view, statefunc.RunFlexLevel0)
+	return view
+}
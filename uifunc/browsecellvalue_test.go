@@ -0,0 +1,37 @@
+package uifunc
+
+import (
+	"gotulua/statefunc"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func TestLimitedColumnShowsTheFullValueOnF3(t *testing.T) {
+	L, db := newTestState(t)
+	long := "a value longer than its column"
+	b := newTestBrowse(t, L, db, "notes", false, long)
+	b.Fields = nil
+	b.addField(L, "n::a;c::A;w::5")
+	showTestBrowse(L, b)
+	t.Cleanup(func() {
+		for statefunc.PopDialog() {
+		}
+	})
+
+	cell := b.TableView.GetCell(b.headerRows(), 0)
+	if cell.MaxWidth != 5 || cell.Text != long {
+		t.Errorf("the cell holds %q cut to %d, want the whole value cut to 5", cell.Text, cell.MaxWidth)
+	}
+
+	b.TableView.Select(b.headerRows(), 0)
+	pressKey(b, tcell.KeyF3, 0)
+	view, ok := statefunc.App.GetFocus().(*tview.TextView)
+	if !ok {
+		t.Fatalf("no value box was shown, focus on %T", statefunc.App.GetFocus())
+	}
+	if got := view.GetText(true); got != long {
+		t.Errorf("the box shows %q, want the full value", got)
+	}
+}
@@ -0,0 +1,168 @@
+package uifunc
+
+import (
+	"gotulua/errorhandlefunc"
+	"gotulua/i18nfunc"
+
+	"github.com/Shopify/go-lua"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// columnGroupRow is the row of the group captions, above the column captions
+const columnGroupRow = 0
+
+// columnGroup is a caption shown above the columns of the fields from First to Last
+type columnGroup struct {
+	Caption string
+	First   string
+	Last    string
+}
+
+// captionRow returns the row of the column captions, under the group captions if there are any
+func (b *TBrowse) captionRow() int {
+	if len(b.columnGroups) > 0 {
+		return columnGroupRow + 1
+	}
+	return 0
+}
+
+// fieldOrder returns the position of every field of the browse, also of the hidden ones
+func (b *TBrowse) fieldOrder() map[string]int {
+	order := make(map[string]int)
+	if len(b.Fields) > 0 {
+		for i, f := range b.Fields {
+			order[f.Name] = i
+		}
+	} else {
+		for i, col := range b.columns() {
+			order[col] = i
+		}
+	}
+	return order
+}
+
+// columnGroupOf returns the group spanning the field, nil if it is in no group
+func (b *TBrowse) columnGroupOf(field string, order map[string]int) *columnGroup {
+	pos, ok := order[field]
+	if !ok {
+		return nil
+	}
+	for i, g := range b.columnGroups {
+		first, last := order[g.First], order[g.Last]
+		if first > last {
+			first, last = last, first
+		}
+		if pos >= first && pos <= last {
+			return &b.columnGroups[i]
+		}
+	}
+	return nil
+}
+
+// fillColumnGroups shows the group captions above the column captions. The caption is shown
+// in the first shown column of its group, the other columns of the group get an empty cell.
+func (b *TBrowse) fillColumnGroups() {
+	if len(b.columnGroups) == 0 {
+		return
+	}
+	order := b.fieldOrder()
+	var prev *columnGroup
+	for i, field := range b.filterFields() {
+		cell := tview.NewTableCell("").SetSelectable(false)
+		g := b.columnGroupOf(field.Name, order)
+		if g != nil {
+			if g != prev {
+				cell.SetText(g.Caption)
+			}
+			cell.SetAttributes(tcell.AttrBold)
+		}
+		prev = g
+		b.TableView.SetCell(columnGroupRow, i, cell)
+	}
+}
+
+// SetColumnGroups sets captions shown in a row above the column captions, each spanning
+// the columns of a range of fields.
+// Lua: browse:SetColumnGroups({{caption, firstField, lastField}, ...}), nil removes the groups.
+func SetColumnGroups(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "SetColumnGroups",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	browse, ok := L.ToUserData(1).(*TBrowse)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.first_argument_not_browse", nil), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	var groups []columnGroup
+	if !L.IsNil(2) {
+		if !L.IsTable(2) {
+			errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_lua_table", map[string]interface{}{
+				"Name": "groups",
+			}), errorhandlefunc.ErrorTypeScript, true)
+			return 0
+		}
+		order := browse.fieldOrder()
+		for i := 1; ; i++ {
+			L.RawGetInt(2, i)
+			if L.IsNil(-1) {
+				L.Pop(1)
+				break
+			}
+			g, ok := readColumnGroup(L, order)
+			L.Pop(1)
+			if !ok {
+				return 0
+			}
+			groups = append(groups, g)
+		}
+	}
+	if browse.TableView == nil {
+		browse.columnGroups = groups
+		return 0
+	}
+	// Keep the selection on the same data row when the header gets or loses the group row
+	row, column := browse.TableView.GetSelection()
+	row -= browse.headerRows()
+	browse.columnGroups = groups
+	browse.TableView.SetFixed(browse.headerRows(), browse.fixedColumns)
+	browse.TableView.Select(max(row, 0)+browse.headerRows(), column)
+	browse.renderColumns()
+	return 0
+}
+
+// readColumnGroup reads the group {caption, firstField, lastField} on top of the stack.
+// The last field can be left out for a group of one column.
+func readColumnGroup(L *lua.State, order map[string]int) (columnGroup, bool) {
+	if !L.IsTable(-1) {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_lua_table", map[string]interface{}{
+			"Name": "group",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return columnGroup{}, false
+	}
+	var parts [3]string
+	for j := range parts {
+		L.RawGetInt(-1, j+1)
+		parts[j], _ = L.ToString(-1)
+		L.Pop(1)
+	}
+	if parts[2] == "" {
+		parts[2] = parts[1]
+	}
+	if parts[0] == "" || parts[1] == "" {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.browse_invalid_column_group", nil), errorhandlefunc.ErrorTypeScript, true)
+		return columnGroup{}, false
+	}
+	for _, name := range parts[1:] {
+		if _, ok := order[name]; !ok {
+			errorhandlefunc.ThrowError(i18nfunc.T("error.field_not_found", map[string]interface{}{
+				"Name": name,
+			}), errorhandlefunc.ErrorTypeScript, true)
+			return columnGroup{}, false
+		}
+	}
+	return columnGroup{Caption: parts[0], First: parts[1], Last: parts[2]}, true
+}
@@ -0,0 +1,59 @@
+package uifunc
+
+import (
+	"gotulua/gormfunc"
+	"testing"
+
+	"github.com/Shopify/go-lua"
+)
+
+// setColumnGroups calls SetColumnGroups with the groups given as Lua code, "nil" removes them
+func setColumnGroups(t *testing.T, L *lua.State, b *TBrowse, groups string) {
+	t.Helper()
+	L.PushUserData(b)
+	if err := lua.LoadString(L, "return "+groups); err != nil {
+		t.Fatal(err)
+	}
+	L.Call(0, 1)
+	SetColumnGroups(L)
+	L.SetTop(0)
+}
+
+func TestColumnGroupsAreShownAboveTheCaptions(t *testing.T) {
+	L, db := newTestState(t)
+	if _, err := gormfunc.Exec(db, "CREATE TABLE parts (id INTEGER PRIMARY KEY, a TEXT, b TEXT, c TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gormfunc.Exec(db, "INSERT INTO parts (a, b, c) VALUES ('a1', 'b1', 'c1')"); err != nil {
+		t.Fatal(err)
+	}
+	b := &TBrowse{Table: gormfunc.OpenTable(db, "parts"), Filters: map[string]string{}, NewRowNum: -1}
+	for _, f := range []string{"n::a;c::A", "n::b;c::B", "n::c;c::C"} {
+		b.addField(L, f)
+	}
+	showTestBrowse(L, b)
+	rows := b.headerRows()
+
+	setColumnGroups(t, L, b, `{{"AB", "a", "b"}}`)
+	if b.headerRows() != rows+1 {
+		t.Fatalf("the header has %d rows, want %d with the group row", b.headerRows(), rows+1)
+	}
+	for col, want := range []string{"AB", "", ""} {
+		if got := b.TableView.GetCell(columnGroupRow, col).Text; got != want {
+			t.Errorf("group cell %d shows %q, want %q", col, got, want)
+		}
+	}
+	for col, want := range []string{"A", "B", "C"} {
+		if got := b.TableView.GetCell(b.captionRow(), col).Text; got != want {
+			t.Errorf("caption %d shows %q, want %q", col, got, want)
+		}
+	}
+	if got := b.TableView.GetCell(b.headerRows(), 2).Text; got != "c1" {
+		t.Errorf("the first row shows %q in column c, want c1", got)
+	}
+
+	setColumnGroups(t, L, b, "nil")
+	if b.headerRows() != rows || b.TableView.GetCell(b.captionRow(), 0).Text != "A" {
+		t.Errorf("removing the groups left %d header rows, want %d", b.headerRows(), rows)
+	}
+}
@@ -0,0 +1,75 @@
+package uifunc
+
+import (
+	"gotulua/errorhandlefunc"
+	"gotulua/i18nfunc"
+
+	"github.com/Shopify/go-lua"
+)
+
+// SetColumnVisible hides or shows a field of the browse.
+// Lua: browse:SetColumnVisible(field, visible). A shown browse is rendered again with its columns.
+func SetColumnVisible(L *lua.State) int {
+	if L.Top() < 3 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "SetColumnVisible",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	browse, ok := L.ToUserData(1).(*TBrowse)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.first_argument_not_browse", nil), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	fieldName, ok := L.ToString(2)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "field",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	f := browse.findFieldByName(fieldName)
+	if f == nil {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.field_not_found", map[string]interface{}{
+			"Name": fieldName,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	hidden := !L.ToBoolean(3)
+	if f.Hidden == hidden {
+		return 0
+	}
+	f.Hidden = hidden
+	if browse.TableView != nil {
+		browse.renderColumns()
+	}
+	return 0
+}
+
+// renderColumns rebuilds the columns of a shown browse from its visible fields and loads
+// the rows again. The selection stays on the same row and, if it is still shown, the same field.
+func (b *TBrowse) renderColumns() {
+	row, column := b.TableView.GetSelection()
+	selected := ""
+	if cell := b.TableView.GetCell(row, column); cell != nil {
+		if field, ok := cell.GetReference().(TBrowseField); ok {
+			selected = field.Name
+		}
+	}
+	b.clearNewRowMode()
+	b.shownFields = b.visibleFields()
+	b.TableView.Clear()
+	for i, field := range b.shownFields {
+		b.TableView.SetCell(b.captionRow(), i, newFieldCell(field, field.Caption).SetSelectable(false))
+	}
+	b.refreshBrowse(false)
+	column = min(column, max(len(b.shownFields)-1, 0))
+	for i, field := range b.shownFields {
+		if field.Name == selected {
+			column = i
+			break
+		}
+	}
+	row = min(max(row, b.headerRows()), b.TableView.GetRowCount()-1)
+	b.TableView.Select(row, column)
+}
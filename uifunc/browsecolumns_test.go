@@ -0,0 +1,61 @@
+package uifunc
+
+import (
+	"gotulua/gormfunc"
+	"testing"
+
+	"github.com/Shopify/go-lua"
+)
+
+// setColumnVisible calls SetColumnVisible as browse:SetColumnVisible(field, visible)
+func setColumnVisible(L *lua.State, b *TBrowse, field string, visible bool) {
+	L.PushUserData(b)
+	L.PushString(field)
+	L.PushBoolean(visible)
+	SetColumnVisible(L)
+	L.SetTop(0)
+}
+
+func TestSetColumnVisibleHidesAndRestoresAColumn(t *testing.T) {
+	L, db := newTestState(t)
+	if _, err := gormfunc.Exec(db, "CREATE TABLE pairs (id INTEGER PRIMARY KEY, a TEXT, b TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gormfunc.Exec(db, "INSERT INTO pairs (a, b) VALUES ('a1', 'b1'), ('a2', 'b2')"); err != nil {
+		t.Fatal(err)
+	}
+	b := &TBrowse{Table: gormfunc.OpenTable(db, "pairs"), Filters: map[string]string{}, NewRowNum: -1}
+	b.addField(L, "n::a;c::A")
+	b.addField(L, "n::b;c::B")
+	b.Fields[1].IsEditable = true
+	showTestBrowse(L, b)
+	first := b.headerRows()
+
+	setColumnVisible(L, b, "a", false)
+	if n := b.TableView.GetColumnCount(); n != 1 {
+		t.Fatalf("the browse shows %d columns, want 1", n)
+	}
+	if got := b.TableView.GetCell(first+1, 0).Text; got != "b2" {
+		t.Errorf("the second row shows %q, want b2", got)
+	}
+
+	b.TableView.Select(first+1, 0)
+	editSelectedCell(t, b, "B2")
+	var saved []string
+	if err := db.Raw("SELECT a || '/' || b FROM pairs ORDER BY id").Scan(&saved).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(saved) != 2 || saved[0] != "a1/b1" || saved[1] != "a2/B2" {
+		t.Errorf("the table holds %q, want the edit saved in b of the second row", saved)
+	}
+
+	setColumnVisible(L, b, "a", true)
+	if n := b.TableView.GetColumnCount(); n != 2 {
+		t.Fatalf("the browse shows %d columns, want 2", n)
+	}
+	for col, want := range []string{"a2", "B2"} {
+		if got := b.TableView.GetCell(first+1, col).Text; got != want {
+			t.Errorf("column %d of the second row shows %q, want %q", col, got, want)
+		}
+	}
+}
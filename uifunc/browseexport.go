@@ -0,0 +1,105 @@
+package uifunc
+
+import (
+	"bytes"
+	"gotulua/gormfunc"
+	"gotulua/i18nfunc"
+	"gotulua/inputfunc"
+	"gotulua/statefunc"
+
+	"github.com/atotto/clipboard"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// exportColumns returns the table fields shown in the browse, in display order
+func (b *TBrowse) exportColumns() []string {
+	if len(b.Fields) == 0 {
+		return b.columns()
+	}
+	var cols []string
+	for _, field := range b.shownFields {
+		if field.IsTableField {
+			cols = append(cols, field.Name)
+		}
+	}
+	return cols
+}
+
+// exportRowCount returns the number of rows in the current filtered view
+func (b *TBrowse) exportRowCount() int {
+	if b.Table.Rows == nil {
+		return 0
+	}
+	return len(b.Table.Rows.Rows)
+}
+
+// showExportMenu lets the user export the current filtered view of the browse
+func (b *TBrowse) showExportMenu() {
+	items := []string{
+		i18nfunc.T("action.export_csv", nil),
+		i18nfunc.T("action.export_json", nil),
+		i18nfunc.T("action.copy_all", nil),
+	}
+	PickList(i18nfunc.T("menu.export.title", nil), items, func(index int) {
+		switch index {
+		case 0:
+			b.showExportPath(gormfunc.ExportFormatCSV)
+		case 1:
+			b.showExportPath(gormfunc.ExportFormatJSON)
+		case 2:
+			b.copyAllToClipboard()
+		}
+	})
+}
+
+// showExportPath asks for the file to export to and writes it on Enter
+func (b *TBrowse) showExportPath(format string) {
+	var input *tview.InputField
+	input = tview.NewInputField().SetText(b.Table.Name + "." + format).
+		SetDoneFunc(func(key tcell.Key) {
+			BrowseSubitemsFlex.RemoveItem(input)
+			statefunc.App.SetRoot(statefunc.RunFlexLevel0, true)
+			if key == tcell.KeyEnter && input.GetText() != "" {
+				b.exportToFile(input.GetText(), format)
+			}
+		})
+	input.SetLabel(i18nfunc.T("prompt.export_path", nil))
+	input.SetTitle("BROWSEEXPORT")
+	inputfunc.ApplyInputTheme(input)
+	BrowseSubitemsFlex.AddItem(input, 0, 1, true)
+	statefunc.App.SetRoot(BrowseSubitemsFlex, true)
+}
+
+func (b *TBrowse) exportToFile(path, format string) {
+	err := b.Table.ExportFile(path, format, b.exportColumns())
+	if err != nil {
+		Message(i18nfunc.T("error.export_failed", map[string]interface{}{
+			"Path":  path,
+			"Error": err.Error(),
+		}))
+		return
+	}
+	Message(i18nfunc.T("dialog.export_done", map[string]interface{}{
+		"Count": b.exportRowCount(),
+		"Path":  path,
+	}))
+}
+
+// copyAllToClipboard copies the current filtered view as CSV to the clipboard
+func (b *TBrowse) copyAllToClipboard() {
+	var buf bytes.Buffer
+	err := b.Table.WriteCSV(&buf, b.exportColumns())
+	if err == nil {
+		err = clipboard.WriteAll(buf.String())
+	}
+	if err != nil {
+		Message(i18nfunc.T("error.clipboard_failed", map[string]interface{}{
+			"Error": err.Error(),
+		}))
+		return
+	}
+	Message(i18nfunc.T("dialog.copy_done", map[string]interface{}{
+		"Count": b.exportRowCount(),
+	}))
+}
@@ -0,0 +1,147 @@
+package uifunc
+
+import (
+	"gotulua/errorhandlefunc"
+	"gotulua/i18nfunc"
+	"gotulua/inputfunc"
+	"gotulua/keymapfunc"
+	"gotulua/statefunc"
+
+	"github.com/Shopify/go-lua"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// filterCell is the reference of a filter row cell, it keeps the field filtered by the cell
+type filterCell struct {
+	field TBrowseField
+}
+
+// headerRows returns the number of rows above the first data row
+func (b *TBrowse) headerRows() int {
+	if b.showFilterRow {
+		return b.captionRow() + 2
+	}
+	return b.captionRow() + 1
+}
+
+// filterRow returns the row of the filter cells, right under the column captions
+func (b *TBrowse) filterRow() int {
+	return b.captionRow() + 1
+}
+
+// filterFields returns the fields of the filter row cells in column order
+func (b *TBrowse) filterFields() []TBrowseField {
+	if len(b.Fields) > 0 {
+		return b.shownFields
+	}
+	var fields []TBrowseField
+	for _, col := range b.columns() {
+		fields = append(fields, TBrowseField{Name: col, Caption: col, IsTableField: true})
+	}
+	return fields
+}
+
+// fillFilterRow shows the filters of the columns in the filter row.
+// Columns that are not table fields can not be filtered and get an empty cell.
+func (b *TBrowse) fillFilterRow() {
+	if !b.showFilterRow {
+		return
+	}
+	for i, field := range b.filterFields() {
+		cell := newFieldCell(field, "").SetSelectable(false)
+		if field.IsTableField {
+			cell.SetText(b.Filters[field.Name]).SetSelectable(true).
+				SetTextColor(tcell.ColorYellow).SetReference(filterCell{field: field})
+		}
+		b.TableView.SetCell(b.filterRow(), i, cell)
+	}
+}
+
+// isFilterRowSelected reports whether the selection is in the filter row
+func (b *TBrowse) isFilterRowSelected() bool {
+	if !b.showFilterRow {
+		return false
+	}
+	row, _ := b.TableView.GetSelection()
+	return row == b.filterRow()
+}
+
+// selectedFilterField returns the field of the selected filter cell, nil if there is none
+func (b *TBrowse) selectedFilterField() *TBrowseField {
+	row, column := b.TableView.GetSelection()
+	cell := b.TableView.GetCell(row, column)
+	if cell == nil {
+		return nil
+	}
+	ref, ok := cell.GetReference().(filterCell)
+	if !ok {
+		return nil
+	}
+	return &ref.field
+}
+
+// filterRowInput handles the keys pressed in the filter row. Enter, the filter key or typing
+// edit the filter of the column, the delete key clears it. Returns true if the key was handled.
+func (b *TBrowse) filterRowInput(event *tcell.EventKey) bool {
+	field := b.selectedFilterField()
+	if field == nil {
+		return false
+	}
+	switch {
+	case event.Key() == tcell.KeyEnter || keymapfunc.Matches(keymapfunc.BrowseFilter, event):
+		b.editRowFilter(*field, b.Filters[field.Name])
+	case keymapfunc.Matches(keymapfunc.BrowseDeleteRow, event):
+		b.applyRowFilter(*field, "")
+	case event.Key() == tcell.KeyRune && event.Modifiers()&(tcell.ModAlt|tcell.ModCtrl) == 0:
+		b.editRowFilter(*field, string(event.Rune()))
+	default:
+		return false
+	}
+	return true
+}
+
+// editRowFilter shows an input for the filter of the field starting with the text
+func (b *TBrowse) editRowFilter(field TBrowseField, text string) {
+	var input *tview.InputField
+	input = tview.NewInputField().SetText(text).
+		SetDoneFunc(func(key tcell.Key) {
+			BrowseSubitemsFlex.RemoveItem(input)
+			statefunc.App.SetRoot(statefunc.RunFlexLevel0, true)
+			if key == tcell.KeyEnter {
+				b.applyRowFilter(field, input.GetText())
+			}
+		})
+	input.SetLabel(field.Caption)
+	input.SetTitle("BROWSEFILTER")
+	inputfunc.ApplyInputTheme(input)
+	BrowseSubitemsFlex.AddItem(input, 0, 1, true)
+	statefunc.App.SetRoot(BrowseSubitemsFlex, true)
+}
+
+// applyRowFilter sets the filter of the field and shows the rows matching the filters of all columns
+func (b *TBrowse) applyRowFilter(field TBrowseField, filter string) {
+	_, column := b.TableView.GetSelection()
+	b.Filters[field.Name] = filter
+	b.Table.SetFilter(field.Name, filter)
+	b.refreshBrowse(true)
+	b.TableView.Select(b.filterRow(), column)
+}
+
+// SetShowFilterRow sets whether the browse shows a row under the header to filter every column.
+// Lua: browse:SetShowFilterRow(show), call it before the browse is shown.
+func SetShowFilterRow(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "SetShowFilterRow",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	browse, ok := L.ToUserData(1).(*TBrowse)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.first_argument_not_browse", nil), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	browse.showFilterRow = L.ToBoolean(2)
+	return 0
+}
@@ -0,0 +1,58 @@
+package uifunc
+
+import (
+	"gotulua/gormfunc"
+	"gotulua/statefunc"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// typeFilter types the text into the filter cell of the column and confirms it with Enter
+func typeFilter(t *testing.T, b *TBrowse, column int, text string) {
+	t.Helper()
+	b.TableView.Select(b.filterRow(), column)
+	pressKey(b, tcell.KeyRune, []rune(text)[0])
+	input, ok := statefunc.App.GetFocus().(*tview.InputField)
+	if !ok {
+		t.Fatalf("no filter input was shown, focus on %T", statefunc.App.GetFocus())
+	}
+	if got := input.GetText(); got != string([]rune(text)[0]) {
+		t.Errorf("the filter input starts with %q, want the typed character", got)
+	}
+	input.SetText(text)
+	input.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), func(p tview.Primitive) { statefunc.App.SetFocus(p) })
+}
+
+func TestFilterRowCombinesTheColumnFilters(t *testing.T) {
+	L, db := newTestState(t)
+	if _, err := gormfunc.Exec(db, "CREATE TABLE pets (id INTEGER PRIMARY KEY, kind TEXT, color TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+	for _, pet := range [][2]string{{"cat", "black"}, {"dog", "black"}, {"cat", "white"}, {"cat", "black"}} {
+		if _, err := gormfunc.Exec(db, "INSERT INTO pets (kind, color) VALUES (?, ?)", pet[0], pet[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b := &TBrowse{Table: gormfunc.OpenTable(db, "pets"), Filters: map[string]string{}, NewRowNum: -1, showFilterRow: true}
+	b.addField(L, "n::kind;c::Kind|n::color;c::Color")
+	showTestBrowse(L, b)
+
+	typeFilter(t, b, 0, "cat")
+	if count := len(b.Table.Rows.Rows); count != 3 {
+		t.Errorf("the kind filter left %d rows, want 3", count)
+	}
+	typeFilter(t, b, 1, "black")
+	if count := len(b.Table.Rows.Rows); count != 2 {
+		t.Errorf("the two filters left %d rows, want 2", count)
+	}
+	for row := b.headerRows(); row < b.TableView.GetRowCount(); row++ {
+		if kind, color := b.TableView.GetCell(row, 0).Text, b.TableView.GetCell(row, 1).Text; kind != "cat" || color != "black" {
+			t.Errorf("row %d shows %s %s, want black cats only", row, color, kind)
+		}
+	}
+	if got := b.TableView.GetCell(b.filterRow(), 1).Text; got != "black" {
+		t.Errorf("the filter cell shows %q, want the filter", got)
+	}
+}
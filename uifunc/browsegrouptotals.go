@@ -0,0 +1,180 @@
+package uifunc
+
+import (
+	"gotulua/errorhandlefunc"
+	"gotulua/i18nfunc"
+	"gotulua/numfunc"
+	"gotulua/typesfunc"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/Shopify/go-lua"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// hasGroupTotals reports whether the browse shows group totals. The browse is read-only then.
+func (b *TBrowse) hasGroupTotals() bool {
+	return b.groupField != ""
+}
+
+// rowOfPos returns the view row of the loaded table row at the position
+func (b *TBrowse) rowOfPos(pos int) int {
+	if b.groupRows != nil && pos >= 0 && pos < len(b.groupRows) {
+		return b.groupRows[pos]
+	}
+	return pos + b.headerRows()
+}
+
+// posOfRow returns the position of the loaded table row shown in the view row, -1 for a total row
+func (b *TBrowse) posOfRow(row int) int {
+	if b.groupRows == nil {
+		return row - b.headerRows()
+	}
+	i := sort.SearchInts(b.groupRows, row)
+	if i < len(b.groupRows) && b.groupRows[i] == row {
+		return i
+	}
+	return -1
+}
+
+// loadGroupedRows fills the browse view with the rows found by Table.Find like loadRows, with a
+// subtotal row after every group of rows with the same value of the group field and a grand total
+// row at the end. The rows are added at once, as the totals need all of them.
+func (b *TBrowse) loadGroupedRows(L *lua.State, goTop bool) {
+	b.loadGen.Add(1) // Stops a load still running in the background
+	b.setLoading("")
+	rows := b.Table.Rows.Rows
+	fields := b.filterFields()
+	subtotals := make([]float64, len(fields))
+	totals := make([]float64, len(fields))
+	b.groupRows = make([]int, len(rows))
+	row := b.headerRows()
+	group := ""
+	for i := range rows {
+		b.Table.Rows.Pos = i
+		text := b.groupText()
+		if i > 0 && text != group {
+			b.setTotalRow(row, i18nfunc.T("browse.subtotal", map[string]interface{}{"Group": group}), fields, subtotals)
+			clear(subtotals)
+			row++
+		}
+		group = text
+		b.groupRows[i] = row
+		b.initRow(L)
+		b.addToTotals(fields, subtotals, totals)
+		row++
+	}
+	if len(rows) > 0 {
+		b.setTotalRow(row, i18nfunc.T("browse.subtotal", map[string]interface{}{"Group": group}), fields, subtotals)
+		b.setTotalRow(row+1, i18nfunc.T("browse.grand_total", nil), fields, totals)
+	}
+	if goTop {
+		b.Table.ScrollToBeginning()
+		b.TableView.ScrollToBeginning()
+	} else if row, _ := b.TableView.GetSelection(); b.posOfRow(row) >= 0 {
+		b.Table.ScrollToRow(min(b.posOfRow(row), len(rows)-1))
+	}
+}
+
+// groupText returns the value of the group field in the current row as the browse shows it
+func (b *TBrowse) groupText() string {
+	s, _, ok := b.fieldText(&TBrowseField{Name: b.groupField, IsTableField: true})
+	if !ok {
+		return ""
+	}
+	return s
+}
+
+// isTotalField reports whether the column of the field gets totals
+func (b *TBrowse) isTotalField(field TBrowseField) bool {
+	if !field.IsTableField {
+		return false
+	}
+	tp := b.Table.GetFieldType(field.Name)
+	return tp == typesfunc.TypeInteger || tp == typesfunc.TypeReal
+}
+
+// addToTotals adds the numeric values of the current row to the subtotals and the totals
+func (b *TBrowse) addToTotals(fields []TBrowseField, subtotals, totals []float64) {
+	r := b.Table.GetCurrentRecord()
+	for j, field := range fields {
+		if !b.isTotalField(field) {
+			continue
+		}
+		v := r[field.Name]
+		if vp, ok := v.(*interface{}); ok {
+			v = *vp
+		}
+		if n, ok := toFloat(v); ok {
+			subtotals[j] += n
+			totals[j] += n
+		}
+	}
+}
+
+// setTotalRow shows a row of totals that can not be selected. The caption goes to the first
+// column without totals, if there is one.
+func (b *TBrowse) setTotalRow(row int, caption string, fields []TBrowseField, sums []float64) {
+	for j, field := range fields {
+		text := ""
+		if b.isTotalField(field) {
+			text = formatTotal(sums[j], b.Table.GetFieldType(field.Name) == typesfunc.TypeInteger)
+		} else if caption != "" {
+			text, caption = caption, ""
+		}
+		b.TableView.SetCell(row, j, tview.NewTableCell(text).SetSelectable(false).SetAttributes(tcell.AttrBold))
+	}
+}
+
+// formatTotal formats a total like the values of its column
+func formatTotal(v float64, isInteger bool) string {
+	if numfunc.IsNumberFormatSet() {
+		return numfunc.FormatNumberToUser(v, isInteger)
+	}
+	if isInteger {
+		return strconv.FormatInt(int64(v), 10)
+	}
+	// Sums of decimal fractions are not exact, 0.1 + 0.2 should show 0.3
+	return strconv.FormatFloat(math.Round(v*1e9)/1e9, 'f', -1, 64)
+}
+
+// SetGroupTotals shows a subtotal row after every group of rows with the same value of the field
+// and a grand total row at the end, summing the integer and real columns. Order the table by the
+// field, so its groups are together. The browse is read-only while it shows totals.
+// Lua: browse:SetGroupTotals(field), nil removes the totals.
+func SetGroupTotals(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "SetGroupTotals",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	browse, ok := L.ToUserData(1).(*TBrowse)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.first_argument_not_browse", nil), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	field := ""
+	if !L.IsNil(2) {
+		if field, ok = L.ToString(2); !ok {
+			errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+				"Name": "field",
+			}), errorhandlefunc.ErrorTypeScript, true)
+			return 0
+		}
+		if browse.Table.GetFieldType(field) == "" {
+			errorhandlefunc.ThrowError(i18nfunc.T("error.field_not_found", map[string]interface{}{
+				"Name": field,
+			}), errorhandlefunc.ErrorTypeScript, true)
+			return 0
+		}
+	}
+	browse.groupField = field
+	browse.groupRows = nil
+	if browse.TableView != nil {
+		browse.renderColumns()
+	}
+	return 0
+}
@@ -0,0 +1,87 @@
+package uifunc
+
+import (
+	"gotulua/gormfunc"
+	"gotulua/i18nfunc"
+	"testing"
+
+	"github.com/Shopify/go-lua"
+)
+
+// setGroupTotals calls SetGroupTotals with the field, "" removes the totals
+func setGroupTotals(L *lua.State, b *TBrowse, field string) {
+	L.PushUserData(b)
+	if field == "" {
+		L.PushNil()
+	} else {
+		L.PushString(field)
+	}
+	SetGroupTotals(L)
+	L.SetTop(0)
+}
+
+func TestGroupTotalsAddSubtotalAndGrandTotalRows(t *testing.T) {
+	L, db := newTestState(t)
+	if _, err := gormfunc.Exec(db, "CREATE TABLE sales (id INTEGER PRIMARY KEY, cat TEXT, qty INTEGER, price REAL)"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gormfunc.Exec(db, `INSERT INTO sales (cat, qty, price) VALUES
+		('a', 1, 0.1), ('a', 2, 0.2), ('b', 3, 1.5), ('b', 4, 2.5), ('c', 5, 10)`); err != nil {
+		t.Fatal(err)
+	}
+	b := &TBrowse{Table: gormfunc.OpenTable(db, "sales"), Filters: map[string]string{}, NewRowNum: -1}
+	for _, f := range []string{"n::cat;c::Cat", "n::qty;c::Qty", "n::price;c::Price"} {
+		b.addField(L, f)
+	}
+	showTestBrowse(L, b)
+	first := b.headerRows()
+
+	setGroupTotals(L, b, "cat")
+	if !b.hasGroupTotals() {
+		t.Fatal("the browse shows no totals")
+	}
+	subtotal := func(group string) string {
+		return i18nfunc.T("browse.subtotal", map[string]interface{}{"Group": group})
+	}
+	want := [][]string{
+		{"a", "1", "0.1"},
+		{"a", "2", "0.2"},
+		{subtotal("a"), "3", "0.3"},
+		{"b", "3", "1.5"},
+		{"b", "4", "2.5"},
+		{subtotal("b"), "7", "4"},
+		{"c", "5", "10"},
+		{subtotal("c"), "5", "10"},
+		{i18nfunc.T("browse.grand_total", nil), "15", "14.3"},
+	}
+	for i, cells := range want {
+		for col, text := range cells {
+			if got := b.TableView.GetCell(first+i, col).Text; got != text {
+				t.Errorf("row %d column %d shows %q, want %q", i, col, got, text)
+			}
+		}
+	}
+	if !b.TableView.GetCell(first+2, 0).NotSelectable {
+		t.Error("a subtotal row can be selected")
+	}
+	if b.posOfRow(first+2) != -1 {
+		t.Error("a subtotal row maps to a table row")
+	}
+
+	// The first row of group b comes after the subtotal of group a
+	b.TableView.Select(first+3, 0)
+	if pos := b.Table.Rows.Pos; pos != 2 {
+		t.Errorf("the table stands on row %d, want 2", pos)
+	}
+
+	setGroupTotals(L, b, "")
+	if b.hasGroupTotals() {
+		t.Fatal("the totals are still on")
+	}
+	if got := b.TableView.GetCell(first+2, 0).Text; got != "b" {
+		t.Errorf("the third row shows %q after removing the totals, want b", got)
+	}
+	if got := b.TableView.GetRowCount(); got != first+5 {
+		t.Errorf("the view has %d rows, want %d", got, first+5)
+	}
+}
@@ -0,0 +1,74 @@
+package uifunc
+
+import (
+	"strings"
+	"time"
+)
+
+// quickJumpTimeout is the pause after which typing starts a new quick-jump prefix
+const quickJumpTimeout = time.Second
+
+// findPrefixRow returns the first row from start on, wrapping around to the first data row,
+// whose text starts with the prefix ignoring case. The rows before first are the header.
+// Returns -1 if none matches.
+func findPrefixRow(rowCount, first, start int, prefix string, cellText func(row int) string) int {
+	if rowCount <= first || prefix == "" {
+		return -1
+	}
+	prefix = strings.ToLower(prefix)
+	if start < first || start >= rowCount {
+		start = first
+	}
+	for i := 0; i < rowCount-first; i++ {
+		row := first + (start-first+i)%(rowCount-first)
+		if strings.HasPrefix(strings.ToLower(strings.TrimSpace(cellText(row))), prefix) {
+			return row
+		}
+	}
+	return -1
+}
+
+// tableNavigationRunes are the keys tview.Table moves the selection with
+const tableNavigationRunes = "gGjkhl"
+
+// isTableNavigationRune reports whether the character is one of the navigation keys of the table
+func isTableNavigationRune(ch rune) bool {
+	return strings.ContainsRune(tableNavigationRunes, ch)
+}
+
+// quickJump adds the typed character to the prefix and selects the next row
+// whose value in the current column starts with it
+func (b *TBrowse) quickJump(ch rune) {
+	now := time.Now()
+	if now.Sub(b.jumpTime) > quickJumpTimeout {
+		b.jumpPrefix = ""
+	}
+	b.jumpTime = now
+	b.jumpPrefix += string(ch)
+	row, column := b.TableView.GetSelection()
+	start := row
+	if len([]rune(b.jumpPrefix)) == 1 {
+		// A new prefix looks for the next match, a longer one may stay on the current row
+		start = row + 1
+	}
+	found := findPrefixRow(b.TableView.GetRowCount(), b.headerRows(), start, b.jumpPrefix, func(r int) string {
+		cell := b.TableView.GetCell(r, column)
+		if cell == nil || b.posOfRow(r) < 0 { // Total rows can not be selected
+			return ""
+		}
+		return cell.Text
+	})
+	if found > 0 && found != row {
+		b.TableView.Select(found, column)
+	}
+}
+
+// isJumping reports whether a quick-jump prefix is being typed
+func (b *TBrowse) isJumping() bool {
+	return b.jumpPrefix != "" && time.Since(b.jumpTime) <= quickJumpTimeout
+}
+
+// resetQuickJump forgets the typed prefix
+func (b *TBrowse) resetQuickJump() {
+	b.jumpPrefix = ""
+}
@@ -0,0 +1,37 @@
+package uifunc
+
+import (
+	"gotulua/statefunc"
+	"gotulua/syncfunc"
+
+	"github.com/Shopify/go-lua"
+)
+
+// openLookup shows the lookup browse of the field in place of the browse. The screen under it
+// goes on the visual stack and the selected cell is kept, so closeLookup comes back to both.
+func (b *TBrowse) openLookup(L *lua.State, field TBrowseField) {
+	lookup := field.LookupBrowse
+	syncfunc.BrowseChId = -1
+	b.NearLookup = true
+	lookup.Show(L) // Initialize lookup browse
+	lookup.setLookupBrowseDest(b, &field)
+	lookup.lookupFromRow, lookup.lookupFromColumn = b.TableView.GetSelection()
+	statefunc.PushVisual(statefunc.RunFlexLevel0)
+	showBrowseLookup(lookup.TableView)
+}
+
+// closeLookup hides the lookup browse and shows the screen it was opened from again,
+// with the focus on the browse it was opened from and the cell selected then. Setting the
+// root alone would focus the first browse of the screen, not always the one that opened it.
+func (b *TBrowse) closeLookup() {
+	BrowseSubitemsFlex.Clear()
+	statefunc.ShowPreviousVisual()
+	parent := b.LookupBrowseDest
+	if parent == nil || parent.TableView == nil {
+		return
+	}
+	if row, column := parent.TableView.GetSelection(); row != b.lookupFromRow || column != b.lookupFromColumn {
+		parent.TableView.Select(b.lookupFromRow, b.lookupFromColumn)
+	}
+	statefunc.App.SetFocus(parent.TableView)
+}
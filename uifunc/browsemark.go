@@ -0,0 +1,131 @@
+package uifunc
+
+import (
+	"fmt"
+	"gotulua/errorhandlefunc"
+	"gotulua/i18nfunc"
+	"gotulua/statefunc"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// toggleMark marks the selected row for a bulk change, or unmarks it, and goes to the next row
+func (b *TBrowse) toggleMark() {
+	if b.isLookup || b.isNewRowMode() || b.isLoading() || b.hasGroupTotals() || b.Table.Rows == nil {
+		return
+	}
+	row, column := b.TableView.GetSelection()
+	if row < b.headerRows() {
+		return
+	}
+	b.Table.Rows.Pos = row - b.headerRows()
+	id := b.getRowId()
+	if id == 0 {
+		return
+	}
+	if b.marked == nil {
+		b.marked = make(map[int64]bool)
+	}
+	if b.marked[id] {
+		delete(b.marked, id)
+	} else {
+		b.marked[id] = true
+	}
+	b.initRow(statefunc.L)
+	if row < b.TableView.GetRowCount()-1 {
+		b.TableView.Select(row+1, column)
+	}
+}
+
+// isMarked reports whether the current row of the table is marked
+func (b *TBrowse) isMarked() bool {
+	return b.marked[b.getRowId()]
+}
+
+// showMark highlights the cells of the current row of the table if it is marked
+func (b *TBrowse) showMark() {
+	if !b.isMarked() {
+		return
+	}
+	row := b.rowOfPos(b.Table.Rows.Pos)
+	for col := range b.TableView.GetColumnCount() {
+		if cell := b.TableView.GetCell(row, col); cell != nil {
+			cell.SetTextColor(tcell.ColorYellow).SetAttributes(tcell.AttrBold)
+		}
+	}
+}
+
+// markedPositions returns the positions of the marked rows among the loaded rows
+func (b *TBrowse) markedPositions() []int {
+	if len(b.marked) == 0 || b.Table.Rows == nil {
+		return nil
+	}
+	var positions []int
+	for i, r := range b.Table.Rows.Rows {
+		if b.marked[recordId(r)] {
+			positions = append(positions, i)
+		}
+	}
+	return positions
+}
+
+// showBulkSet asks for a value of the field of the selected column and sets it in all marked rows
+func (b *TBrowse) showBulkSet() {
+	if b.isLookup || b.isNewRowMode() || b.hasGroupTotals() {
+		return
+	}
+	positions := b.markedPositions()
+	if len(positions) == 0 {
+		Message(i18nfunc.T("dialog.bulk_set_no_marks", nil))
+		return
+	}
+	field := b.getCurrentField()
+	if field == nil || !field.IsTableField || !field.CanEdit() {
+		return
+	}
+	caption := field.Caption
+	if caption == "" {
+		caption = field.Name
+	}
+	extType := b.Table.GetFieldType(field.Name)
+	label := i18nfunc.T("prompt.bulk_set", map[string]interface{}{
+		"Field": caption,
+		"Count": len(positions),
+	})
+	showBrowseEdit(label, "", extType, func(s string, key tcell.Key) {
+		defer statefunc.Pages.SwitchToPage("main")
+		if key != tcell.KeyEnter {
+			return
+		}
+		b.bulkSet(field, positions, s)
+	})
+}
+
+// bulkSet sets the value typed for the field in the rows at the positions and shows them again.
+// The marks are removed once the rows are changed.
+func (b *TBrowse) bulkSet(field *TBrowseField, positions []int, typed string) bool {
+	extType := b.Table.GetFieldType(field.Name)
+	result := typed
+	if result == "" {
+		result = fmt.Sprintf("%v", b.Table.GetDefaultValueForTheField(field.Name))
+	}
+	var ok bool
+	if result, ok = ungroupEditedNumber(field.Caption, extType, typed, result); !ok {
+		return false
+	}
+	if result, ok = editedValueToInternal(field.Caption, extType, typed, result); !ok {
+		return false
+	}
+	if !b.Table.UpdateRows(positions, field.Name, result) {
+		errorhandlefunc.ThrowError(statefunc.GetLastErrorText(), errorhandlefunc.ErrorTypeData, false)
+		return false
+	}
+	b.marked = nil
+	pos := b.Table.Rows.Pos
+	for _, p := range positions {
+		b.Table.Rows.Pos = p
+		b.initRow(statefunc.L)
+	}
+	b.Table.Rows.Pos = pos
+	return true
+}
@@ -0,0 +1,49 @@
+package uifunc
+
+import (
+	"gotulua/statefunc"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func TestBulkSetChangesTheMarkedRowsOnly(t *testing.T) {
+	L, db := newTestState(t)
+	b := newTestBrowse(t, L, db, "names", false, "a", "b", "c")
+	b.Fields[0].IsEditable = true
+	showTestBrowse(L, b)
+	first := b.headerRows()
+
+	b.TableView.Select(first, 0)
+	b.toggleMark() // Marks a and goes to b
+	b.TableView.Select(first+2, 0)
+	b.toggleMark()
+	if got := b.markedPositions(); len(got) != 2 || got[0] != 0 || got[1] != 2 {
+		t.Fatalf("the marked positions are %v, want [0 2]", got)
+	}
+
+	b.showBulkSet()
+	input, ok := statefunc.App.GetFocus().(*tview.InputField)
+	if !ok {
+		t.Fatalf("no input was shown, focus on %T", statefunc.App.GetFocus())
+	}
+	input.SetText("x")
+	input.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), func(p tview.Primitive) {})
+
+	var saved []string
+	if err := db.Raw("SELECT a FROM names ORDER BY id").Scan(&saved).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(saved) != 3 || saved[0] != "x" || saved[1] != "b" || saved[2] != "x" {
+		t.Errorf("the table holds %q, want [x b x]", saved)
+	}
+	for i, want := range []string{"x", "b", "x"} {
+		if got := b.TableView.GetCell(first+i, 0).Text; got != want {
+			t.Errorf("row %d shows %q, want %q", i, got, want)
+		}
+	}
+	if len(b.marked) != 0 {
+		t.Error("the marks were kept after the bulk change")
+	}
+}
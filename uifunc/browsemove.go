@@ -0,0 +1,38 @@
+package uifunc
+
+import (
+	"gotulua/errorhandlefunc"
+	"gotulua/statefunc"
+)
+
+// moveRow moves the selected row one place up or down in the order of the table set with
+// SetOrderField. The two rows that change places are shown again and the selection follows the row.
+func (b *TBrowse) moveRow(up bool) {
+	if b.isLookup || b.isNewRowMode() || b.isLoading() || b.hasGroupTotals() || !b.Table.HasOrderField() || b.Table.Rows == nil {
+		return
+	}
+	row, column := b.TableView.GetSelection()
+	if row < b.headerRows() {
+		return
+	}
+	b.Table.Rows.Pos = row - b.headerRows()
+	var ok bool
+	if up {
+		ok = b.Table.MoveUp()
+	} else {
+		ok = b.Table.MoveDown()
+	}
+	if !ok {
+		errorhandlefunc.ThrowError(statefunc.GetLastErrorText(), errorhandlefunc.ErrorTypeData, false)
+		return
+	}
+	newRow := b.Table.Rows.Pos + b.headerRows()
+	if newRow == row {
+		return
+	}
+	b.Table.Rows.Pos = row - b.headerRows()
+	b.initRow(statefunc.L)
+	b.Table.Rows.Pos = newRow - b.headerRows()
+	b.initRow(statefunc.L)
+	b.TableView.Select(newRow, column)
+}
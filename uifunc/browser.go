@@ -8,12 +8,17 @@ import (
 	"gotulua/gormfunc"
 	"gotulua/i18nfunc"
 	"gotulua/inputfunc"
+	"gotulua/keymapfunc"
+	"gotulua/numfunc"
 	"gotulua/statefunc"
 	"gotulua/syncfunc"
 	"gotulua/timefunc"
 	"gotulua/typesfunc"
 	"reflect"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/Shopify/go-lua"
 	"github.com/gdamore/tcell/v2"
@@ -32,6 +37,10 @@ import (
 //   - IsLookup: Indicates if the field is a lookup field.
 //   - LookupTable: Pointer to the TBrowse structure used for lookup fields.
 //   - LookupFunc: The name of the function used to perform lookup operations for this field.
+//   - ViewRoles: The roles that may see the field, empty for every role.
+//   - EditRoles: The roles that may edit the field, empty for every role.
+//   - Format: The name of a function showing the value of a table field, if applicable.
+//   - Hidden: Indicates if the field was hidden by the script with SetColumnVisible.
 type TBrowseField struct {
 	Name         string
 	Caption      string
@@ -43,6 +52,11 @@ type TBrowseField struct {
 	LookupBrowse *TBrowse    // Pointer to the TBrowse for lookup fields
 	LookupFunc   string
 	ExtraType    string //Set if the field type is kind of Date/Time/DateTime/Boolean. Allowed values "", "D", "T", "DT", "B"
+	ViewRoles    []string
+	EditRoles    []string
+	Format       string // Function formatting the shown value, storage and editing keep the value
+	Hidden       bool
+	MaxWidth     int // Maximum number of characters shown in the column, 0 if not limited
 }
 
 type TButton struct {
@@ -58,11 +72,30 @@ type TBrowse struct {
 	TableView        *tview.Table   // Pointer to the tview.Table for displaying the browse view
 	NewRowNum        int            // Counter for new rows, initialized to -1
 	Fields           []TBrowseField // List of fields to display in the browse view
+	shownFields      []TBrowseField // Fields the current role may see, set when the browse is shown
 	Buttons          []TButton      // List of buttons to display in the browse view
 	isLookup         bool           // IsLookup indicates whether the current operation is a lookup action.
 	lastRowVisited   int
 	NearLookup       bool
 	Filters          map[string]string
+	loadGen          atomic.Int64 // Incremented on every load, stops the outdated background loads
+	loading          string       // Progress of the background load shown in the info bar, "" when done
+	onOpen           string       // Lua function called when the browse is shown
+	onClose          string       // Lua function called when the browse is dismissed
+	isOpen           bool
+	hidePrimaryKey   bool                  // Leave the primary key out when the columns are rendered without fields
+	jumpPrefix       string                // Characters typed for the quick jump
+	jumpTime         time.Time             // When the last quick jump character was typed
+	rowTagFunc       string                // Lua function computing the tag of every row
+	rowTags          map[int64]interface{} // Row tags by primary key
+	showFilterRow    bool                  // Show the filter row under the header
+	fixedColumns     int                   // Leading columns kept visible when scrolling right
+	columnGroups     []columnGroup         // Captions shown above ranges of columns
+	marked           map[int64]bool        // Rows marked for a bulk change, by primary key
+	groupField       string                // Field whose groups of rows get subtotals, read-only browse
+	groupRows        []int                 // View rows of the loaded table rows when totals are shown
+	lookupFromRow    int                   // Cell of the browse selected when this lookup was opened from it
+	lookupFromColumn int
 }
 
 // BrowseTableNew creates a new TBrowse instance and adds it to the Lua state.
@@ -128,6 +161,140 @@ func AddButton(L *lua.State) int {
 	return 1
 }
 
+// PressButton runs the function of the browse button with the caption, as clicking it would.
+// Lua: browse:PressButton(caption), returns false if the browse has no such button.
+func PressButton(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "PressButton",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	browse, ok := L.ToUserData(1).(*TBrowse)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.first_argument_not_browse", nil), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	caption, ok := L.ToString(2)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.second_argument_not_string", nil), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	button := browse.findButton(caption)
+	if button == nil {
+		L.PushBoolean(false)
+		return 1
+	}
+	browse.onButtonPress(button.Function)
+	L.PushBoolean(true)
+	return 1
+}
+
+// SetOnOpen sets the Lua function called with the table each time the browse is shown.
+func SetOnOpen(L *lua.State) int {
+	return setBrowseHook(L, "SetOnOpen")
+}
+
+// SetOnClose sets the Lua function called with the table when the browse is dismissed.
+func SetOnClose(L *lua.State) int {
+	return setBrowseHook(L, "SetOnClose")
+}
+
+func setBrowseHook(L *lua.State, name string) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": name,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	browse, ok := L.ToUserData(1).(*TBrowse) // Get the browse from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.first_argument_not_browse", nil), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	funcName, ok := L.ToString(2) // Get the function name from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.second_argument_not_string", nil), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	if name == "SetOnOpen" {
+		browse.onOpen = funcName
+	} else {
+		browse.onClose = funcName
+	}
+	return 0
+}
+
+// SetShowPrimaryKey sets whether the primary key column is shown when the browse has no fields.
+func SetShowPrimaryKey(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "SetShowPrimaryKey",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	browse, ok := L.ToUserData(1).(*TBrowse) // Get the browse from Lua
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.first_argument_not_browse", nil), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	browse.hidePrimaryKey = !L.ToBoolean(2)
+	return 0
+}
+
+// SetFixedColumns sets how many leading columns stay visible when the browse scrolls right.
+// Lua: browse:SetFixedColumns(n), 0 lets all columns scroll.
+func SetFixedColumns(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "SetFixedColumns",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	browse, ok := L.ToUserData(1).(*TBrowse)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.first_argument_not_browse", nil), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	n, ok := L.ToInteger(2)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_integer", map[string]interface{}{
+			"Name": "n",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	browse.fixedColumns = max(n, 0)
+	if browse.TableView != nil {
+		browse.TableView.SetFixed(browse.headerRows(), browse.fixedColumns)
+	}
+	return 0
+}
+
+// columns returns the table columns shown when the browse has no fields
+func (b *TBrowse) columns() []string {
+	if !b.hidePrimaryKey {
+		return b.Table.Columns
+	}
+	var cols []string
+	for _, col := range b.Table.Columns {
+		if col != gormfunc.PrimaryKeyField {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+// close runs the onClose function once for every time the browse was shown
+func (b *TBrowse) close(L *lua.State) {
+	if !b.isOpen {
+		return
+	}
+	b.isOpen = false
+	if b.onClose != "" {
+		b.runFieldFunction(L, b.onClose)
+	}
+}
+
 // addButton adds a new button to the TBrowse instance.
 // This button will use a Lua function (specified by functionValue) to compute its value dynamically.
 //
@@ -148,6 +315,16 @@ func (b *TBrowse) addButton(L *lua.State, caption string, functionValue string)
 	return 1
 }
 
+// findButton returns the button with the caption, nil if there is none
+func (b *TBrowse) findButton(caption string) *TButton {
+	for i := range b.Buttons {
+		if b.Buttons[i].Caption == caption {
+			return &b.Buttons[i]
+		}
+	}
+	return nil
+}
+
 // addTableField adds a new table-based field to the TBrowse instance.
 // This field will use a table field (specified by fieldName) to display its value.
 //
@@ -174,6 +351,7 @@ func (b *TBrowse) addTableField(L *lua.State, fieldName, caption string, isEdita
 		IsEditable:   isEditable,
 	}
 	b.Fields = append(b.Fields, field) // Add the field to the browse view
+	b.Table.SetFieldCaption(fieldName, caption)
 	return 1
 }
 
@@ -226,6 +404,10 @@ func AddField(L *lua.State) int {
 //   - f: function name for computed fields (optional)
 //   - e: editable flag ("true" or "false", optional)
 //   - t: extra type information (optional)
+//   - roles: comma separated roles that may see the field (optional)
+//   - eroles: comma separated roles that may edit the field (optional)
+//   - fmt: function name formatting the shown value of a table field (optional)
+//   - w: maximum number of characters shown in the column, the full value is shown by browse.view_value (optional)
 //
 // If a function is specified, AddFuncField is called; otherwise, AddTableField is used.
 // Returns 1 to indicate success.
@@ -233,7 +415,7 @@ func (b *TBrowse) addField(L *lua.State, description string) int {
 	//n::Name;c::Pet Name;f::GetPetName|n::Vaccine;c::Vaccine Used;e::true|n::Date;c::Vaccination Date;e::true;t::D
 	fields := strings.Split(description, "|")
 	for _, field := range fields {
-		var name, caption, function, editable, extraType string
+		var name, caption, function, editable, extraType, viewRoles, editRoles, format, width string
 		parts := strings.Split(field, ";")
 		for _, part := range parts {
 			params := strings.Split(part, "::")
@@ -249,6 +431,14 @@ func (b *TBrowse) addField(L *lua.State, description string) int {
 					editable = params[1]
 				case "t":
 					extraType = params[1]
+				case "roles":
+					viewRoles = params[1]
+				case "eroles":
+					editRoles = params[1]
+				case "fmt":
+					format = params[1]
+				case "w":
+					width = params[1]
 				}
 			}
 		}
@@ -258,6 +448,21 @@ func (b *TBrowse) addField(L *lua.State, description string) int {
 			} else {
 				b.addTableField(L, name, caption, editable == "true", extraType)
 			}
+			if f := b.findFieldByName(name); f != nil {
+				f.ViewRoles = parseRoles(viewRoles)
+				f.EditRoles = parseRoles(editRoles)
+				f.Format = format
+				if width != "" {
+					w, err := strconv.Atoi(width)
+					if err != nil || w < 0 {
+						errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_integer", map[string]interface{}{
+							"Name": "w",
+						}), errorhandlefunc.ErrorTypeScript, true)
+						return 0
+					}
+					f.MaxWidth = w
+				}
+			}
 		}
 	}
 	return 1
@@ -308,34 +513,36 @@ func (b *TBrowse) setLookupBrowseDest(bl *TBrowse, f *TBrowseField) {
 // Lua callbacks as needed. If not in lookup mode, the TableView is pushed as
 // Lua userdata for further manipulation.
 func (b *TBrowse) Show(L *lua.State) int {
-	b.TableView = tview.NewTable().SetBorders(true).SetSelectable(true, true).SetFixed(1, 0) // Set borders for the TableView
+	b.isOpen = true
+	if b.onOpen != "" {
+		b.runFieldFunction(L, b.onOpen)
+	}
+	b.TableView = tview.NewTable().SetBorders(true).SetSelectable(true, true).SetFixed(b.headerRows(), b.fixedColumns) // Set borders for the TableView
 	//b.TableView.SetBorder(true)                                               // Set a border around the TableView
 	//b.TableView.SetBorderPadding(1, 1, 1, 1)                                  //
 	b.TableView.SetTitle(b.Title) // Set the title for the TableView
+	b.shownFields = b.visibleFields()
 	if len(b.Fields) > 0 {
-		for i := range b.Fields {
-			b.TableView.SetCell(0, i, tview.NewTableCell(b.Fields[i].Caption).SetSelectable(false))
+		for i, field := range b.shownFields {
+			b.TableView.SetCell(b.captionRow(), i, newFieldCell(field, field.Caption).SetSelectable(false))
 		}
 	} else {
-		for i, col := range b.Table.Columns {
-			b.TableView.SetCell(0, i, tview.NewTableCell(col).SetSelectable(false)) // Set column headers
+		for i, col := range b.columns() {
+			b.TableView.SetCell(b.captionRow(), i, tview.NewTableCell(col).SetSelectable(false)) // Set column headers
 		}
 	}
-	if b.Table.Find() { // Find all rows in the table
-		for {
-			b.initRow(L)
-			if !b.Table.Next() { // Move to the next row
-				b.Table.ScrollToBeginning()     // Stand to the first row
-				b.TableView.ScrollToBeginning() // Scroll to the beginning of the table
-				break                           // Exit the loop if no more rows
-			}
-		}
+	b.fillColumnGroups()
+	b.fillFilterRow()
+	b.groupRows = nil
+	if rest, found := b.findRows(); found { // Find the rows of the table
+		b.loadRows(L, true, rest)
 	} else {
 		b.Table.Init()
 		b.initRow(L)
-		b.setNewRowMode(1) // Set NewRowNum to the next row index
+		b.setNewRowMode(b.headerRows()) // Set NewRowNum to the next row index
 		b.TableView.ScrollToBeginning()
 	}
+	b.TableView.Select(b.headerRows(), 0) // Start on the first row, not in the filter row
 	// In-place editing
 	b.TableView.SetSelectedFunc(func(row, column int) {
 		cell := b.TableView.GetCell(row, column)
@@ -354,10 +561,16 @@ func (b *TBrowse) Show(L *lua.State) int {
 				return // Field does not exist in the table
 			}
 		}
-		if !field.IsEditable {
-			return // Only allow editing for editable fields
+		if !field.CanEdit() || b.hasGroupTotals() {
+			return // Only allow editing for editable fields the current role may change
 		}
 		initial := cell.Text
+		if field.Format != "" && !b.isNewRowMode() {
+			// The value is edited as it is, not as the format function shows it
+			if text, _, ok := b.fieldText(&field); ok {
+				initial = text
+			}
+		}
 
 		extType := b.Table.GetFieldType(field.Name)
 
@@ -372,39 +585,25 @@ func (b *TBrowse) Show(L *lua.State) int {
 					if result == "" {
 						result = fmt.Sprintf("%v", b.Table.GetDefaultValueForTheField(field.Name))
 					}
+					if result, ok = ungroupEditedNumber(field.Caption, extType, s, result); !ok {
+						return
+					}
 					if b.isNewRowMode() {
+						inserted := b.isInsertedRow()
 						// If in new row mode, add a new row with the input value
 						if !b.Table.AddRow(field.Name, result) { // Add a new row to the table
 							return
 						}
+						if inserted {
+							// The saved record takes its place in the table order
+							b.clearNewRowMode()
+							b.refreshBrowse(false)
+							statefunc.Pages.SwitchToPage("main")
+							return
+						}
 					} else {
-						t := b.Table.GetFieldType(field.Name)
-						var err error
-						switch t {
-						case typesfunc.TypeDate:
-							result, err = timefunc.FormatDateTime(result, t, timefunc.ToInternalFormat)
-							if err != nil {
-								errorhandlefunc.ThrowError(err.Error(), errorhandlefunc.ErrorTypeData, false)
-								return
-							}
-						case typesfunc.TypeTime:
-							result, err = timefunc.FormatDateTime(result, t, timefunc.ToInternalFormat)
-							if err != nil {
-								errorhandlefunc.ThrowError(err.Error(), errorhandlefunc.ErrorTypeData, false)
-								return
-							}
-						case typesfunc.TypeDateTime:
-							result, err = timefunc.FormatDateTime(result, t, timefunc.ToInternalFormat)
-							if err != nil {
-								errorhandlefunc.ThrowError(err.Error(), errorhandlefunc.ErrorTypeData, false)
-								return
-							}
-						case typesfunc.TypeBoolean:
-							result, err = boolfunc.FormatBool(result, boolfunc.ToInternalFormat)
-							if err != nil {
-								errorhandlefunc.ThrowError(err.Error(), errorhandlefunc.ErrorTypeData, false)
-								return
-							}
+						if result, ok = editedValueToInternal(field.Caption, b.Table.GetFieldType(field.Name), s, result); !ok {
+							return
 						}
 						if !b.Table.SaveField(field.Name, result) { // Set the field value in the table
 							return
@@ -425,12 +624,14 @@ func (b *TBrowse) Show(L *lua.State) int {
 	b.TableView.SetSelectionChangedFunc(func(row, column int) {
 		// TODO: add calls of the lua callbacks linked to current line of the browse
 		if b.Table.Rows != nil {
-			if row > 0 {
-				b.Table.Rows.Pos = row - 1 // Set the current row position in the table
+			if row >= b.headerRows() {
+				if pos := b.posOfRow(row); pos >= 0 {
+					b.Table.Rows.Pos = pos // Set the current row position in the table
+				}
 			} else {
 				b.Table.Rows.Pos = 0
 			}
-			if b.lastRowVisited != row {
+			if b.lastRowVisited != row && row >= b.headerRows() {
 				b.lastRowVisited = row
 				for i := 0; i < b.TableView.GetColumnCount(); i++ {
 					cell := b.TableView.GetCell(row, i)
@@ -446,8 +647,11 @@ func (b *TBrowse) Show(L *lua.State) int {
 		// 	//fmt.Printf("User moved to row %d, column %d, cell text: %s\n", row, column, cell.Text)
 	})
 	b.TableView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		switch event.Key() {
-		case tcell.KeyEnter: //KeyCtrlL:
+		if b.isFilterRowSelected() && b.filterRowInput(event) {
+			return nil
+		}
+		switch key := event.Key(); {
+		case key == tcell.KeyEnter: //KeyCtrlL:
 			if b.isLookup {
 				syncfunc.SetLookupSuccess(true)
 				b.applyLookup(L)
@@ -457,8 +661,8 @@ func (b *TBrowse) Show(L *lua.State) int {
 				}
 				// pagesfunc.Pages.RemovePage("browselookup")
 				// pagesfunc.Pages.SwitchToPage("main")
-				BrowseSubitemsFlex.Clear()
-				statefunc.App.SetRoot(statefunc.RunFlexLevel0, true)
+				b.close(L)
+				b.closeLookup()
 				b.LookupBrowseDest.checkInsertedLineToShow(L)
 				return event
 			}
@@ -475,28 +679,46 @@ func (b *TBrowse) Show(L *lua.State) int {
 			if field.LookupBrowse == nil {
 				return event
 			}
-			syncfunc.BrowseChId = -1
-			b.NearLookup = true
-			field.LookupBrowse.Show(L) // Initialize lookup browse
-			field.LookupBrowse.setLookupBrowseDest(b, &field)
-			showBrowseLookup(field.LookupBrowse.TableView)
+			b.openLookup(L, field)
 			return event
-		case tcell.KeyEscape:
+		case key == tcell.KeyEscape:
 			// If Escape is pressed, return to the main view
+			b.resetQuickJump()
+			b.close(L)
 			if b.isLookup {
-				BrowseSubitemsFlex.Clear()
-				statefunc.App.SetRoot(statefunc.RunFlexLevel0, true)
+				b.closeLookup()
 				return nil
 			} else {
 				// statefunc.App.SetRoot(statefunc.RunFlexLevel0, true).SetFocus(statefunc.RunFlexLevel0)
 				statefunc.App.SetRoot(statefunc.MainFlex, true).SetFocus(statefunc.MainFlex)
 				return nil // Return nil to indicate the event was handled
 			}
-		case tcell.KeyDown:
-			if !b.isLookup {
+		case keymapfunc.Matches(keymapfunc.BrowseToggleMark, event) && !b.isJumping():
+			// While a quick-jump prefix is typed, the space belongs to it
+			b.toggleMark()
+			return nil
+		case keymapfunc.Matches(keymapfunc.BrowseBulkSet, event):
+			b.showBulkSet()
+			return nil
+		case keymapfunc.Matches(keymapfunc.BrowseMoveUp, event):
+			b.moveRow(true)
+			return nil
+		case keymapfunc.Matches(keymapfunc.BrowseMoveDown, event):
+			b.moveRow(false)
+			return nil
+		case key == tcell.KeyDown:
+			if event.Modifiers()&tcell.ModAlt != 0 {
+				// Alt+Down inserts a blank row below the current one
+				b.insertNewRow(L, false)
+				return nil
+			}
+			if b.leaveInsertedRow(true) {
+				return nil
+			}
+			if !b.isLookup && !b.hasGroupTotals() {
 				row, _ := b.TableView.GetSelection()
 				lastRow := b.TableView.GetRowCount() - 1
-				if row == lastRow {
+				if row == lastRow && !b.isLoading() {
 					// If the last row is selected, do not allow further down navigation
 					if !b.isNewRowMode() {
 						// If no new row is being added, return nil to indicate the event was handled
@@ -505,13 +727,21 @@ func (b *TBrowse) Show(L *lua.State) int {
 					}
 				}
 			}
-		case tcell.KeyUp:
+		case key == tcell.KeyUp:
+			if event.Modifiers()&tcell.ModAlt != 0 {
+				// Alt+Up inserts a blank row above the current one
+				b.insertNewRow(L, true)
+				return nil
+			}
+			if b.leaveInsertedRow(false) {
+				return event
+			}
 			if !b.isLookup {
 				row, _ := b.TableView.GetSelection()
 				lastRow := b.TableView.GetRowCount() - 1
 				if row == lastRow {
 					if b.isNewRowMode() {
-						if lastRow > 1 {
+						if lastRow > b.headerRows() {
 							b.TableView.RemoveRow(lastRow)
 						}
 						b.clearNewRowMode()
@@ -522,16 +752,31 @@ func (b *TBrowse) Show(L *lua.State) int {
 					}
 				}
 			}
-		case tcell.KeyDelete:
-			if !b.isLookup && !b.isNewRowMode() {
+		case keymapfunc.Matches(keymapfunc.BrowseDeleteRow, event):
+			if !b.isLookup && !b.isNewRowMode() && !b.hasGroupTotals() {
 				Confirm(i18nfunc.T("dialog.remove_row", nil), func(idx bool) {
 					if idx {
 						b.deleteRow()
 					}
 				})
 			}
-		case tcell.KeyF7:
+		case keymapfunc.Matches(keymapfunc.BrowseFilter, event):
 			b.showBrowseFilter()
+		case keymapfunc.Matches(keymapfunc.BrowseViewValue, event):
+			b.showCellValue()
+			return nil
+		case keymapfunc.Matches(keymapfunc.BrowseExport, event):
+			if !b.isLookup && !b.isNewRowMode() {
+				b.showExportMenu()
+				return nil
+			}
+		case key == tcell.KeyRune:
+			// The navigation keys of the table move the selection unless they go on a prefix
+			if event.Modifiers()&(tcell.ModAlt|tcell.ModCtrl) == 0 && !b.isNewRowMode() &&
+				(b.isJumping() || !isTableNavigationRune(event.Rune())) {
+				b.quickJump(event.Rune())
+				return nil
+			}
 		}
 		return event // Return the event for further processing
 	})
@@ -540,7 +785,14 @@ func (b *TBrowse) Show(L *lua.State) int {
 			row, _ := b.TableView.GetSelection()
 			lastRow := b.TableView.GetRowCount() - 1
 			if action == tview.MouseLeftClick {
-				if b.isNewRowMode() {
+				if b.isInsertedRow() {
+					x, y := event.Position()
+					cellr, _ := b.TableView.CellAt(x, y)
+					if cellr != b.NewRowNum {
+						b.TableView.RemoveRow(b.NewRowNum)
+						b.clearNewRowMode()
+					}
+				} else if b.isNewRowMode() {
 					if row < lastRow {
 						if lastRow >= 0 {
 							b.TableView.RemoveRow(lastRow)
@@ -579,10 +831,10 @@ func (b *TBrowse) deleteRow() {
 			row = b.TableView.GetRowCount() - 1
 		}
 		b.TableView.Select(row, col)
-		if b.TableView.GetRowCount() == 1 {
+		if b.TableView.GetRowCount() == b.headerRows() {
 			//b.TableView.ScrollToBeginning()
 			b.Table.Init()
-			b.setNewRowMode(1) // Set NewRowNum to the next row index
+			b.setNewRowMode(b.headerRows()) // Set NewRowNum to the next row index
 			b.addNewEmptyRow(statefunc.L)
 			//b.InitRow(statefunc.L)
 			b.TableView.ScrollToBeginning()
@@ -610,13 +862,12 @@ func (b *TBrowse) checkInsertedLineToShow(L *lua.State) {
 	}
 }
 
+// refreshFuncCells runs the field functions of the selected row again
 func (b *TBrowse) refreshFuncCells(L *lua.State) {
 	row, _ := b.TableView.GetSelection()
 	for col := range b.TableView.GetColumnCount() {
 		cell := b.TableView.GetCell(row, col)
-		ref := cell.GetReference()
-		if ref != nil {
-			field := ref.(TBrowseField)
+		if field, ok := cell.GetReference().(TBrowseField); ok {
 			if field.Function != "" { // If the field has a function, call it
 				v := b.runFieldFunction(L, field.Function)
 				cell := b.TableView.GetCell(row, col)
@@ -629,17 +880,16 @@ func (b *TBrowse) refreshFuncCells(L *lua.State) {
 func (b *TBrowse) refreshBrowse(goTop bool) {
 	colCount := b.TableView.GetColumnCount()
 	rc := b.TableView.GetRowCount()
-	if rc > 1 {
+	first := b.headerRows()
+	if rc > first {
 		for col := 0; col < colCount; col++ {
-			hCell := b.TableView.GetCell(0, col)
+			hCell := b.TableView.GetCell(b.captionRow(), col)
 			if hCell != nil {
 				hCell.SetStyle(tcell.StyleDefault.Normal().Underline(false))
 			}
-			cell := b.TableView.GetCell(1, col)
+			cell := b.TableView.GetCell(first, col)
 			if cell != nil {
-				ref := cell.GetReference()
-				if ref != nil {
-					field := ref.(TBrowseField)
+				if field, ok := cell.GetReference().(TBrowseField); ok {
 					if b.Filters != nil {
 						if b.Filters[field.Name] != "" {
 							hCell.SetStyle(tcell.StyleDefault.Normal().Underline(true))
@@ -648,31 +898,158 @@ func (b *TBrowse) refreshBrowse(goTop bool) {
 				}
 			}
 		}
-		for i := rc - 1; i > 0; i-- {
+		for i := rc - 1; i >= first; i-- {
 			b.TableView.RemoveRow(i)
 		}
 	}
-	if b.Table.Find() {
-		for {
-			b.initRow(statefunc.L)
-			if !b.Table.Next() { // Move to the next row
-				if goTop {
-					b.Table.ScrollToBeginning()     // Stand to the first row
-					b.TableView.ScrollToBeginning() // Scroll to the beginning of the table
-				}
-				break // Exit the loop if no more rows
-			}
-		}
+	b.fillColumnGroups()
+	b.fillFilterRow()
+	b.groupRows = nil
+	if rest, found := b.findRows(); found {
+		b.loadRows(statefunc.L, goTop, rest)
 	} else {
 		b.Table.Init()
 		b.initRow(statefunc.L)
-		b.setNewRowMode(1) // Set NewRowNum to the next row index
+		b.setNewRowMode(first) // Set NewRowNum to the next row index
 		b.TableView.ScrollToBeginning()
 		return
 	}
 
 }
 
+// browseLoadBatch is the number of rows added to the browse view at once
+const browseLoadBatch = 100
+
+var loadingSpinner = []string{"|", "/", "-", "\\"}
+
+// findRows runs Table.Find for the browse. Without group totals only the first batch of
+// rows is loaded, the rest is read by loadRows with the returned RowFetch.
+func (b *TBrowse) findRows() (*gormfunc.RowFetch, bool) {
+	if b.hasGroupTotals() {
+		return nil, b.Table.Find()
+	}
+	return b.Table.FindFirst(browseLoadBatch)
+}
+
+// loadRows fills the browse view with the rows found by findRows. The first batch is added
+// right away. The rest is read from the database in the background and added in batches on
+// the UI goroutine, so the browse accepts input while loading. Adding rows runs the field
+// functions and moves the table position, so it waits while a script runs.
+// A newer load (e.g. after a filter change) stops the previous one.
+func (b *TBrowse) loadRows(L *lua.State, goTop bool, rest *gormfunc.RowFetch) {
+	if b.hasGroupTotals() {
+		b.loadGroupedRows(L, goTop)
+		return
+	}
+	gen := b.loadGen.Add(1)
+	rows := b.Table.Rows
+	loaded := len(rows.Rows)
+	b.addRows(L, 0, loaded)
+	if goTop {
+		b.Table.ScrollToBeginning()     // Stand to the first row
+		b.TableView.ScrollToBeginning() // Scroll to the beginning of the table
+	} else {
+		// Keep the table positioned on the selected row, as far as it is loaded
+		row, _ := b.TableView.GetSelection()
+		b.Table.ScrollToRow(max(0, min(row-b.headerRows(), loaded-1)))
+	}
+	if rest == nil {
+		b.setLoading("")
+		return
+	}
+	b.setLoading(fmt.Sprintf("%s %d", loadingSpinner[0], loaded))
+	go b.loadRest(L, gen, rows, loaded, rest)
+}
+
+// loadRest reads the rows after the first batch and adds them to rows and to the view in
+// batches. It stops when the browse is reloaded or its rows are replaced.
+func (b *TBrowse) loadRest(L *lua.State, gen int64, rows *gormfunc.Rowset, loaded int, rest *gormfunc.RowFetch) {
+	current := func() bool {
+		return b.loadGen.Load() == gen && b.Table.Rows == rows
+	}
+	records, err := rest.Fetch()
+	if err != nil {
+		updateWithoutScript(func() bool {
+			if current() {
+				b.setLoading("")
+				errorhandlefunc.ThrowError(err.Error(), errorhandlefunc.ErrorTypeScript, false)
+			}
+			return false
+		})
+		return
+	}
+	total := loaded + len(records)
+	for step := 1; len(records) > 0; step++ {
+		batch := records[:min(browseLoadBatch, len(records))]
+		records = records[len(batch):]
+		added := updateWithoutScript(func() bool {
+			if !current() {
+				return false
+			}
+			from := len(rows.Rows)
+			rows.Rows = append(rows.Rows, batch...)
+			b.addRows(L, from, len(rows.Rows))
+			if len(records) == 0 {
+				b.setLoading("")
+			} else {
+				b.setLoading(fmt.Sprintf("%s %d/%d", loadingSpinner[step%len(loadingSpinner)], len(rows.Rows), total))
+			}
+			return true
+		})
+		if !added {
+			return
+		}
+	}
+}
+
+// scriptPollInterval is how often updateWithoutScript checks whether the running script has ended
+const scriptPollInterval = 20 * time.Millisecond
+
+// updateWithoutScript runs fn on the UI goroutine at a time no script started from the editor
+// is running on the Lua state, waiting for a running one to end. Returns the result of fn.
+func updateWithoutScript(fn func() bool) bool {
+	for {
+		for statefunc.IsScriptAsync() {
+			time.Sleep(scriptPollInterval)
+		}
+		var ran, result bool
+		done := make(chan struct{})
+		statefunc.App.QueueUpdateDraw(func() {
+			defer close(done)
+			if statefunc.IsScriptAsync() { // A script was started in the meantime
+				return
+			}
+			ran = true
+			result = fn()
+		})
+		<-done
+		if ran {
+			return result
+		}
+	}
+}
+
+// addRows shows the table rows from..to-1 in the browse view keeping the current row position
+func (b *TBrowse) addRows(L *lua.State, from, to int) {
+	pos := b.Table.Rows.Pos
+	for i := from; i < to; i++ {
+		b.Table.Rows.Pos = i
+		b.initRow(L)
+	}
+	b.Table.Rows.Pos = pos
+}
+
+// isLoading reports whether the rows are still being added in the background
+func (b *TBrowse) isLoading() bool {
+	return b.loading != ""
+}
+
+// setLoading shows the progress of the background load in the info bar
+func (b *TBrowse) setLoading(progress string) {
+	b.loading = progress
+	refreshInfo()
+}
+
 func (b *TBrowse) refreshBrowseLine() {
 	id := b.getRowId()
 	if id == 0 {
@@ -685,7 +1062,11 @@ func (b *TBrowse) refreshBrowseLine() {
 }
 
 func (b *TBrowse) getRowId() int64 {
-	r := b.Table.GetCurrentRecord()
+	return recordId(b.Table.GetCurrentRecord())
+}
+
+// recordId returns the primary key of the record, 0 if it has none
+func recordId(r gormfunc.Record) int64 {
 	if r == nil {
 		return 0
 	}
@@ -710,8 +1091,8 @@ func (b *TBrowse) runCellFuncIfExists(L *lua.State, cell *tview.TableCell) {
 	if ref == nil {
 		return
 	}
-	field := ref.(TBrowseField)
-	if field.Function == "" {
+	field, ok := ref.(TBrowseField)
+	if !ok || field.Function == "" {
 		return
 	}
 	result := b.runFieldFunction(L, field.Function)
@@ -775,7 +1156,7 @@ func (b *TBrowse) applyLookup(L *lua.State) {
 	}
 	L.SetMetaTable(-2)
 	// Now stack: [function, wrapper, wrapper1]
-	err := L.ProtectedCall(2, 0, 0)
+	err := statefunc.ProtectedCallWithTimeout(L, b.LookupFieldDest.LookupFunc, 2, 0)
 	if err != nil {
 		errorhandlefunc.ThrowError(err.Error(), errorhandlefunc.ErrorTypeScript, true)
 	}
@@ -826,6 +1207,12 @@ func (b *TBrowse) convertFldFormatIntToUser(field *TBrowseField) (interface{}, e
 			v = ""
 		}
 	case typesfunc.TypeInteger, typesfunc.TypeReal:
+		if numfunc.IsNumberFormatSet() {
+			if n, ok := toFloat(v); ok {
+				v = numfunc.FormatNumberToUser(n, ft == typesfunc.TypeInteger)
+				break
+			}
+		}
 		v = fmt.Sprintf("%v", v) // Convert to string for display
 	default:
 		if v == nil {
@@ -835,56 +1222,131 @@ func (b *TBrowse) convertFldFormatIntToUser(field *TBrowseField) (interface{}, e
 	return v, nil
 }
 
+// toFloat converts a numeric field value to float64
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
 func (b *TBrowse) initRow(L *lua.State) {
+	b.computeRowTag(L)
 	if len(b.Fields) > 0 {
 		// If fields are defined, use them to populate the table
-		for j, field := range b.Fields {
+		for j, field := range b.shownFields {
 			if field.IsTableField { // If the field is a table field, get the value from the table
 				// Get the field value from the table
-				v, err := b.convertFldFormatIntToUser(&field)
-				if err != nil {
-					errorhandlefunc.ThrowError(err.Error(), errorhandlefunc.ErrorTypeScript, true)
+				s, v, ok := b.fieldText(&field)
+				if !ok {
 					return
 				}
-				var s string
-				if v != nil {
-					switch v.(type) {
-					case string, int, int64, float64, bool:
-						s = fmt.Sprintf("%v", v)
-					default:
-						t := fmt.Sprintf("%v", reflect.TypeOf(v))
-						if t == "*interface {}" {
-							var vp *interface{} = v.(*interface{})
-							vv := *vp
-							s = vv.(string)
-						} else {
-							errorhandlefunc.ThrowError(i18nfunc.T("error.value_should_be_string", map[string]interface{}{
-								"Value": v,
-							}), errorhandlefunc.ErrorTypeScript, true)
-							return
-						}
-					}
+				if field.Format != "" {
+					s = b.runFormatFunction(L, field.Format, v) // Show the value through the format function
 				}
-				i := b.Table.Rows.Pos                                                                      // Get the current row index
-				b.TableView.SetCell(i+1, j, tview.NewTableCell(s).SetSelectable(true).SetReference(field)) // Set cell values
+				i := b.rowOfPos(b.Table.Rows.Pos)                                                         // Get the current row index
+				b.TableView.SetCell(i, j, newFieldCell(field, s).SetSelectable(true).SetReference(field)) // Set cell values
 			} else {
 				result := b.runFieldFunction(L, field.Function)
-				i := b.Table.Rows.Pos                                                                                              // Get the current row index
-				b.TableView.SetCell(i+1, j, tview.NewTableCell(fmt.Sprintf("%v", result)).SetSelectable(true).SetReference(field)) // Set cell values
+				i := b.rowOfPos(b.Table.Rows.Pos)                                                                                 // Get the current row index
+				b.TableView.SetCell(i, j, newFieldCell(field, fmt.Sprintf("%v", result)).SetSelectable(true).SetReference(field)) // Set cell values
 			}
 		}
 	} else {
-		for j, col := range b.Table.Columns {
+		for j, col := range b.columns() {
 			dtType := b.Table.GetFieldType(col) // Get the field type for the column
 			// if j < len(b.Fields) {
 			// 	dtType = b.Fields[j].ExtraType
 			// }
 			b.Table.GetField(col, dtType)
-			i := b.Table.Rows.Pos                                                                     // Get the current row index
-			v := b.Table.Rows.Rows[i][col]                                                            // Get the field value for the column
-			b.TableView.SetCell(i+1, j, tview.NewTableCell(fmt.Sprintf("%v", v)).SetSelectable(true)) // Set cell values
+			i := b.Table.Rows.Pos                                                                               // Get the current row index
+			v := b.Table.Rows.Rows[i][col]                                                                      // Get the field value for the column
+			b.TableView.SetCell(b.rowOfPos(i), j, tview.NewTableCell(fmt.Sprintf("%v", v)).SetSelectable(true)) // Set cell values
 		}
 	}
+	b.showMark()
+}
+
+// fieldText returns the value of the table field in the current row as the browse shows it
+// without a format function, and the value itself. Returns false if the value can not be shown.
+func (b *TBrowse) fieldText(field *TBrowseField) (string, interface{}, bool) {
+	v, err := b.convertFldFormatIntToUser(field)
+	if err != nil {
+		errorhandlefunc.ThrowError(err.Error(), errorhandlefunc.ErrorTypeScript, true)
+		return "", nil, false
+	}
+	var s string
+	if v != nil {
+		switch v.(type) {
+		case string, int, int64, float64, bool:
+			s = fmt.Sprintf("%v", v)
+		default:
+			t := fmt.Sprintf("%v", reflect.TypeOf(v))
+			if t == "*interface {}" {
+				var vp *interface{} = v.(*interface{})
+				vv := *vp
+				if vv != nil { // NULL, like an unset reference, shows empty
+					s = fmt.Sprintf("%v", vv)
+				}
+			} else {
+				errorhandlefunc.ThrowError(i18nfunc.T("error.value_should_be_string", map[string]interface{}{
+					"Value": v,
+				}), errorhandlefunc.ErrorTypeScript, true)
+				return "", nil, false
+			}
+		}
+	}
+	return s, v, true
+}
+
+// runFormatFunction calls the format function of a field with its value and returns the text to show
+func (b *TBrowse) runFormatFunction(L *lua.State, function string, value interface{}) string {
+	defer func() {
+		if r := recover(); r != nil {
+			errorhandlefunc.ThrowError(fmt.Sprint(r), errorhandlefunc.ErrorTypeScript, true)
+		}
+	}()
+	L.Global(function) // Get the function from the Lua global environment
+	if !L.IsFunction(-1) {
+		L.Pop(1)
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_a_function", map[string]interface{}{
+			"Name": function,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return ""
+	}
+	if p, ok := value.(*interface{}); ok && p != nil {
+		value = *p
+	}
+	switch v := value.(type) {
+	case nil:
+		L.PushNil()
+	case int:
+		L.PushInteger(v)
+	case int64:
+		L.PushInteger(int(v))
+	case float64:
+		L.PushNumber(v)
+	case bool:
+		L.PushBoolean(v)
+	default:
+		L.PushString(fmt.Sprintf("%v", v))
+	}
+	err := statefunc.ProtectedCallWithTimeout(L, function, 1, 1) // Call the Lua function with the value
+	if err != nil {
+		errorhandlefunc.ThrowError(err.Error(), errorhandlefunc.ErrorTypeScript, false)
+		return ""
+	}
+	text, _ := lua.ToStringMeta(L, -1)
+	L.Pop(2) // the result and its string
+	return text
 }
 
 func (b *TBrowse) runFieldFunction(L *lua.State, function string) interface{} {
@@ -912,7 +1374,7 @@ func (b *TBrowse) runFieldFunction(L *lua.State, function string) interface{} {
 	}
 	L.SetMetaTable(-2)
 
-	err := L.ProtectedCall(1, 1, 0) // Call the Lua function with the Table as an argument
+	err := statefunc.ProtectedCallWithTimeout(L, function, 1, 1) // Call the Lua function with the Table as an argument
 	if err != nil {
 		errorhandlefunc.ThrowError(err.Error(), errorhandlefunc.ErrorTypeScript, false)
 		return nil
@@ -923,9 +1385,17 @@ func (b *TBrowse) runFieldFunction(L *lua.State, function string) interface{} {
 }
 
 func (b *TBrowse) addNewEmptyRow(L *lua.State) int {
-	for i, field := range b.Fields {
+	for i, field := range b.shownFields {
 		// Create a new cell for each field
-		cell := tview.NewTableCell(fmt.Sprintf("%v", b.Table.GetDefaultValueForTheField(field.Name))).SetSelectable(true).SetReference(field)
+		value := fmt.Sprintf("%v", b.Table.GetDefaultValueForTheField(field.Name))
+		switch fType := b.Table.GetFieldType(field.Name); fType {
+		case typesfunc.TypeDate, typesfunc.TypeTime, typesfunc.TypeDateTime:
+			// Defaults are kept in the internal format
+			if v, err := timefunc.FormatDateTime(value, fType, timefunc.ToUserFormat); err == nil {
+				value = v
+			}
+		}
+		cell := newFieldCell(field, value).SetSelectable(true).SetReference(field)
 		//cell.SetTextColor(tcell.ColorYellow) // Set the text color for new rows
 		b.TableView.SetCell(b.NewRowNum, i, cell)
 	}
@@ -934,7 +1404,7 @@ func (b *TBrowse) addNewEmptyRow(L *lua.State) int {
 }
 
 func (b *TBrowse) addNewRowByTableRow(row gormfunc.Record) int {
-	for i, field := range b.Fields {
+	for i, field := range b.shownFields {
 		// Create a new cell for each field
 		var value string
 		val := row[field.Name]
@@ -974,7 +1444,7 @@ func (b *TBrowse) addNewRowByTableRow(row gormfunc.Record) int {
 				value = fmt.Sprintf("%v", val)
 			}
 		}
-		cell := tview.NewTableCell(value).SetSelectable(true).SetReference(field)
+		cell := newFieldCell(field, value).SetSelectable(true).SetReference(field)
 		//cell.SetTextColor(tcell.ColorYellow) // Set the text color for new rows
 		b.TableView.SetCell(b.NewRowNum, i, cell)
 	}
@@ -982,6 +1452,55 @@ func (b *TBrowse) addNewRowByTableRow(row gormfunc.Record) int {
 	return 0
 }
 
+// insertNewRow adds a blank row above or below the selected one and puts
+// the browse into new row mode on it. The record is inserted into the table
+// when the first field of the row is edited.
+func (b *TBrowse) insertNewRow(L *lua.State, above bool) {
+	if b.isLookup || b.isNewRowMode() || b.isLoading() || b.hasGroupTotals() {
+		return
+	}
+	row, col := b.TableView.GetSelection()
+	if row < b.headerRows() {
+		row = b.headerRows()
+	}
+	if !above {
+		row++
+	}
+	if row >= b.TableView.GetRowCount() {
+		// Below the last row is the regular new row at the end
+		b.setNewRowMode(b.TableView.GetRowCount())
+	} else {
+		b.TableView.InsertRow(row)
+		b.setNewRowMode(row)
+	}
+	b.addNewEmptyRow(L)
+	b.TableView.Select(b.NewRowNum, col)
+}
+
+// isInsertedRow reports whether the new row was inserted between existing rows
+func (b *TBrowse) isInsertedRow() bool {
+	return b.isNewRowMode() && b.NewRowNum < b.TableView.GetRowCount()-1
+}
+
+// leaveInsertedRow drops the unsaved inserted row when the selection moves
+// away from it. For moves down the row below takes its place and stays
+// selected, so the caller should not pass the key on.
+func (b *TBrowse) leaveInsertedRow(down bool) bool {
+	if !b.isInsertedRow() {
+		return false
+	}
+	row, col := b.TableView.GetSelection()
+	if row != b.NewRowNum {
+		return false
+	}
+	b.TableView.RemoveRow(row)
+	b.clearNewRowMode()
+	if down {
+		b.TableView.Select(row, col)
+	}
+	return true
+}
+
 func (b *TBrowse) isNewRowMode() bool {
 	return b.NewRowNum >= 0
 }
@@ -1094,6 +1613,43 @@ func SetFieldLookup(L *lua.State) int {
 	return browse.setFieldLookup(L, fieldName, lookupTable, lookupFunc)
 }
 
+// ungroupEditedNumber removes the grouping added for display from the result of an edit of a number field
+func ungroupEditedNumber(caption, extType, typed, result string) (string, bool) {
+	if !numfunc.IsNumberFormatSet() || (extType != typesfunc.TypeInteger && extType != typesfunc.TypeReal) {
+		return result, true
+	}
+	result, err := numfunc.ParseNumberFromUser(result)
+	if err != nil {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.field_number_format", map[string]interface{}{
+			"Field": caption,
+			"Value": typed,
+		}), errorhandlefunc.ErrorTypeData, false)
+		return "", false
+	}
+	return result, true
+}
+
+// editedValueToInternal converts the result of an edit of a date, time or boolean field to the
+// format kept in the table. typed is the text as the user typed it, for the error message.
+func editedValueToInternal(caption, extType, typed, result string) (string, bool) {
+	var err error
+	switch extType {
+	case typesfunc.TypeDate, typesfunc.TypeTime, typesfunc.TypeDateTime:
+		result, err = timefunc.FormatDateTime(result, extType, timefunc.ToInternalFormat)
+		if err != nil {
+			errorhandlefunc.ThrowError(timefunc.FieldFormatError(caption, typed, extType).Error(), errorhandlefunc.ErrorTypeData, false)
+			return "", false
+		}
+	case typesfunc.TypeBoolean:
+		result, err = boolfunc.FormatBool(result, boolfunc.ToInternalFormat)
+		if err != nil {
+			errorhandlefunc.ThrowError(err.Error(), errorhandlefunc.ErrorTypeData, false)
+			return "", false
+		}
+	}
+	return result, true
+}
+
 func showBrowseEdit(label, text, extType string, callback func(s string, key tcell.Key)) {
 	var input *tview.InputField
 	input = tview.NewInputField().SetText(text).
@@ -1104,6 +1660,7 @@ func showBrowseEdit(label, text, extType string, callback func(s string, key tce
 		})
 	input.SetLabel(label)
 	input.SetTitle("BROWSEINPUT")
+	inputfunc.ApplyInputTheme(input)
 	var ph string
 	switch extType {
 	case typesfunc.TypeDate:
@@ -1115,20 +1672,16 @@ func showBrowseEdit(label, text, extType string, callback func(s string, key tce
 	case typesfunc.TypeBoolean:
 		inputfunc.SetBoolInput(input)
 		input.SetPlaceholder("true/false")
-		input.SetPlaceholderTextColor(tcell.ColorYellow)
 		inputfunc.SetDateInput(input, ph)
 	case typesfunc.TypeInteger:
 		input.SetPlaceholder("0")
-		input.SetPlaceholderTextColor(tcell.ColorYellow)
 		input.SetAcceptanceFunc(tview.InputFieldInteger)
 	case typesfunc.TypeReal:
 		input.SetPlaceholder("0.0")
-		input.SetPlaceholderTextColor(tcell.ColorYellow)
 		input.SetAcceptanceFunc(tview.InputFieldFloat)
 	}
 	if extType == typesfunc.TypeDate || extType == typesfunc.TypeTime || extType == typesfunc.TypeDateTime {
 		input.SetPlaceholder(ph)
-		input.SetPlaceholderTextColor(tcell.ColorYellow)
 		inputfunc.SetDateInput(input, ph)
 
 	}
@@ -1203,6 +1756,7 @@ func (b *TBrowse) showBrowseFilter() bool {
 	})
 	input.SetLabel(field.Caption)
 	input.SetTitle("BROWSEFILTER")
+	inputfunc.ApplyInputTheme(input)
 	BrowseSubitemsFlex.AddItem(input, 0, 1, true)
 	statefunc.App.SetRoot(BrowseSubitemsFlex, true)
 	return true
@@ -0,0 +1,550 @@
+package uifunc
+
+import (
+	"fmt"
+	"gotulua/errorhandlefunc"
+	"gotulua/gormfunc"
+	"gotulua/i18nfunc"
+	"gotulua/inputfunc"
+	"gotulua/statefunc"
+	"gotulua/typesfunc"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Shopify/go-lua"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	i18nfunc.InitI18n("en") // The errors are checked in their English text
+	os.Exit(m.Run())
+}
+
+// newTestState sets up the UI state and a Lua state for a test, with a database in memory
+func newTestState(t testing.TB) (*lua.State, *gorm.DB) {
+	t.Helper()
+	statefunc.SetState(tview.NewFlex(), tview.NewFlex(), tview.NewPages(), tview.NewApplication())
+	SetUIData()
+	L := lua.NewState()
+	lua.OpenLibraries(L)
+	statefunc.L = L
+	lua.NewMetaTable(L, "TableMT")
+	L.Pop(1)
+	db, err := gormfunc.CreateDB(gormfunc.MemoryDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { gormfunc.CloseDB(db) })
+	return L, db
+}
+
+// newTestBrowse creates the table with a text column a holding the values and a browse of it
+func newTestBrowse(t testing.TB, L *lua.State, db *gorm.DB, name string, isLookup bool, values ...string) *TBrowse {
+	t.Helper()
+	if _, err := gormfunc.Exec(db, "CREATE TABLE "+name+" (id INTEGER PRIMARY KEY, a TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range values {
+		if _, err := gormfunc.Exec(db, "INSERT INTO "+name+" (a) VALUES (?)", v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b := &TBrowse{Table: gormfunc.OpenTable(db, name), Filters: map[string]string{}, NewRowNum: -1, isLookup: isLookup}
+	b.addField(L, "n::a;c::A")
+	return b
+}
+
+// showTestBrowse shows the browse and takes the table view it leaves on the Lua stack
+func showTestBrowse(L *lua.State, b *TBrowse) {
+	b.Show(L)
+	L.SetTop(0)
+}
+
+// runTestApp runs the event loop of the application on a simulation screen until the test ends
+func runTestApp(t testing.TB) {
+	t.Helper()
+	statefunc.App.SetScreen(tcell.NewSimulationScreen(""))
+	go statefunc.App.Run()
+	t.Cleanup(statefunc.App.Stop)
+}
+
+// onUI runs fn on the UI goroutine and waits for it
+func onUI(fn func()) {
+	done := make(chan struct{})
+	statefunc.App.QueueUpdate(func() {
+		defer close(done)
+		fn()
+	})
+	<-done
+}
+
+// waitForLoad waits until the browse has added all its rows in the background
+func waitForLoad(t testing.TB, b *TBrowse) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(time.Millisecond) {
+		var loading bool
+		onUI(func() { loading = b.isLoading() })
+		if !loading {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the rows were not loaded")
+		}
+	}
+}
+
+// rowValues returns n values "row 000", "row 001", ... in the order they sort
+func rowValues(n int) []string {
+	values := make([]string, n)
+	for i := range values {
+		values[i] = fmt.Sprintf("row %03d", i)
+	}
+	return values
+}
+
+// checkRows checks that the browse shows the values in their order and the table holds them
+func checkRows(t *testing.T, b *TBrowse, values []string) {
+	t.Helper()
+	if n := len(b.Table.Rows.Rows); n != len(values) {
+		t.Errorf("the table holds %d rows, want %d", n, len(values))
+	}
+	if n := b.TableView.GetRowCount() - b.headerRows(); n != len(values) {
+		t.Errorf("the browse shows %d rows, want %d", n, len(values))
+	}
+	for i, v := range values {
+		if got := b.TableView.GetCell(b.headerRows()+i, 0).Text; got != v {
+			t.Errorf("row %d shows %q, want %q", i, got, v)
+			return
+		}
+	}
+}
+
+func pressKey(b *TBrowse, key tcell.Key, ch rune) {
+	b.TableView.GetInputCapture()(tcell.NewEventKey(key, ch, tcell.ModNone))
+}
+
+func TestQuickJumpLeavesNavigationKeysToTheTable(t *testing.T) {
+	L, db := newTestState(t)
+	b := newTestBrowse(t, L, db, "names", false, "anna", "bob", "greta", "olga", "jack")
+	showTestBrowse(L, b)
+	b.TableView.Select(1, 0)
+
+	for _, ch := range "gGjkhl" {
+		pressKey(b, tcell.KeyRune, ch)
+		if row, _ := b.TableView.GetSelection(); row != 1 || b.isJumping() {
+			t.Errorf("%c started a quick jump to row %d", ch, row)
+		}
+	}
+	// Once a prefix is typed the navigation keys go on it
+	pressKey(b, tcell.KeyRune, 'o')
+	pressKey(b, tcell.KeyRune, 'l')
+	if row, _ := b.TableView.GetSelection(); row != 4 {
+		t.Errorf("ol selected row %d, want 4", row)
+	}
+}
+
+func TestBackgroundLoadAddsAllRows(t *testing.T) {
+	L, db := newTestState(t)
+	values := rowValues(2*browseLoadBatch + 50)
+	b := newTestBrowse(t, L, db, "many", false, values...)
+	runTestApp(t)
+
+	onUI(func() {
+		showTestBrowse(L, b)
+		if n := len(b.Table.Rows.Rows); n != browseLoadBatch {
+			t.Errorf("the first batch has %d rows, want %d", n, browseLoadBatch)
+		}
+	})
+	waitForLoad(t, b)
+	onUI(func() { checkRows(t, b, values) })
+}
+
+func TestBackgroundLoadWaitsForTheScript(t *testing.T) {
+	L, db := newTestState(t)
+	values := rowValues(2*browseLoadBatch + 50)
+	b := newTestBrowse(t, L, db, "many", false, values...)
+	runTestApp(t)
+
+	loadedWhileRunning := make(chan int, 1)
+	statefunc.StartScript(L, "script", func(string) error {
+		showTestBrowse(L, b)
+		time.Sleep(100 * time.Millisecond) // The rest of the rows are read meanwhile
+		loadedWhileRunning <- len(b.Table.Rows.Rows)
+		return nil
+	})
+	if n := <-loadedWhileRunning; n != browseLoadBatch {
+		t.Errorf("%d rows were added while the script was running, want %d", n, browseLoadBatch)
+	}
+	waitForLoad(t, b)
+	onUI(func() { checkRows(t, b, values) })
+}
+
+func TestReloadKeepsThePositionInTheLoadedRows(t *testing.T) {
+	L, db := newTestState(t)
+	values := rowValues(2*browseLoadBatch + 50)
+	b := newTestBrowse(t, L, db, "many", false, values...)
+	runTestApp(t)
+	onUI(func() { showTestBrowse(L, b) })
+	waitForLoad(t, b)
+
+	onUI(func() {
+		b.TableView.Select(b.headerRows()+2*browseLoadBatch, 0)
+		b.refreshBrowse(false)
+		if pos := b.Table.Rows.Pos; pos != browseLoadBatch-1 {
+			t.Errorf("the table stands on row %d, want the last loaded row %d", pos, browseLoadBatch-1)
+		}
+	})
+	waitForLoad(t, b)
+	onUI(func() { checkRows(t, b, values) })
+}
+
+// editSelectedCell edits the selected cell of the browse as Enter does and types the text in the edit input
+func editSelectedCell(t *testing.T, b *TBrowse, text string) {
+	t.Helper()
+	setFocus := func(p tview.Primitive) { statefunc.App.SetFocus(p) }
+	b.TableView.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), setFocus)
+	input, ok := statefunc.App.GetFocus().(*tview.InputField)
+	if !ok {
+		t.Fatalf("no edit input was shown, focus on %T", statefunc.App.GetFocus())
+	}
+	input.SetText(text)
+	input.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), setFocus)
+}
+
+func TestInsertedRowIsSavedOnEdit(t *testing.T) {
+	L, db := newTestState(t)
+	b := newTestBrowse(t, L, db, "names", false, "a", "c")
+	b.Fields[0].IsEditable = true
+	showTestBrowse(L, b)
+	first := b.headerRows()
+	b.TableView.Select(first, 0)
+
+	b.TableView.GetInputCapture()(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModAlt))
+	if b.NewRowNum != first+1 {
+		t.Fatalf("the new row is at %d, want %d", b.NewRowNum, first+1)
+	}
+	if row, _ := b.TableView.GetSelection(); row != first+1 {
+		t.Errorf("row %d is selected, want the new row", row)
+	}
+	if got := b.TableView.GetCell(first+2, 0).Text; got != "c" {
+		t.Errorf("the row below the new one shows %q, want c", got)
+	}
+
+	editSelectedCell(t, b, "b")
+	if b.isNewRowMode() {
+		t.Error("the browse is still in new row mode")
+	}
+	if count, _ := b.Table.Count(); count != 3 {
+		t.Errorf("the table has %d rows, want the new one saved", count)
+	}
+	var saved []string
+	for row := first; row < b.TableView.GetRowCount(); row++ {
+		saved = append(saved, b.TableView.GetCell(row, 0).Text)
+	}
+	if len(saved) != 3 || saved[2] != "b" {
+		t.Errorf("the browse shows %q, want the saved row in the table order", saved)
+	}
+
+	// A row inserted above and left unedited is dropped again
+	b.TableView.Select(first, 0)
+	rows := b.TableView.GetRowCount()
+	b.TableView.GetInputCapture()(tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModAlt))
+	if b.NewRowNum != first || b.TableView.GetRowCount() != rows+1 {
+		t.Fatalf("the new row is at %d of %d rows, want %d of %d", b.NewRowNum, b.TableView.GetRowCount(), first, rows+1)
+	}
+	pressKey(b, tcell.KeyDown, 0)
+	if b.isNewRowMode() || b.TableView.GetRowCount() != rows {
+		t.Errorf("leaving the new row kept it: %d rows, want %d", b.TableView.GetRowCount(), rows)
+	}
+}
+
+func TestOnOpenAndOnCloseRunOnce(t *testing.T) {
+	L, db := newTestState(t)
+	b := newTestBrowse(t, L, db, "names", false, "a", "b")
+	if err := lua.DoString(L, `
+		opened, closed = 0, 0
+		function onOpen(t) opened = opened + 1 end
+		function onClose(t) closed = closed + 1 end`); err != nil {
+		t.Fatal(err)
+	}
+	b.onOpen, b.onClose = "onOpen", "onClose"
+	counts := func() (opened, closed int) {
+		L.Global("opened")
+		L.Global("closed")
+		opened, _ = L.ToInteger(-2)
+		closed, _ = L.ToInteger(-1)
+		L.Pop(2)
+		return
+	}
+
+	showTestBrowse(L, b)
+	if opened, closed := counts(); opened != 1 || closed != 0 {
+		t.Fatalf("after Show onOpen ran %d times and onClose %d, want 1 and 0", opened, closed)
+	}
+	pressKey(b, tcell.KeyEscape, 0)
+	pressKey(b, tcell.KeyEscape, 0) // The browse is closed already
+	if opened, closed := counts(); opened != 1 || closed != 1 {
+		t.Errorf("after Escape onOpen ran %d times and onClose %d, want 1 and 1", opened, closed)
+	}
+}
+
+func TestHiddenPrimaryKeyStillFindsTheRow(t *testing.T) {
+	L, db := newTestState(t)
+	b := newTestBrowse(t, L, db, "names", false, "a", "b", "c")
+	b.Fields = nil // The columns are rendered from the table
+	b.hidePrimaryKey = true
+	showTestBrowse(L, b)
+
+	for col := 0; col < b.TableView.GetColumnCount(); col++ {
+		if caption := b.TableView.GetCell(b.captionRow(), col).Text; caption == gormfunc.PrimaryKeyField {
+			t.Errorf("column %d shows the primary key", col)
+		}
+	}
+	if got := b.TableView.GetCell(b.headerRows()+1, 0).Text; got != "b" {
+		t.Errorf("the second row shows %q, want b", got)
+	}
+
+	b.TableView.Select(b.headerRows()+1, 0)
+	if !b.Table.SaveField("a", "B") {
+		t.Fatal(statefunc.GetLastErrorText())
+	}
+	var saved string
+	if err := db.Raw("SELECT a FROM names WHERE id = 2").Scan(&saved).Error; err != nil || saved != "B" {
+		t.Errorf("row 2 holds %q, want the edit saved on it: %v", saved, err)
+	}
+}
+
+func TestMalformedDateErrorNamesTheFieldAndFormat(t *testing.T) {
+	var shown []string
+	errorhandlefunc.SetErrorSink(func(msg string) { shown = append(shown, msg) })
+	t.Cleanup(func() { errorhandlefunc.SetErrorSink(nil) })
+
+	if _, ok := editedValueToInternal("Start date", typesfunc.TypeDate, "31.13.2024", "31.13.2024"); ok {
+		t.Fatal("the malformed date was converted")
+	}
+	if len(shown) != 1 {
+		t.Fatalf("%d errors were shown, want 1", len(shown))
+	}
+	for _, want := range []string{"Start date", "31.13.2024", "dd.mm.yyyy"} {
+		if !strings.Contains(shown[0], want) {
+			t.Errorf("the error %q does not contain %q", shown[0], want)
+		}
+	}
+	if _, ok := editedValueToInternal("Start date", typesfunc.TypeDate, "29.02.2024", "29.02.2024"); !ok {
+		t.Errorf("a valid date was refused: %q", shown)
+	}
+}
+
+func TestExportMenuWritesTheFilteredRowsAsCSV(t *testing.T) {
+	L, db := newTestState(t)
+	b := newTestBrowse(t, L, db, "names", false, "anna", "bob", "anna")
+	b.Table.SetFilter("a", "anna")
+	showTestBrowse(L, b)
+	setFocus := func(p tview.Primitive) { statefunc.App.SetFocus(p) }
+
+	pressKey(b, tcell.KeyCtrlE, 0)
+	menu, ok := statefunc.App.GetFocus().(*tview.List)
+	if !ok {
+		t.Fatalf("no export menu was shown, focus on %T", statefunc.App.GetFocus())
+	}
+	menu.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), setFocus) // Export CSV
+	input, ok := statefunc.App.GetFocus().(*tview.InputField)
+	if !ok {
+		t.Fatalf("no path was asked for, focus on %T", statefunc.App.GetFocus())
+	}
+	path := filepath.Join(t.TempDir(), "names.csv")
+	input.SetText(path)
+	input.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), setFocus)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "a\nanna\nanna\n"; string(data) != want {
+		t.Errorf("the export holds %q, want %q", data, want)
+	}
+}
+
+func TestInputThemeColorsANewDateEdit(t *testing.T) {
+	newTestState(t)
+	placeholder, text, background := inputfunc.GetInputTheme()
+	t.Cleanup(func() { inputfunc.SetInputTheme(placeholder, text, background) })
+	inputfunc.SetInputTheme(tcell.ColorFuchsia, tcell.ColorNavy, tcell.ColorSilver)
+
+	showBrowseEdit("Day", "", typesfunc.TypeDate, func(string, tcell.Key) {})
+	input, ok := statefunc.App.GetFocus().(*tview.InputField)
+	if !ok {
+		t.Fatalf("no edit input was shown, focus on %T", statefunc.App.GetFocus())
+	}
+	if fg, _, _ := input.GetPlaceholderStyle().Decompose(); fg != tcell.ColorFuchsia {
+		t.Errorf("the placeholder color is %v, want the theme color", fg)
+	}
+	if fg, bg, _ := input.GetFieldStyle().Decompose(); fg != tcell.ColorNavy || bg != tcell.ColorSilver {
+		t.Errorf("the field colors are %v on %v, want the theme colors", fg, bg)
+	}
+}
+
+func TestFixedColumnsStayVisibleWhenScrollingRight(t *testing.T) {
+	L, db := newTestState(t)
+	if _, err := gormfunc.Exec(db, "CREATE TABLE wide (id INTEGER PRIMARY KEY, a TEXT, b TEXT, c TEXT, d TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+	filler := strings.Repeat("x", 15)
+	if _, err := gormfunc.Exec(db, "INSERT INTO wide (a, b, c, d) VALUES (?, ?, ?, ?)", "KEY1", filler, filler, "LAST"); err != nil {
+		t.Fatal(err)
+	}
+	b := &TBrowse{Table: gormfunc.OpenTable(db, "wide"), Filters: map[string]string{}, NewRowNum: -1}
+	for _, f := range []string{"n::a;c::A", "n::b;c::B", "n::c;c::C", "n::d;c::D"} {
+		b.addField(L, f)
+	}
+	L.PushUserData(b)
+	L.PushInteger(1)
+	SetFixedColumns(L)
+	L.SetTop(0)
+	showTestBrowse(L, b)
+
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer screen.Fini()
+	screen.SetSize(40, 10)
+	b.TableView.SetRect(0, 0, 40, 10)
+	b.TableView.Select(b.headerRows(), 3) // Scrolls right to the last column
+	b.TableView.Draw(screen)
+	screen.Show()
+	cells, width, height := screen.GetContents()
+	var text strings.Builder
+	for i := 0; i < width*height; i++ {
+		text.WriteString(string(cells[i].Runes))
+	}
+	if !strings.Contains(text.String(), "LAST") {
+		t.Fatal("the browse did not scroll to the last column")
+	}
+	if !strings.Contains(text.String(), "KEY1") {
+		t.Error("the fixed first column scrolled out of view")
+	}
+}
+
+func TestFormatFunctionChangesTheShownValueOnly(t *testing.T) {
+	L, db := newTestState(t)
+	if err := lua.DoString(L, `function mask(v) return "****" .. string.sub(v, -2) end`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gormfunc.Exec(db, "CREATE TABLE cards (id INTEGER PRIMARY KEY, a TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gormfunc.Exec(db, "INSERT INTO cards (a) VALUES ('123456')"); err != nil {
+		t.Fatal(err)
+	}
+	b := &TBrowse{Table: gormfunc.OpenTable(db, "cards"), Filters: map[string]string{}, NewRowNum: -1}
+	b.addField(L, "n::a;c::A;fmt::mask")
+	b.Fields[0].IsEditable = true
+	showTestBrowse(L, b)
+	first := b.headerRows()
+	if got := b.TableView.GetCell(first, 0).Text; got != "****56" {
+		t.Errorf("the cell shows %q, want the formatted ****56", got)
+	}
+
+	b.TableView.Select(first, 0)
+	setFocus := func(p tview.Primitive) { statefunc.App.SetFocus(p) }
+	b.TableView.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), setFocus)
+	input, ok := statefunc.App.GetFocus().(*tview.InputField)
+	if !ok {
+		t.Fatalf("no edit input was shown, focus on %T", statefunc.App.GetFocus())
+	}
+	if got := input.GetText(); got != "123456" {
+		t.Errorf("the edit starts from %q, want the stored 123456", got)
+	}
+	input.SetText("654321")
+	input.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), setFocus)
+
+	var stored string
+	if err := db.Raw("SELECT a FROM cards").Scan(&stored).Error; err != nil || stored != "654321" {
+		t.Errorf("the table holds %q, want the unformatted 654321: %v", stored, err)
+	}
+	if got := b.TableView.GetCell(first, 0).Text; got != "****21" {
+		t.Errorf("the edited cell shows %q, want ****21", got)
+	}
+}
+
+// Browse edits are never deferred, so leaving a browse has no pending edits to confirm
+func TestEditIsSavedBeforeTheBrowseIsLeft(t *testing.T) {
+	L, db := newTestState(t)
+	b := newTestBrowse(t, L, db, "names", false, "a", "b")
+	b.Fields[0].IsEditable = true
+	showTestBrowse(L, b)
+	b.TableView.Select(b.headerRows()+1, 0)
+
+	editSelectedCell(t, b, "B")
+	var saved string
+	if err := db.Raw("SELECT a FROM names WHERE id = 2").Scan(&saved).Error; err != nil || saved != "B" {
+		t.Fatalf("row 2 holds %q right after the edit, want B: %v", saved, err)
+	}
+
+	pressKey(b, tcell.KeyEscape, 0)
+	if n := statefunc.Pages.GetPageCount(); n != 0 {
+		t.Errorf("leaving the browse added %d pages, want no confirmation", n)
+	}
+	if err := db.Raw("SELECT a FROM names WHERE id = 2").Scan(&saved).Error; err != nil || saved != "B" {
+		t.Errorf("row 2 holds %q after leaving the browse, want B: %v", saved, err)
+	}
+}
+
+func TestPressButtonRunsTheButtonFunction(t *testing.T) {
+	L, db := newTestState(t)
+	b := newTestBrowse(t, L, db, "items", false, "x")
+	if err := lua.DoString(L, `pressed = nil; function onGo(t) pressed = t ~= nil end`); err != nil {
+		t.Fatal(err)
+	}
+	b.addButton(L, "Go", "onGo")
+
+	press := func(caption string) bool {
+		L.PushUserData(b)
+		L.PushString(caption)
+		PressButton(L)
+		defer L.SetTop(0)
+		return L.ToBoolean(-1)
+	}
+	if !press("Go") {
+		t.Error("PressButton returned false for an existing button")
+	}
+	L.Global("pressed")
+	if !L.ToBoolean(-1) {
+		t.Error("the button function did not run with the table")
+	}
+	L.SetTop(0)
+	if press("Missing") {
+		t.Error("PressButton returned true for an unknown caption")
+	}
+}
+
+func TestClosingALookupFocusesTheBrowseItWasOpenedFrom(t *testing.T) {
+	L, db := newTestState(t)
+	master := newTestBrowse(t, L, db, "orders", false, "o1")
+	detail := newTestBrowse(t, L, db, "lines", false, "l1", "l2")
+	lookup := newTestBrowse(t, L, db, "codes", true, "c1")
+	showTestBrowse(L, master)
+	showTestBrowse(L, detail)
+	// The first browse of the screen gets the focus when the screen is set as the root
+	statefunc.RunFlexLevel0.AddItem(master.TableView, 0, 1, true).AddItem(detail.TableView, 0, 1, false)
+	statefunc.App.SetRoot(statefunc.RunFlexLevel0, true)
+	statefunc.App.SetFocus(detail.TableView)
+	row := detail.headerRows() + 1
+	detail.TableView.Select(row, 0)
+
+	detail.openLookup(L, TBrowseField{Name: "a", LookupBrowse: lookup})
+	pressKey(lookup, tcell.KeyEscape, 0)
+
+	if statefunc.App.GetFocus() != detail.TableView {
+		t.Error("the focus did not return to the browse the lookup was opened from")
+	}
+	if got, _ := detail.TableView.GetSelection(); got != row {
+		t.Errorf("the browse stands on row %d, want %d", got, row)
+	}
+}
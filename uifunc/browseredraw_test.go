@@ -0,0 +1,62 @@
+package uifunc
+
+import (
+	"gotulua/statefunc"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// countDraws counts the screens the application draws from now on
+func countDraws() *atomic.Int64 {
+	var draws atomic.Int64
+	statefunc.App.SetAfterDrawFunc(func(tcell.Screen) { draws.Add(1) })
+	return &draws
+}
+
+// reloadOnUI reloads the browse in one update of the event loop, drawn once afterwards as
+// the handler of a key press is
+func reloadOnUI(b *TBrowse) {
+	done := make(chan struct{})
+	statefunc.App.QueueUpdateDraw(func() { b.refreshBrowse(true) })
+	statefunc.App.QueueUpdate(func() { close(done) })
+	<-done
+}
+
+// Filling the cells of a browse does not draw the screen, SetCell only changes the table.
+// The whole reload is drawn once, so batching the cell updates has no draws to save.
+func TestReloadingTheBrowseDrawsOnce(t *testing.T) {
+	L, db := newTestState(t)
+	b := newTestBrowse(t, L, db, "many", false, rowValues(browseLoadBatch)...)
+	runTestApp(t)
+	onUI(func() { showTestBrowse(L, b) })
+	waitForLoad(t, b)
+
+	draws := countDraws()
+	onUI(func() { b.refreshBrowse(true) })
+	if n := draws.Load(); n != 0 {
+		t.Errorf("filling %d rows drew the screen %d times, want none", browseLoadBatch, n)
+	}
+	reloadOnUI(b)
+	if n := draws.Load(); n != 1 {
+		t.Errorf("reloading %d rows drew the screen %d times, want once", browseLoadBatch, n)
+	}
+}
+
+// BenchmarkReloadDraws reports the screens drawn per reload of a browse of a full batch of
+// rows, which stays 1 however many cells are set.
+func BenchmarkReloadDraws(b *testing.B) {
+	L, db := newTestState(b)
+	browse := newTestBrowse(b, L, db, "many", false, rowValues(browseLoadBatch)...)
+	runTestApp(b)
+	onUI(func() { showTestBrowse(L, browse) })
+	waitForLoad(b, browse)
+
+	draws := countDraws()
+	b.ResetTimer()
+	for range b.N {
+		reloadOnUI(browse)
+	}
+	b.ReportMetric(float64(draws.Load())/float64(b.N), "draws/op")
+}
@@ -0,0 +1,118 @@
+package uifunc
+
+import (
+	"gotulua/errorhandlefunc"
+	"gotulua/i18nfunc"
+	"strings"
+
+	"github.com/Shopify/go-lua"
+)
+
+// currentRole is the role of the user running the script, "" when no role is set
+var currentRole string
+
+// SetRole sets the role used to decide which browse fields are shown and editable.
+// Browses shown afterwards use the new role.
+func SetRole(name string) {
+	currentRole = strings.TrimSpace(name)
+}
+
+func GetRole() string {
+	return currentRole
+}
+
+// parseRoles splits a comma separated list of roles
+func parseRoles(s string) []string {
+	var roles []string
+	for _, r := range strings.Split(s, ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			roles = append(roles, r)
+		}
+	}
+	return roles
+}
+
+// roleAllowed reports whether the current role is in the list. An empty list allows every role.
+func roleAllowed(roles []string) bool {
+	if len(roles) == 0 {
+		return true
+	}
+	for _, r := range roles {
+		if strings.EqualFold(r, currentRole) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsVisible reports whether the field is shown for the current role
+func (f TBrowseField) IsVisible() bool {
+	return roleAllowed(f.ViewRoles)
+}
+
+// CanEdit reports whether the field is editable and the current role may edit it
+func (f TBrowseField) CanEdit() bool {
+	return f.IsEditable && roleAllowed(f.EditRoles)
+}
+
+// visibleFields returns the fields the current role may see and the script did not hide, in display order
+func (b *TBrowse) visibleFields() []TBrowseField {
+	var fields []TBrowseField
+	for _, f := range b.Fields {
+		if f.IsVisible() && !f.Hidden {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// SetFieldRoles limits the field of the browse to the given roles.
+// Lua: browse:SetFieldRoles(field, viewRoles, [editRoles]), roles are comma separated,
+// an empty list allows every role.
+func SetFieldRoles(L *lua.State) int {
+	if L.Top() < 3 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "SetFieldRoles",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	browse, ok := L.ToUserData(1).(*TBrowse)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.first_argument_not_browse", nil), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	fieldName, ok := L.ToString(2)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "field",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	viewRoles, ok := L.ToString(3)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "viewRoles",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	editRoles := ""
+	if L.Top() >= 4 {
+		editRoles, ok = L.ToString(4)
+		if !ok {
+			errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+				"Name": "editRoles",
+			}), errorhandlefunc.ErrorTypeScript, true)
+			return 0
+		}
+	}
+	f := browse.findFieldByName(fieldName)
+	if f == nil {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.field_not_found", map[string]interface{}{
+			"Name": fieldName,
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	f.ViewRoles = parseRoles(viewRoles)
+	f.EditRoles = parseRoles(editRoles)
+	return 1
+}
@@ -0,0 +1,42 @@
+package uifunc
+
+import (
+	"gotulua/gormfunc"
+	"slices"
+	"testing"
+)
+
+func TestRoleChangesTheShownAndEditableFields(t *testing.T) {
+	L, db := newTestState(t)
+	if _, err := gormfunc.Exec(db, "CREATE TABLE staff (id INTEGER PRIMARY KEY, name TEXT, salary REAL)"); err != nil {
+		t.Fatal(err)
+	}
+	b := &TBrowse{Table: gormfunc.OpenTable(db, "staff"), Filters: map[string]string{}, NewRowNum: -1}
+	b.addField(L, "n::name;c::Name;e::true;eroles::editor|n::salary;c::Salary;e::true;roles::admin")
+	t.Cleanup(func() { SetRole("") })
+
+	shown := func() (captions []string, editable []string) {
+		showTestBrowse(L, b)
+		for _, f := range b.shownFields {
+			captions = append(captions, f.Caption)
+			if f.CanEdit() {
+				editable = append(editable, f.Caption)
+			}
+		}
+		return
+	}
+
+	SetRole("admin")
+	captions, editable := shown()
+	if !slices.Equal(captions, []string{"Name", "Salary"}) || !slices.Equal(editable, []string{"Salary"}) {
+		t.Errorf("the admin sees %q and edits %q, want Name, Salary and edits Salary", captions, editable)
+	}
+	SetRole("editor")
+	captions, editable = shown()
+	if !slices.Equal(captions, []string{"Name"}) || !slices.Equal(editable, []string{"Name"}) {
+		t.Errorf("the editor sees %q and edits %q, want and edit only Name", captions, editable)
+	}
+	if n := b.TableView.GetColumnCount(); n != 1 {
+		t.Errorf("the browse of the editor has %d columns, want 1", n)
+	}
+}
@@ -0,0 +1,80 @@
+package uifunc
+
+import (
+	"gotulua/errorhandlefunc"
+	"gotulua/i18nfunc"
+
+	"github.com/Shopify/go-lua"
+)
+
+// computeRowTag runs the row tag function for the current row of the table
+// and keeps the result by the primary key of the row
+func (b *TBrowse) computeRowTag(L *lua.State) {
+	if b.rowTagFunc == "" {
+		return
+	}
+	id := b.getRowId()
+	if id == 0 {
+		return
+	}
+	if b.rowTags == nil {
+		b.rowTags = make(map[int64]interface{})
+	}
+	b.rowTags[id] = b.runFieldFunction(L, b.rowTagFunc)
+}
+
+// rowTag returns the tag of the current row, nil if it has none
+func (b *TBrowse) rowTag() interface{} {
+	id := b.getRowId()
+	if id == 0 {
+		return nil
+	}
+	return b.rowTags[id]
+}
+
+// SetRowTag sets the Lua function called with the table for every row shown in the browse.
+// Its result is kept with the row as a tag that is not shown, read it with GetRowTag.
+func SetRowTag(L *lua.State) int {
+	if L.Top() < 2 {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.not_enough_args_lua", map[string]interface{}{
+			"Name": "SetRowTag",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	browse, ok := L.ToUserData(1).(*TBrowse)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.first_argument_not_browse", nil), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	funcName, ok := L.ToString(2)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.arg_not_string", map[string]interface{}{
+			"Name": "funcName",
+		}), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	browse.rowTagFunc = funcName
+	browse.rowTags = nil
+	return 0
+}
+
+// GetRowTag returns the tag of the selected row of the browse, nil if it has none.
+// Tags can be strings, numbers or booleans.
+func GetRowTag(L *lua.State) int {
+	browse, ok := L.ToUserData(1).(*TBrowse)
+	if !ok {
+		errorhandlefunc.ThrowError(i18nfunc.T("error.first_argument_not_browse", nil), errorhandlefunc.ErrorTypeScript, true)
+		return 0
+	}
+	switch v := browse.rowTag().(type) {
+	case string:
+		L.PushString(v)
+	case float64:
+		L.PushNumber(v)
+	case bool:
+		L.PushBoolean(v)
+	default:
+		L.PushNil()
+	}
+	return 1
+}
@@ -0,0 +1,43 @@
+package uifunc
+
+import (
+	"fmt"
+	"gotulua/gormfunc"
+	"testing"
+
+	"github.com/Shopify/go-lua"
+)
+
+func TestRowTagsAreKeptPerRow(t *testing.T) {
+	L, db := newTestState(t)
+	// The tag function reads the fields of the row
+	lua.NewMetaTable(L, "TableMT")
+	L.PushGoFunction(func(L *lua.State) int {
+		w := L.ToUserData(1).(*gormfunc.TableWrapper)
+		key, _ := L.ToString(2)
+		L.PushString(fmt.Sprint(w.Table.GetField(key, "")))
+		return 1
+	})
+	L.SetField(-2, "__index")
+	L.Pop(1)
+	if err := lua.DoString(L, `function tag(t) return "tag " .. t.a end`); err != nil {
+		t.Fatal(err)
+	}
+	b := newTestBrowse(t, L, db, "names", false, "a", "b", "c")
+	b.rowTagFunc = "tag"
+	showTestBrowse(L, b)
+
+	if len(b.rowTags) != 3 {
+		t.Errorf("%d row tags were computed, want one per row", len(b.rowTags))
+	}
+	for i, want := range []string{"tag a", "tag b", "tag c"} {
+		b.TableView.Select(b.headerRows()+i, 0)
+		L.PushGoFunction(GetRowTag)
+		L.PushUserData(b)
+		L.Call(1, 1)
+		if got, _ := L.ToString(-1); got != want {
+			t.Errorf("GetRowTag on row %d returned %q, want %q", i, got, want)
+		}
+		L.Pop(1)
+	}
+}
@@ -6,20 +6,17 @@ import (
 	"github.com/rivo/tview"
 )
 
+// Confirm asks a question with OK and Cancel buttons. Like the other dialogs it goes on
+// the dialog stack, so closing it shows again the dialog it was opened from.
 func Confirm(text string, callback func(bool)) {
 	dialog := tview.NewModal()
 	dialog.SetText(text)
 	dialog.AddButtons([]string{"OK", "Cancel"})
 	dialog.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
-		statefunc.ShowPreviousVisual()
+		statefunc.PopDialog()
 		callback(buttonIndex == 0)
 	})
-	statefunc.PushVisual(statefunc.RunFlexLevel0)
-	statefunc.RunFlexLevelDialog.Clear()
-	statefunc.RunFlexLevelDialog.AddItem(dialog, 0, 1, false)
-	statefunc.App.SetRoot(statefunc.RunFlexLevelDialog, true)
-	statefunc.App.SetFocus(dialog)
-	statefunc.App.ForceDraw() // Ensure the dialog is drawn immediately
+	statefunc.PushDialog(tview.NewFlex().AddItem(dialog, 0, 1, false), dialog, statefunc.RunFlexLevel0)
 }
 
 func Message(text string) {
@@ -27,16 +24,30 @@ func Message(text string) {
 	dialog.SetText(text)
 	dialog.AddButtons([]string{"OK"})
 	dialog.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
-		if statefunc.IsNowOnInitialTop() && statefunc.IsRunAsScript() {
+		if statefunc.DialogCount() <= 1 && statefunc.IsNowOnInitialTop() && statefunc.IsRunAsScript() {
 			statefunc.ShowMainVisual()
 			return
 		}
-		statefunc.ShowPreviousVisual()
+		statefunc.PopDialog()
 	})
-	statefunc.PushVisual(statefunc.RunFlexLevel0)
-	statefunc.RunFlexLevelDialog.Clear()
-	statefunc.RunFlexLevelDialog.AddItem(dialog, 1, 0, false)
-	statefunc.App.SetRoot(statefunc.RunFlexLevelDialog, true)
-	statefunc.App.SetFocus(dialog)
-	statefunc.App.ForceDraw() // Ensure the dialog is drawn immediately
+	statefunc.PushDialog(tview.NewFlex().AddItem(dialog, 1, 0, false), dialog, statefunc.RunFlexLevel0)
+}
+
+// PickList shows a list of items to choose from. The callback gets the index
+// of the chosen item or -1 if the list was closed with Escape.
+func PickList(title string, items []string, callback func(int)) {
+	list := tview.NewList().ShowSecondaryText(false)
+	for _, item := range items {
+		list.AddItem(item, "", 0, nil)
+	}
+	list.SetSelectedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
+		statefunc.PopDialog()
+		callback(index)
+	})
+	list.SetDoneFunc(func() {
+		statefunc.PopDialog()
+		callback(-1)
+	})
+	list.SetBorder(true).SetTitle(" " + title + " ")
+	statefunc.PushDialog(tview.NewFlex().AddItem(list, 0, 1, false), list, statefunc.RunFlexLevel0)
 }
@@ -2,6 +2,7 @@ package uifunc
 
 import (
 	"fmt"
+	"gotulua/keymapfunc"
 	"gotulua/statefunc"
 	"strconv"
 
@@ -21,6 +22,12 @@ var info *tview.TextView = nil
 var btnInfo *tview.TextView = nil
 var currRegion string = ""
 var currBtnRegion string = ""
+var appTitle string = ""
+var appStatus string = ""
+var titleBar *tview.TextView = nil
+var statusLine *tview.TextView = nil
+var infoBarVisible = true   // Show the info bar with the widget titles
+var buttonBarVisible = true // Show the button bar of browses with buttons
 
 func AddWidget(widget tview.Primitive, title string, browse *TBrowse) {
 	w := Widget{
@@ -58,22 +65,23 @@ func showWidget(title string) {
 func showCurrentWidget(w int) {
 	if len(Widgets) > 0 {
 		statefunc.RunFlexLevel0.Clear()
+		statefunc.RunFlexLevel0.AddItem(getTitleBar(), barSize(appTitle), 0, false)
 		switch Widgets[w].Widget.(type) {
 		case *tview.Table:
-			if Widgets[w].Browse.Buttons != nil {
+			if Widgets[w].Browse.Buttons != nil && buttonBarVisible {
 				flex := tview.NewFlex().SetDirection(tview.FlexRow)
 				flex.AddItem(Widgets[w].Widget, 0, 1, true)
 				buttFlex := tview.NewFlex().SetDirection(tview.FlexColumn)
 				buttFlex.AddItem(setBrowseButtons(Widgets[w].Browse), 0, 1, true)
 				flex.AddItem(buttFlex, 1, 0, true).AddItem(tview.NewFlex(), 1, 0, false)
-				flex.SetTitle(" Ctrl+N/Ctrl+P - Next/Previous, F7 - Set Filter, Enter - Edit ")
+				flex.SetTitle(" Ctrl+N/Ctrl+P - Next/Previous, F7 - Set Filter, Ctrl+E - Export, Alt+Up/Alt+Down - Insert Row, Enter - Edit ")
 				flex.SetBorder(true)
 				flex.SetBorderPadding(1, 1, 1, 1)
 				statefunc.RunFlexLevel0.AddItem(flex, 0, 1, true)
 			} else {
 				flex := tview.NewFlex().SetDirection(tview.FlexRow)
 				flex.AddItem(Widgets[w].Widget, 0, 1, true)
-				flex.SetTitle(" Ctrl+N/Ctrl+P - Next/Previous, F7 - Set Filter, Enter - Edit ")
+				flex.SetTitle(" Ctrl+N/Ctrl+P - Next/Previous, F7 - Set Filter, Ctrl+E - Export, Alt+Up/Alt+Down - Insert Row, Enter - Edit ")
 				flex.SetBorder(true)
 				flex.SetBorderPadding(1, 1, 1, 1)
 				statefunc.RunFlexLevel0.AddItem(flex, 0, 1, true)
@@ -81,28 +89,39 @@ func showCurrentWidget(w int) {
 		default:
 			statefunc.RunFlexLevel0.AddItem(Widgets[w].Widget, 0, 1, true)
 		}
-		currRegion = Widgets[w].Region                     // Update the current region
-		setInfo(Widgets[w])                                // Set the info TextView with the current widget
-		statefunc.RunFlexLevel0.AddItem(info, 1, 0, false) // Add the info TextView to the layout
-		if len(Widgets) > 1 {
-			statefunc.RunFlexLevel0.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-				switch event.Key() {
-				case tcell.KeyCtrlN:
-					if w+1 < len(Widgets) {
-						showCurrentWidget(w + 1) // Show the next widget
-					} else {
-						showCurrentWidget(0) // Wrap around to the first widget
-					}
-				case tcell.KeyCtrlP:
-					if w-1 >= 0 {
-						showCurrentWidget(w - 1) // Show the previous widget
-					} else {
-						showCurrentWidget(len(Widgets) - 1) // Wrap around to the last widget
-					}
-				}
-				return event
-			})
+		currRegion = Widgets[w].Region // Update the current region
+		setInfo(Widgets[w])            // Set the info TextView with the current widget
+		if infoBarVisible {
+			statefunc.RunFlexLevel0.AddItem(info, 1, 0, false) // Add the info TextView to the layout
 		}
+		statefunc.RunFlexLevel0.AddItem(getStatusLine(), barSize(appStatus), 0, false)
+		statefunc.RunFlexLevel0.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			if keymapfunc.Matches(keymapfunc.AppToggleBars, event) {
+				// Hide both bars if any is shown, show both otherwise
+				show := !infoBarVisible && !buttonBarVisible
+				infoBarVisible, buttonBarVisible = show, show
+				showCurrentWidget(w)
+				return nil
+			}
+			if len(Widgets) < 2 {
+				return event
+			}
+			switch event.Key() {
+			case tcell.KeyCtrlN:
+				if w+1 < len(Widgets) {
+					showCurrentWidget(w + 1) // Show the next widget
+				} else {
+					showCurrentWidget(0) // Wrap around to the first widget
+				}
+			case tcell.KeyCtrlP:
+				if w-1 >= 0 {
+					showCurrentWidget(w - 1) // Show the previous widget
+				} else {
+					showCurrentWidget(len(Widgets) - 1) // Wrap around to the last widget
+				}
+			}
+			return event
+		})
 		statefunc.App.SetRoot(statefunc.RunFlexLevel0, true) // Set the root to the Flex layout
 		statefunc.App.SetFocus(Widgets[w].Widget)            // Set focus to the widget
 	}
@@ -134,10 +153,98 @@ func setInfo(w Widget) {
 	info.Clear() // Clear the previous content
 	for _, wd := range Widgets {
 		fmt.Fprintf(info, `["%s"][white:black]%s[white][""]  `, wd.Region, wd.WidgetTitle)
+		if wd.Browse != nil && wd.Browse.isLoading() {
+			fmt.Fprintf(info, `[yellow]%s[white]  `, wd.Browse.loading)
+		}
 	}
 	info.Highlight(w.Region).ScrollToHighlight() // Highlight the current widget
 }
 
+// refreshInfo redraws the info bar for the current widget
+func refreshInfo() {
+	if info == nil {
+		return
+	}
+	for _, w := range Widgets {
+		if w.Region == currRegion {
+			setInfo(w)
+			return
+		}
+	}
+}
+
+// SetInfoBarVisible shows or hides the info bar under the widgets
+func SetInfoBarVisible(show bool) {
+	infoBarVisible = show
+	showRegion(currRegion)
+}
+
+// SetButtonBarVisible shows or hides the button bar of browses with buttons
+func SetButtonBarVisible(show bool) {
+	buttonBarVisible = show
+	showRegion(currRegion)
+}
+
+// showRegion shows the widget of the region again, so the layout follows the bar settings
+func showRegion(region string) {
+	for i, w := range Widgets {
+		if w.Region == region {
+			showCurrentWidget(i)
+			return
+		}
+	}
+}
+
+// barSize returns the height of a title or status bar, which is hidden while it has no text
+func barSize(text string) int {
+	if text == "" {
+		return 0
+	}
+	return 1
+}
+
+func getTitleBar() *tview.TextView {
+	if titleBar == nil {
+		titleBar = tview.NewTextView().
+			SetDynamicColors(true).
+			SetWrap(false).
+			SetTextAlign(tview.AlignCenter)
+	}
+	return titleBar
+}
+
+func getStatusLine() *tview.TextView {
+	if statusLine == nil {
+		statusLine = tview.NewTextView().
+			SetDynamicColors(true).
+			SetWrap(false)
+	}
+	return statusLine
+}
+
+// SetAppTitle sets the title shown above the widgets of the running script
+func SetAppTitle(text string) {
+	appTitle = text
+	getTitleBar().SetText(text)
+	statefunc.RunFlexLevel0.ResizeItem(titleBar, barSize(text), 0)
+}
+
+func GetAppTitle() string {
+	return appTitle
+}
+
+// SetStatus sets the status line shown below the widgets of the running script.
+// It stays until it is changed, also when another widget is shown.
+func SetStatus(text string) {
+	appStatus = text
+	getStatusLine().SetText(text)
+	statefunc.RunFlexLevel0.ResizeItem(statusLine, barSize(text), 0)
+}
+
+func GetStatus() string {
+	return appStatus
+}
+
 func createBrowseButtons(b *TBrowse) *tview.TextView {
 	// The bottom row has some info on where we are.
 	btnInfo := tview.NewTextView().
@@ -0,0 +1,99 @@
+package uifunc
+
+import (
+	"gotulua/statefunc"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// screenLines draws the run layout on a simulation screen and returns its lines
+func screenLines(t *testing.T) []string {
+	t.Helper()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer screen.Fini()
+	screen.SetSize(40, 10)
+	statefunc.RunFlexLevel0.SetRect(0, 0, 40, 10)
+	statefunc.RunFlexLevel0.Draw(screen)
+	screen.Show()
+	cells, width, height := screen.GetContents()
+	lines := make([]string, height)
+	for y := range lines {
+		var line strings.Builder
+		for x := 0; x < width; x++ {
+			line.WriteString(string(cells[y*width+x].Runes))
+		}
+		lines[y] = strings.TrimSpace(line.String())
+	}
+	return lines
+}
+
+func TestAppTitleAndStatusAreRendered(t *testing.T) {
+	newTestState(t)
+	t.Cleanup(func() {
+		SetAppTitle("")
+		SetStatus("")
+		ClearWidgets()
+	})
+	ClearWidgets()
+	AddWidget(tview.NewTextView().SetText("body"), "Main", nil)
+
+	SetAppTitle("Inventory")
+	SetStatus("3 items")
+	lines := screenLines(t)
+	if lines[0] != "Inventory" {
+		t.Errorf("the first line is %q, want the title", lines[0])
+	}
+	if last := lines[len(lines)-1]; last != "3 items" {
+		t.Errorf("the last line is %q, want the status", last)
+	}
+
+	SetStatus("")
+	if last := screenLines(t)[len(lines)-1]; last == "3 items" {
+		t.Error("the cleared status is still shown")
+	}
+}
+
+func TestHidingTheInfoBarRemovesItFromTheLayout(t *testing.T) {
+	newTestState(t)
+	t.Cleanup(func() {
+		SetInfoBarVisible(true)
+		ClearWidgets()
+	})
+	ClearWidgets()
+	AddWidget(tview.NewTextView().SetText("body"), "Main", nil)
+	AddWidget(tview.NewTextView().SetText("other"), "Other", nil)
+	hasInfoBar := func() bool {
+		for _, line := range screenLines(t) {
+			if strings.Contains(line, "Main") && strings.Contains(line, "Other") {
+				return true
+			}
+		}
+		return false
+	}
+	if !hasInfoBar() {
+		t.Fatal("the info bar is not shown")
+	}
+	items := statefunc.RunFlexLevel0.GetItemCount()
+
+	SetInfoBarVisible(false)
+	if hasInfoBar() {
+		t.Error("the hidden info bar is still shown")
+	}
+	if n := statefunc.RunFlexLevel0.GetItemCount(); n != items-1 {
+		t.Errorf("the layout has %d items, want %d without the info bar", n, items-1)
+	}
+
+	SetInfoBarVisible(true)
+	if !hasInfoBar() {
+		t.Error("the info bar was not shown again")
+	}
+	if n := statefunc.RunFlexLevel0.GetItemCount(); n != items {
+		t.Errorf("the layout has %d items, want %d with the info bar", n, items)
+	}
+}
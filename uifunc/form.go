@@ -6,6 +6,7 @@ import (
 	"gotulua/inputfunc"
 	"gotulua/statefunc"
 	"gotulua/timefunc"
+	"strings"
 
 	"github.com/Shopify/go-lua"
 	"github.com/gdamore/tcell/v2"
@@ -79,7 +80,8 @@ func FormHide(L *lua.State) int {
 // addInput adds an input field to the form with the given title, type, and callback function.
 //
 // The callback function is called when the input field is changed.
-func (form *Form) addInput(title string, typeName string, callback string) {
+// Required inputs get a red asterisk after the title.
+func (form *Form) addInput(title string, typeName string, callback string, required bool) {
 	if InputFields == nil {
 		InputFields = []InputField{}
 	}
@@ -88,6 +90,7 @@ func (form *Form) addInput(title string, typeName string, callback string) {
 		Caption:  title,
 		Type:     typeName,
 		callback: callback,
+		Required: required,
 	})
 	var af func(text string, ch rune) bool = nil // Acceptance function for the input field
 	var defVal string = ""
@@ -104,28 +107,31 @@ func (form *Form) addInput(title string, typeName string, callback string) {
 	case "D", "T", "DT":
 		needPH = true // Date and time inputs need a placeholder
 	}
-	form.Form.AddInputField(title, defVal, 0, af, nil)
+	label := title
+	if required {
+		label += "[red]*[-]"
+	}
+	form.Form.AddInputField(label, defVal, 0, af, nil)
 	var input *tview.InputField = CurrForm.Form.GetFormItem(CurrForm.Form.GetFormItemCount() - 1).(*tview.InputField)
 	input.SetDoneFunc(onDone)
 	input.SetMouseCapture(mouseCapture)
 	input.SetInputCapture(inputCapture)
+	inputfunc.ApplyInputTheme(input)
+	inputfunc.ApplyFormTheme(form.Form)
 	//input.SetChangedFunc()                                                                            // Set the done function for the InputField
 	if needPH {
 		switch typeName {
 		case "D":
 			ph := timefunc.TemplateToPlaceholder(timefunc.DateFormat)
 			input.SetPlaceholder(ph)
-			input.SetPlaceholderTextColor(tcell.ColorYellow)
 			inputfunc.SetDateInput(input, ph)
 		case "T":
 			ph := timefunc.TemplateToPlaceholder(timefunc.TimeFormat)
 			input.SetPlaceholder(ph)
-			input.SetPlaceholderTextColor(tcell.ColorYellow)
 			inputfunc.SetDateInput(input, ph)
 		case "DT":
 			ph := timefunc.TemplateToPlaceholder(timefunc.DateTimeFormat)
 			input.SetPlaceholder(ph)
-			input.SetPlaceholderTextColor(tcell.ColorYellow)
 			inputfunc.SetDateInput(input, ph)
 		}
 	}
@@ -169,20 +175,34 @@ func FormAddButton(L *lua.State) int {
 	}
 	L.Pop(1)
 
-	form.addButton(buttonText, buttonFuncName) // Add the button to the Form
-	return 1                                   // Return the number of results
+	checkRequired := true
+	if L.Top() >= 4 {
+		checkRequired = L.ToBoolean(4) // false for buttons like Cancel that run with empty required inputs
+	}
+
+	form.addButton(buttonText, buttonFuncName, checkRequired) // Add the button to the Form
+	return 1                                                  // Return the number of results
 }
 
 // addButton adds a button to the form with the given text and callback function.
 //
 // The callback function is called when the button is clicked.
-func (form *Form) addButton(buttonText string, callback string) {
+// If checkRequired is set, the callback is not called while required inputs are empty.
+func (form *Form) addButton(buttonText string, callback string, checkRequired bool) {
 	form.Form.AddButton(buttonText, func() {
 		defer func() {
 			if r := recover(); r != nil {
 				errorhandlefunc.ThrowError(r.(string), errorhandlefunc.ErrorTypeScript, true)
 			}
 		}()
+		if checkRequired {
+			if missing := missingRequired(form.Form); len(missing) > 0 {
+				errorhandlefunc.ThrowError(i18nfunc.T("error.form_required_empty", map[string]interface{}{
+					"Fields": strings.Join(missing, ", "),
+				}), errorhandlefunc.ErrorTypeData, false)
+				return
+			}
+		}
 		statefunc.L.Global(callback) // Get the function from the Lua global state
 		statefunc.L.Call(0, 0)
 	})
@@ -0,0 +1,54 @@
+package uifunc
+
+import (
+	"gotulua/errorhandlefunc"
+	"strings"
+	"testing"
+
+	"github.com/Shopify/go-lua"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func TestRequiredInputBlocksTheButton(t *testing.T) {
+	L, _ := newTestState(t)
+	if err := lua.DoString(L, `submitted = 0 function submit() submitted = submitted + 1 end`); err != nil {
+		t.Fatal(err)
+	}
+	var shown []string
+	errorhandlefunc.SetErrorSink(func(msg string) { shown = append(shown, msg) })
+	t.Cleanup(func() { errorhandlefunc.SetErrorSink(nil) })
+	InputFields = nil
+	t.Cleanup(func() { InputFields = nil })
+	CurrForm = &Form{Title: "Person", Form: tview.NewForm()}
+	CurrForm.addInput("Note", "S", "", false)
+	CurrForm.addInput("Name", "S", "", true)
+	CurrForm.addButton("OK", "submit", true)
+	submitted := func() int {
+		L.Global("submitted")
+		defer L.Pop(1)
+		n, _ := L.ToInteger(-1)
+		return n
+	}
+	press := func() {
+		CurrForm.Form.GetButton(0).InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), func(tview.Primitive) {})
+	}
+
+	name := CurrForm.Form.GetFormItem(1).(*tview.InputField)
+	if !strings.Contains(name.GetLabel(), "*") {
+		t.Errorf("the required input is labelled %q, want it marked", name.GetLabel())
+	}
+	press()
+	if submitted() != 0 {
+		t.Error("the button ran with the required input empty")
+	}
+	if len(shown) != 1 || !strings.Contains(shown[0], "Name") || strings.Contains(shown[0], "Note") {
+		t.Errorf("the errors shown are %q, want one naming only Name", shown)
+	}
+
+	name.SetText("Anna")
+	press()
+	if submitted() != 1 {
+		t.Error("the button did not run with the required input filled")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"gotulua/statefunc"
 	"gotulua/timefunc"
 	"strconv"
+	"strings"
 
 	"github.com/Shopify/go-lua"
 	"github.com/gdamore/tcell/v2"
@@ -19,6 +20,7 @@ type InputField struct {
 	callback   string
 	Type       string
 	Value      interface{}
+	Required   bool // The form buttons refuse to run while the input is empty
 }
 
 // type Widget struct {
@@ -63,7 +65,8 @@ func AddInputField(L *lua.State) int {
 		errorhandlefunc.ThrowError(err.Error(), errorhandlefunc.ErrorTypeScript, true)
 		return 0
 	}
-	form.addInput(title, typeName, callback)
+	required := L.Top() >= 5 && L.ToBoolean(5)
+	form.addInput(title, typeName, callback, required)
 	return 1 // Return the number of results
 }
 
@@ -106,6 +109,23 @@ func isCurrentInputValid(form *tview.Form, fi int) bool {
 	return true
 }
 
+// missingRequired returns the captions of the required inputs of the form that are empty
+// and moves the focus to the first of them.
+func missingRequired(form *tview.Form) []string {
+	var missing []string
+	for i := 0; i < form.GetFormItemCount() && i < len(InputFields); i++ {
+		inp, ok := form.GetFormItem(i).(*tview.InputField)
+		if !ok || !InputFields[i].Required || strings.TrimSpace(inp.GetText()) != "" {
+			continue
+		}
+		if missing == nil {
+			form.SetFocus(i)
+		}
+		missing = append(missing, InputFields[i].Caption)
+	}
+	return missing
+}
+
 func onDone(key tcell.Key) {
 	var inp *tview.InputField
 	fi, _ := CurrForm.Form.GetFocusedItemIndex()